@@ -3,17 +3,17 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
+	// La imagen final no incluye la base de zonas horarias que usan las rachas
+	_ "time/tzdata"
 
 	_ "explorax-backend/docs"
 	"explorax-backend/internal/database"
 	"explorax-backend/internal/handlers"
-	"explorax-backend/internal/middleware"
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/utils"
 
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // @title Explorax Backend API
@@ -30,60 +30,62 @@ func main() {
 		log.Println("No se encontró el archivo .env o hubo un error al cargarlo")
 	}
 
-	// Conectar a MongoDB
-	database.Connect()
-
-	// Configurar Gin Router
-	router := gin.Default()
-	router.Use(cors.Default())
-	// Agregar Swagger UI
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Grupo de endpoints de autenticación
-	auth := router.Group("/auth")
-	{
-		auth.POST("/register", handlers.Register)
-		auth.POST("/login", handlers.Login)
-	}
-
-	// Endpoints protegidos con JWT
-	admin := router.Group("/admin")
-	admin.Use(middleware.JWTAuthMiddleware())
-	{
-		admin.POST("/missions/create", handlers.CreateMission)
-	}
-
-	missions := router.Group("/missions")
-	missions.Use(middleware.JWTAuthMiddleware())
-	{
-		missions.GET("/all", handlers.GetAllMissions)
-		missions.POST("/start", handlers.StartMission)
-		missions.POST("/complete", handlers.CompleteMission)
-		missions.GET("/progress", handlers.GetProgress)
-		missions.GET("/active", handlers.GetActiveMissions)
-		missions.GET("/completed", handlers.GetCompletedMissions)
-		missions.GET("/statistics", handlers.GetStatistics)
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Fatal("JWT_SECRET no está configurada. Revisa tu .env o variables de entorno.")
 	}
 
-	// Endpoints públicos
-	publicMissions := router.Group("/missions")
-	{
-		publicMissions.GET("/leaderboard", handlers.GetLeaderboard)
-		publicMissions.GET("/overview", handlers.GetMissionsOverview)
+	// Elegir el almacenamiento: MongoDB por defecto, o en memoria para desarrollo
+	var store database.Store
+	if os.Getenv("STORAGE_DRIVER") == "memory" {
+		log.Println("Usando almacenamiento en memoria; los datos se perderán al detener el servidor")
+		store = database.NewMemoryStore()
+	} else {
+		database.Connect()
+		mongoStore := database.NewMongoStore(database.Client.Database("explorax"))
+		if err := mongoStore.EnsureIndexes(); err != nil {
+			log.Fatal("No se pudieron crear los índices de MongoDB: ", err)
+		}
+		if err := mongoStore.MigrateDefaultTenant(); err != nil {
+			log.Fatal("No se pudo asignar la organización por defecto: ", err)
+		}
+		store = mongoStore
 	}
 
-	mission := router.Group("/mission")
-	mission.Use(middleware.JWTAuthMiddleware())
-	{
-		mission.GET("/:id", handlers.GetMissionByID)
-	}
+	// Configurar Gin Router con todas las rutas
+	deps := handlers.StoreDeps(store, utils.NewHMACSigner(secret, nil))
+	deps.Levels = levelCurveFromEnv()
+	router := handlers.NewRouter(deps)
 
 	// Iniciar servidor
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
-	router.Use(cors.Default())
 
 	router.Run(":" + port)
 }
+
+// levelCurveFromEnv lee la curva de niveles de LEVEL_BASE_XP y LEVEL_GROWTH;
+// las variables ausentes toman el valor de models.DefaultLevelCurve.
+func levelCurveFromEnv() models.LevelCurve {
+	curve := models.DefaultLevelCurve
+	if v := os.Getenv("LEVEL_BASE_XP"); v != "" {
+		baseXP, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatal("LEVEL_BASE_XP no es un entero válido: ", err)
+		}
+		curve.BaseXP = baseXP
+	}
+	if v := os.Getenv("LEVEL_GROWTH"); v != "" {
+		growth, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Fatal("LEVEL_GROWTH no es un número válido: ", err)
+		}
+		curve.Growth = growth
+	}
+	if !curve.Valid() {
+		log.Fatal("La curva de niveles requiere LEVEL_BASE_XP > 0 y LEVEL_GROWTH >= 1")
+	}
+	return curve
+}
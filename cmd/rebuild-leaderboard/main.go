@@ -0,0 +1,51 @@
+// Command rebuild-leaderboard recalcula la colección leaderboard a partir de
+// las misiones completadas, organización por organización. Sirve para
+// llenarla la primera vez y para corregirla si alguna actualización
+// incremental falló. Conviene ejecutarlo con la API detenida: las misiones
+// completadas mientras corre pueden no quedar contadas.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No se encontró el archivo .env o hubo un error al cargarlo")
+	}
+
+	database.Connect()
+	defer database.Client.Disconnect(context.Background())
+
+	store := database.NewMongoStore(database.Client.Database("explorax"))
+	if err := store.EnsureIndexes(); err != nil {
+		log.Fatal("No se pudieron crear los índices de MongoDB: ", err)
+	}
+	if err := store.MigrateDefaultTenant(); err != nil {
+		log.Fatal("No se pudo asignar la organización por defecto: ", err)
+	}
+
+	tenants, err := store.GetTenants(database.ListQuery[models.Tenant]{})
+	if err != nil {
+		log.Fatal("No se pudieron obtener las organizaciones: ", err)
+	}
+	tenantIDs := []string{models.DefaultTenantID}
+	for _, tenant := range tenants {
+		tenantIDs = append(tenantIDs, tenant.ID)
+	}
+
+	for _, tenantID := range tenantIDs {
+		start := time.Now()
+		if err := store.ForTenant(tenantID).RebuildLeaderboard(); err != nil {
+			log.Fatalf("No se pudo reconstruir el leaderboard de %s: %v", tenantID, err)
+		}
+		log.Printf("Leaderboard de %s reconstruido en %s", tenantID, time.Since(start).Round(time.Millisecond))
+	}
+}
@@ -15,73 +15,92 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Autentica a un usuario y devuelve un token JWT.",
-                "consumes": [
-                    "application/json"
+        "/admin/achievements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Retorna una página de las reglas de logros, incluidas las inactivas, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Inicia sesión de usuario",
+                "summary": "Lista las definiciones de logros",
                 "parameters": [
                     {
-                        "description": "Credenciales de usuario (email y password)",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Token JWT generado exitosamente",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "$ref": "#/definitions/handlers.Page-models_Achievement"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
                             }
                         }
                     },
                     "400": {
-                        "description": "Datos inválidos",
+                        "description": "Parámetros inválidos",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
-                        "description": "Credenciales incorrectas",
+                        "description": "Usuario no autenticado",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno",
+                        "description": "No se pudieron obtener los logros",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
+            },
             "post": {
-                "description": "Permite registrar un nuevo usuario en la plataforma.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea una regla de logro que se evalúa al iniciar o completar misiones. Tipos de regla: missions_completed, missions_started, fast_completion (minutos), streak_days y xp_total.",
                 "consumes": [
                     "application/json"
                 ],
@@ -89,73 +108,73 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Registro de usuario",
+                "summary": "Define un logro nuevo",
                 "parameters": [
                     {
-                        "description": "Datos del usuario",
-                        "name": "user",
+                        "description": "Definición del logro",
+                        "name": "achievement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.RegisterRequest"
+                            "$ref": "#/definitions/handlers.CreateAchievementRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Usuario creado exitosamente",
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Achievement"
                         }
                     },
                     "400": {
                         "description": "Datos inválidos",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe un logro con ese código",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor",
+                        "description": "No se pudo crear el logro",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/mission/{id}": {
-            "get": {
+        "/admin/achievements/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna los detalles de una misión específica por su ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Elimina la definición de un logro. Los usuarios que ya lo obtuvieron lo conservan.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
                 ],
-                "summary": "Obtiene una misión por su ID",
+                "summary": "Elimina un logro",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID de la misión",
+                        "description": "ID del logro",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -163,64 +182,44 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Logro eliminado",
                         "schema": {
-                            "$ref": "#/definitions/models.Mission"
+                            "$ref": "#/definitions/handlers.GenericResponse"
                         }
                     },
                     "400": {
-                        "description": "ID de misión inválido",
+                        "description": "ID de logro inválido",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
-                    "404": {
-                        "description": "Misión no encontrada",
+                    "403": {
+                        "description": "Se requiere rol de administrador",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/missions": {
-            "get": {
-                "description": "Retorna una lista de todas las misiones disponibles en la base de datos",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Missions"
-                ],
-                "summary": "Obtiene todas las misiones",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Logro no encontrado",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Mission"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "No se pudieron obtener las misiones",
+                        "description": "No se pudo eliminar el logro",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Crea una nueva misión con un título y descripción proporcionados",
+                "description": "Modifica solo los campos enviados. Con \"active\": false el logro deja de otorgarse; quienes ya lo tienen lo conservan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -228,93 +227,149 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
                 ],
-                "summary": "Crea una nueva misión",
+                "summary": "Actualiza parcialmente un logro",
                 "parameters": [
                     {
-                        "description": "Detalles de la misión",
-                        "name": "mission",
+                        "type": "string",
+                        "description": "ID del logro",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a modificar",
+                        "name": "achievement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Mission"
+                            "$ref": "#/definitions/handlers.PatchAchievementRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Misión creada exitosamente",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/models.Achievement"
                         }
                     },
                     "400": {
                         "description": "Datos inválidos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Logro no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "No se pudo crear la misión",
+                        "description": "No se pudo actualizar el logro",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/active": {
+        "/admin/missions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todas las misiones con estado \"iniciada\" para un usuario autenticado",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retorna una página de las misiones, incluidas las archivadas, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
+                ],
+                "summary": "Lista todas las misiones para administración",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Obtiene misiones activas de un usuario",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MissionProgress"
+                            "$ref": "#/definitions/handlers.Page-models_Mission"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
                     "401": {
                         "description": "Usuario no autenticado",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor",
+                        "description": "No se pudieron obtener las misiones",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/complete": {
+        "/admin/missions/create": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Actualiza el estado de la misión a \"completada\".",
+                "description": "Crea una nueva misión de la organización del administrador con un título, una descripción y, opcionalmente, una lista ordenada de pasos, un cuestionario y prerrequisitos. Con global en true, que solo admite la organización por defecto, la misión se ofrece en todas las organizaciones.",
                 "consumes": [
                     "application/json"
                 ],
@@ -324,66 +379,60 @@ const docTemplate = `{
                 "tags": [
                     "Missions"
                 ],
-                "summary": "Completa una misión",
+                "summary": "Crea una nueva misión",
                 "parameters": [
                     {
-                        "description": "Mission ID",
+                        "description": "Detalles de la misión",
                         "name": "mission",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/handlers.CreateMissionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Misión creada exitosamente",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.GenericResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Datos inválidos",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Usuario no autenticado",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador o, para una misión global, de la organización por defecto",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "No se pudo crear la misión",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/completed": {
-            "get": {
+        "/admin/missions/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todas las misiones con estado \"completada\" para un usuario autenticado",
+                "description": "Reemplaza el título, la descripción, los pasos, el cuestionario y los prerrequisitos de una misión existente. Si no se envían pasos, cuestionario o prerrequisitos, la misión queda sin ellos. Los prerrequisitos no pueden formar un ciclo.",
                 "consumes": [
                     "application/json"
                 ],
@@ -391,102 +440,339 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
+                ],
+                "summary": "Reemplaza una misión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nuevos datos de la misión",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateMissionRequest"
+                        }
+                    }
                 ],
-                "summary": "Obtiene misiones completadas de un usuario",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MissionProgress"
-                            }
+                            "$ref": "#/definitions/models.Mission"
                         }
                     },
-                    "401": {
-                        "description": "Usuario no autenticado",
+                    "400": {
+                        "description": "Datos inválidos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor",
+                        "description": "No se pudo actualizar la misión",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            }
-        },
-        "/missions/leaderboard": {
-            "get": {
-                "description": "Devuelve un leaderboard con los usuarios ordenados por número de misiones completadas",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Elimina definitivamente una misión que ningún usuario ha iniciado. Si ya tiene progreso registrado se debe archivar en su lugar.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
                 ],
-                "summary": "Obtiene el ranking de usuarios basado en misiones completadas",
-                "responses": {
+                "summary": "Elimina una misión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Misión eliminada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID de misión inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión tiene progreso registrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo eliminar la misión",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifica solo los campos enviados. Con \"archived\" se archiva o restaura la misión; \"steps\", \"quiz\" y \"prerequisites\" reemplazan la lista completa de pasos, el cuestionario y los prerrequisitos, que no pueden formar un ciclo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Actualiza parcialmente una misión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a modificar",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PatchMissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.LeaderboardEntry"
-                            }
+                            "$ref": "#/definitions/models.Mission"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor",
+                        "description": "No se pudo actualizar la misión",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/overview": {
-            "get": {
-                "description": "Devuelve estadísticas sobre las misiones, incluyendo la misión más popular y el tiempo promedio de finalización.",
+        "/admin/missions/{id}/translations/{locale}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Guarda la traducción del título, la descripción y los pasos de la misión a un idioma, reemplazando la anterior. Los textos que no se envían se muestran en el idioma original. No se puede traducir al idioma en que está escrita la misión.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
+                ],
+                "summary": "Agrega o reemplaza la traducción de una misión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma: es, en o pt",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Textos traducidos",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionTranslationRequest"
+                        }
+                    }
                 ],
-                "summary": "Obtiene el resumen de las misiones",
                 "responses": {
                     "200": {
-                        "description": "Resumen de misiones",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Mission"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno al obtener el resumen",
+                        "description": "No se pudo guardar la traducción",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina la traducción de la misión a un idioma; en ese idioma se vuelve a mostrar el texto original.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Elimina la traducción de una misión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idioma: es, en o pt",
+                        "name": "locale",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Mission"
+                        }
+                    },
+                    "400": {
+                        "description": "ID de misión o idioma inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión o traducción no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo eliminar la traducción",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/progress": {
-            "get": {
+        "/admin/paths": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve todas las misiones iniciadas y completadas del usuario.",
+                "description": "Agrupa misiones de la organización, o globales, en capítulos ordenados. Una misión no puede repetirse en la ruta ni aparecer antes que alguno de sus prerrequisitos.",
                 "consumes": [
                     "application/json"
                 ],
@@ -494,48 +780,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
+                ],
+                "summary": "Crea una ruta de aprendizaje",
+                "parameters": [
+                    {
+                        "description": "Datos de la ruta",
+                        "name": "path",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LearningPathRequest"
+                        }
+                    }
                 ],
-                "summary": "Obtiene el progreso de misiones",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.MissionProgress"
-                            }
+                            "$ref": "#/definitions/models.LearningPath"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Datos inválidos",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "No se pudo crear la ruta",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
             }
         },
-        "/missions/statistics": {
-            "get": {
+        "/admin/paths/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Devuelve estadísticas como el número total de misiones completadas y duración promedio",
+                "description": "Reemplaza el título, la descripción y los capítulos de la ruta, con las mismas reglas que al crearla",
                 "consumes": [
                     "application/json"
                 ],
@@ -543,120 +837,5244 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Missions"
+                    "Admin"
+                ],
+                "summary": "Reemplaza una ruta de aprendizaje",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la ruta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nuevos datos de la ruta",
+                        "name": "path",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LearningPathRequest"
+                        }
+                    }
                 ],
-                "summary": "Obtiene estadísticas del usuario",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserStatistics"
+                            "$ref": "#/definitions/models.LearningPath"
                         }
                     },
-                    "401": {
-                        "description": "Usuario no autenticado",
+                    "400": {
+                        "description": "Datos inválidos",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Ruta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor",
+                        "description": "No se pudo actualizar la ruta",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenericResponse"
+                            "$ref": "#/definitions/apierror.Response"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.GenericResponse": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Elimina la ruta; sus misiones y el progreso de los usuarios no se modifican",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Elimina una ruta de aprendizaje",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la ruta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ruta eliminada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID de ruta inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Ruta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo eliminar la ruta",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las organizaciones dadas de alta por ID, sin incluir la organización por defecto. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link. Solo para administradores de la organización por defecto.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Lista las organizaciones",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_Tenant"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador de la organización por defecto",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las organizaciones",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra una escuela u organización. Sus usuarios se registran e inician sesión enviando su ID en la cabecera X-Tenant-ID y no ven los datos de las demás organizaciones. Solo para administradores de la organización por defecto.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Da de alta una organización",
+                "parameters": [
+                    {
+                        "description": "Datos de la organización",
+                        "name": "tenant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tenant"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador de la organización por defecto",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe una organización con ese ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo crear la organización",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/translations/missing": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revisa una página de las misiones no archivadas de la organización, por fecha de creación, y devuelve para cada misión e idioma si está completa y, si no, los textos que faltan traducir. limit cuenta misiones: cada una aparece una vez por idioma revisado. Las misiones globales solo se revisan en la organización por defecto, que es la que puede traducirlas. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Informa las traducciones pendientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Revisa solo este idioma",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Misiones por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_TranslationStatus"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Idioma o parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de administrador",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo armar el informe",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/assignments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las misiones asignadas por el docente autenticado, ordenadas por fecha de entrega. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assignments"
+                ],
+                "summary": "Lista las asignaciones del docente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_Assignment"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las asignaciones",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asigna una misión a una clase del docente o a un estudiante inscrito en alguna de sus clases, con fecha de apertura y de entrega. Los administradores pueden asignar a cualquier clase o estudiante.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assignments"
+                ],
+                "summary": "Asigna una misión",
+                "parameters": [
+                    {
+                        "description": "Datos de la asignación",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAssignmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Assignment"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión, clase o estudiante no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo crear la asignación",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/assignments/{id}/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve el avance de cada estudiante en la misión asignada: no iniciada, en curso o completada, y si la completó a tiempo según la fecha en que terminó la misión. Con status=not_started lista solo a quienes no la han empezado. Solo lo ve el docente que la creó o un administrador.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assignments"
+                ],
+                "summary": "Obtiene el reporte de una asignación",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la asignación",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtra a los estudiantes: not_started, in_progress o completed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AssignmentReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Asignación no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo obtener el reporte",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Autentica a un usuario de la organización indicada en X-Tenant-ID, abre una sesión y devuelve un token de acceso JWT de corta duración, que incluye la organización, junto con un refresh token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Inicia sesión de usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organización del usuario",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Credenciales de usuario (email y password)",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens generados exitosamente",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Credenciales incorrectas",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoca la sesión del token usado. Con \"all\" en true revoca todas las sesiones del usuario, por ejemplo ante la pérdida de un dispositivo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Cierra la sesión",
+                "parameters": [
+                    {
+                        "description": "Alcance del cierre de sesión",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sesión cerrada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Intercambia un refresh token vigente por un nuevo token de acceso y un nuevo refresh token. El refresh token usado deja de ser válido; reutilizarlo revoca la sesión completa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Renueva el token de acceso",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organización del usuario; la misma que al iniciar sesión",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Refresh token",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens renovados",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh token inválido, expirado o revocado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Permite registrar un nuevo usuario en la organización indicada en X-Tenant-ID, o en la organización por defecto si no se envía.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registro de usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organización del usuario",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Datos del usuario",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Usuario creado exitosamente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "La organización no existe",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/classrooms": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las clases a cargo del docente autenticado, con su código y sus estudiantes, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Lista las clases del docente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_Classroom"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las clases",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Crea una clase a cargo del docente autenticado con un código aleatorio de 8 caracteres, que los estudiantes usan para inscribirse.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Crea una clase",
+                "parameters": [
+                    {
+                        "description": "Datos de la clase",
+                        "name": "classroom",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateClassroomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Classroom"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo crear la clase",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/classrooms/join": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inscribe al estudiante autenticado en la clase del código indicado. El código no distingue mayúsculas y admite espacios o guiones. Inscribirse de nuevo en la misma clase no tiene efecto.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Inscribe al estudiante en una clase",
+                "parameters": [
+                    {
+                        "description": "Código de la clase",
+                        "name": "join",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.JoinClassroomRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentClassroom"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Solo los estudiantes pueden inscribirse",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Código de clase inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo completar la inscripción",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/classrooms/{id}/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve el avance de cada estudiante de la clase (misiones completadas, duración promedio, porcentaje de avance, XP, nivel y misiones activas) y el resumen de la clase. Solo lo ve el docente de la clase o un administrador.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Obtiene el tablero de una clase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la clase",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ClassroomDashboard"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Clase no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo obtener el tablero",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/classrooms/{id}/students/{studentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve las estadísticas del estudiante junto con sus 20 misiones activas y sus 20 completadas más recientes. Solo lo ve el docente de la clase o un administrador.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Obtiene el avance de un estudiante de la clase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la clase",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del estudiante",
+                        "name": "studentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StudentReport"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Clase o estudiante no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo obtener el avance del estudiante",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quita al estudiante de la clase. Su progreso se conserva.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Da de baja a un estudiante de la clase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la clase",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID del estudiante",
+                        "name": "studentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Se requiere rol de docente",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Clase o estudiante no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo dar de baja al estudiante",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/achievements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de los logros obtenidos por el usuario autenticado, en el orden en que los consiguió. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Achievements"
+                ],
+                "summary": "Lista los logros del usuario",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_UserAchievement"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener los logros",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/assignments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las asignaciones abiertas del usuario autenticado, propias o de sus clases, ordenadas por fecha de entrega, con su avance y si la completó a tiempo. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Assignments"
+                ],
+                "summary": "Lista las misiones asignadas al usuario",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_StudentAssignment"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las asignaciones",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/classrooms": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las clases en que está inscrito el usuario autenticado, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Lista las clases del estudiante",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_StudentClassroom"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las clases",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/classrooms/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Da de baja al usuario autenticado de la clase. Su progreso se conserva.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Classrooms"
+                ],
+                "summary": "Abandona una clase",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la clase",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "No estás inscrito en la clase",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo abandonar la clase",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/preferences": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Modifica solo los campos enviados. La zona horaria define en qué día cuenta cada actividad para la racha. En los rankings públicos los menores de 18 años, o quienes no indicaron su fecha de nacimiento, aparecen con un seudónimo aunque elijan un nombre; con hide_from_leaderboard el usuario deja de aparecer en ellos. Con locale las misiones se muestran en ese idioma, sin importar Accept-Language.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Actualiza las preferencias del usuario",
+                "parameters": [
+                    {
+                        "description": "Preferencias a modificar",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdatePreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Usuario no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron guardar las preferencias",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/streak": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve la racha diaria actual y la más larga, contadas en la zona horaria del usuario, las protecciones disponibles y un calendario con las misiones completadas cada día. Se gana una protección cada 7 días seguidos (máximo 2); cada una cubre un día sin actividad.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Obtiene la racha del usuario",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 365,
+                        "description": "Días del calendario, hasta 366",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StreakResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cantidad de días inválida",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error al obtener la racha",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/mission/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna los detalles de una misión específica por su ID, en el idioma del usuario: el de su preferencia o, si no eligió uno, el de Accept-Language. Lo que no está traducido se muestra en el idioma original. Las respuestas del cuestionario no se incluyen.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene una misión por su ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Mission"
+                        }
+                    },
+                    "400": {
+                        "description": "ID de misión inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/abandon": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Abandona una misión iniciada o pausada. Puede volver a iniciarse más tarde desde cero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Abandona una misión",
+                "parameters": [
+                    {
+                        "description": "ID de la misión",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "La misión no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está en curso",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/active": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna una página de las misiones en curso (iniciadas o pausadas) del usuario autenticado, por defecto de la iniciada más recientemente a la más antigua, con el mismo tiempo transcurrido que /missions/progress. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene misiones activas de un usuario",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-started",
+                        "description": "started o ended; con - delante, en orden descendente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mission para incluir la misión de cada progreso",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_MissionProgressView"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/all": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna las misiones disponibles (no archivadas), sin las respuestas de los cuestionarios y en el idioma del usuario, junto con el total y las facetas (cuántas misiones hay por categoría, etiqueta, dificultad y grado) de todas las que cumplen los filtros. Las misiones vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link. A los estudiantes con alguna asignación abierta, dirigida a ellos o a sus clases, les devuelve por defecto solo esas misiones; assigned=false devuelve todo el catálogo y assigned=true solo las asignadas, para cualquier usuario.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene el catálogo de misiones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo las misiones asignadas al usuario; por defecto, true para los estudiantes con asignaciones abiertas",
+                        "name": "assigned",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Categoría",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Etiquetas separadas por comas; la misión debe tenerlas todas",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dificultad, de 1 a 5",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Grado escolar, de 1 a 12",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Duración estimada máxima en minutos",
+                        "name": "max_minutes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created",
+                        "description": "created, title, difficulty, grade o duration; con - delante, en orden descendente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Misiones por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada misión, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionCatalog"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las misiones",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Actualiza el estado de la misión a \"completada\". Solo se pueden completar misiones iniciadas; una misión pausada debe reanudarse antes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Completa una misión",
+                "parameters": [
+                    {
+                        "description": "ID de la misión",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "La misión no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está en curso",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/completed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna una página de las misiones con estado \"completada\" del usuario autenticado, por defecto de la completada más recientemente a la más antigua, con el mismo tiempo transcurrido que /missions/progress. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene misiones completadas de un usuario",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-ended",
+                        "description": "started o ended; con - delante, en orden descendente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mission para incluir la misión de cada progreso",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_MissionProgressView"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/leaderboard": {
+            "get": {
+                "description": "Devuelve una página del ranking semanal, mensual o histórico de una organización, que se actualiza al completar cada misión. Cada entrada muestra solo un nombre público y un avatar; los menores aparecen con un seudónimo y quienes ocultaron su perfil no aparecen. Se ordena por XP, luego por misiones completadas y, a igual puntaje, primero quien lo alcanzó antes. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene el ranking de usuarios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organización cuyo ranking se consulta; por defecto, la organización por defecto",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "all_time",
+                        "description": "Periodo: weekly, monthly o all_time",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Entradas por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "rank",
+                        "description": "Solo rank",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada entrada, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_LeaderboardEntry"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/leaderboard/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve la posición del usuario autenticado en el ranking del periodo junto con los usuarios inmediatamente anteriores y posteriores.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene la posición del usuario en el ranking",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "all_time",
+                        "description": "Periodo: weekly, monthly o all_time",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 2,
+                        "description": "Vecinos a cada lado, hasta 10",
+                        "name": "neighbours",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LeaderboardPosition"
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "El usuario no completó misiones en el periodo u ocultó su perfil",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/overview": {
+            "get": {
+                "description": "Devuelve estadísticas sobre las misiones completadas en una organización, incluyendo la misión más popular y el tiempo promedio de finalización. Como es público, de cada misión solo incluye el ID y el título.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene el resumen de las misiones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Organización consultada; por defecto, la organización por defecto",
+                        "name": "X-Tenant-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resumen de misiones",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno al obtener el resumen",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/pause": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pausa una misión iniciada. El tiempo en pausa no cuenta para la duración de la misión.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Pausa una misión",
+                "parameters": [
+                    {
+                        "description": "ID de la misión",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "La misión no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está en curso",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página del progreso del usuario en sus misiones, por defecto de la iniciada más recientemente a la más antigua, con el tiempo activo transcurrido. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios; sin él no se consultan las misiones y solo las completadas traen su porcentaje (100). Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene el progreso de misiones",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-started",
+                        "description": "started o ended; con - delante, en orden descendente",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "mission para incluir la misión de cada progreso",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_MissionProgressView"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/resume": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reanuda una misión pausada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Reanuda una misión",
+                "parameters": [
+                    {
+                        "description": "ID de la misión",
+                        "name": "mission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MissionActionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "La misión no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está pausada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Busca las palabras de q en el título, la descripción y las etiquetas de las misiones disponibles, sin distinguir mayúsculas ni acentos y reconociendo los plurales. Los resultados se ordenan por relevancia: pesa más una coincidencia en el título que en las etiquetas, y en estas más que en la descripción. Se busca en el idioma original de las misiones, pero se devuelven en el idioma del usuario; cada resultado incluye fragmentos de ese texto con las coincidencias marcadas con \u003cmark\u003e. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Busca misiones por texto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texto a buscar",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Resultados por página, hasta 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "relevance",
+                        "description": "Solo relevance",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas: mission, score o highlights",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-handlers_MissionSearchHit"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo buscar",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/statistics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve estadísticas como el número total de misiones completadas, la duración promedio, la XP acumulada y el nivel",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Obtiene estadísticas del usuario",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserStatistics"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/steps/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marca un paso de una misión iniciada como completado. Al completar el último paso obligatorio la misión se completa automáticamente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Completa un paso de una misión",
+                "parameters": [
+                    {
+                        "description": "Misión y paso a completar",
+                        "name": "step",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CompleteStepRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paso completado y progreso actualizado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "La misión no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión o paso no encontrado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está en curso o el paso ya fue completado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de los intentos calificados del usuario en el cuestionario de una misión, del más antiguo al más reciente. Las respuestas calificadas solo se incluyen cuando el usuario ya aprobó o agotó sus intentos. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Lista los intentos de un cuestionario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_QuizAttempt"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID de misión o parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/missions/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Califica en el servidor las respuestas al cuestionario de una misión iniciada y guarda el intento. Si el puntaje alcanza el mínimo para aprobar, la misión se completa. Cada cuestionario admite maxAttempts intentos; el intento solo incluye las respuestas calificadas si aprobó o si fue el último disponible.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Missions"
+                ],
+                "summary": "Envía las respuestas de un cuestionario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la misión",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Respuestas",
+                        "name": "answers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SubmitQuizRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Intento calificado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Datos inválidos, la misión no tiene cuestionario o no fue iniciada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Misión no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "La misión no está en curso o no quedan intentos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/paths": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve una página de las rutas de aprendizaje de la organización, con sus capítulos y los IDs de sus misiones, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paths"
+                ],
+                "summary": "Lista las rutas de aprendizaje",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Resultados por página, hasta 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de cada resultado, separados por comas",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Page-models_LearningPath"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "URL de la página siguiente, con rel=next"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "Cursor de la página siguiente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parámetros inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudieron obtener las rutas",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/paths/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devuelve la ruta con sus capítulos y, por cada misión, su estado para el usuario autenticado: completed si la completó, locked si le falta completar algún prerrequisito y unlocked si puede iniciarla. Las misiones archivadas o eliminadas se omiten y no bloquean a las demás. Las misiones se muestran en el idioma del usuario.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Paths"
+                ],
+                "summary": "Obtiene una ruta de aprendizaje con el avance del usuario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID de la ruta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LearningPathView"
+                        }
+                    },
+                    "400": {
+                        "description": "ID de ruta inválido",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Usuario no autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Ruta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "No se pudo obtener la ruta",
+                        "schema": {
+                            "$ref": "#/definitions/apierror.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apierror.Code": {
+            "type": "string",
+            "enum": [
+                "VALIDATION_FAILED",
+                "INVALID_QUERY",
+                "INVALID_OBJECT_ID",
+                "INVALID_CURSOR",
+                "UNAUTHENTICATED",
+                "FORBIDDEN",
+                "INTERNAL_ERROR",
+                "TOKEN_MISSING",
+                "TOKEN_INVALID",
+                "SESSION_EXPIRED",
+                "INVALID_CREDENTIALS",
+                "REFRESH_TOKEN_INVALID",
+                "REFRESH_TOKEN_REUSED",
+                "TENANT_NOT_FOUND",
+                "TENANT_MISMATCH",
+                "TENANT_EXISTS",
+                "USER_NOT_FOUND",
+                "MISSION_NOT_FOUND",
+                "STEP_NOT_FOUND",
+                "PATH_NOT_FOUND",
+                "ACHIEVEMENT_NOT_FOUND",
+                "CLASSROOM_NOT_FOUND",
+                "STUDENT_NOT_FOUND",
+                "ASSIGNMENT_NOT_FOUND",
+                "TRANSLATION_NOT_FOUND",
+                "STUDENT_NOT_ENROLLED",
+                "NOT_ENROLLED",
+                "INVALID_JOIN_CODE",
+                "NOT_ON_LEADERBOARD",
+                "MISSION_NOT_STARTED",
+                "MISSION_ALREADY_STARTED",
+                "INVALID_TRANSITION",
+                "STEPS_INCOMPLETE",
+                "STEP_ALREADY_COMPLETED",
+                "QUIZ_REQUIRED",
+                "NO_QUIZ",
+                "QUIZ_ATTEMPTS_EXHAUSTED",
+                "PROGRESS_CONFLICT",
+                "MISSION_LOCKED",
+                "MISSION_IN_USE",
+                "GLOBAL_MISSION_FORBIDDEN",
+                "ACHIEVEMENT_CODE_TAKEN",
+                "SOURCE_LOCALE",
+                "UNSUPPORTED_LOCALE",
+                "REQUIRED",
+                "INVALID_JSON",
+                "INVALID_TYPE",
+                "INVALID_FORMAT",
+                "INVALID_EMAIL",
+                "INVALID_DATE",
+                "OUT_OF_RANGE",
+                "TOO_SMALL",
+                "INVALID_LENGTH",
+                "TOO_MANY",
+                "TOO_FEW",
+                "DUPLICATE",
+                "NOT_FOUND",
+                "UNKNOWN_VALUE",
+                "INVALID_CHARACTER",
+                "INVALID_TIMEZONE",
+                "MUST_BE_AFTER",
+                "MUTUALLY_EXCLUSIVE",
+                "NO_CHANGES",
+                "NO_TEXT",
+                "INVALID_RULE",
+                "GLOBAL_PREREQUISITE",
+                "PREREQUISITE_CYCLE",
+                "PREREQUISITE_ORDER"
+            ],
+            "x-enum-varnames": [
+                "ValidationFailed",
+                "InvalidQueryParams",
+                "InvalidObjectID",
+                "InvalidCursor",
+                "Unauthenticated",
+                "Forbidden",
+                "InternalError",
+                "TokenMissing",
+                "TokenInvalid",
+                "SessionExpired",
+                "InvalidCredentials",
+                "RefreshTokenInvalid",
+                "RefreshTokenReused",
+                "TenantNotFound",
+                "TenantMismatch",
+                "TenantExists",
+                "UserNotFound",
+                "MissionNotFound",
+                "StepNotFound",
+                "PathNotFound",
+                "AchievementNotFound",
+                "ClassroomNotFound",
+                "StudentNotFound",
+                "AssignmentNotFound",
+                "TranslationNotFound",
+                "StudentNotEnrolled",
+                "NotEnrolled",
+                "InvalidJoinCode",
+                "NotOnLeaderboard",
+                "MissionNotStarted",
+                "MissionAlreadyStarted",
+                "InvalidTransition",
+                "StepsIncomplete",
+                "StepAlreadyCompleted",
+                "QuizRequired",
+                "NoQuiz",
+                "QuizAttemptsExhausted",
+                "ProgressConflict",
+                "MissionLocked",
+                "MissionInUse",
+                "GlobalMissionForbidden",
+                "AchievementCodeTaken",
+                "SourceLocale",
+                "UnsupportedLocale",
+                "Required",
+                "InvalidJSON",
+                "InvalidType",
+                "InvalidFormat",
+                "InvalidEmail",
+                "InvalidDate",
+                "OutOfRange",
+                "TooSmall",
+                "InvalidLength",
+                "TooMany",
+                "TooFew",
+                "Duplicate",
+                "NotFound",
+                "UnknownValue",
+                "InvalidCharacter",
+                "InvalidTimezone",
+                "MustBeAfter",
+                "MutuallyExclusive",
+                "NoChanges",
+                "NoText",
+                "InvalidRule",
+                "GlobalPrerequisite",
+                "PrerequisiteCycle",
+                "PrerequisiteOrder"
+            ]
+        },
+        "apierror.Error": {
+            "description": "Error de la API",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/apierror.Code"
+                        }
+                    ],
+                    "example": "MISSION_NOT_FOUND"
+                },
+                "details": {
+                    "description": "Details son los campos inválidos de la petición",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apierror.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Misión no encontrada"
+                },
+                "params": {
+                    "description": "Params son los datos del error, por ejemplo el estado de la misión",
+                    "type": "object"
+                },
+                "requestId": {
+                    "description": "RequestID es el de la cabecera X-Request-ID",
+                    "type": "string",
+                    "example": "4f6c2a9e8b1d4c7a"
+                }
+            }
+        },
+        "apierror.FieldError": {
+            "description": "Campo inválido",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/apierror.Code"
+                        }
+                    ],
+                    "example": "REQUIRED"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "steps[0].title"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Es obligatorio"
+                },
+                "params": {
+                    "type": "object"
+                }
+            }
+        },
+        "apierror.Response": {
+            "description": "Respuesta de error",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/apierror.Error"
+                }
+            }
+        },
+        "handlers.AssignmentReport": {
+            "type": "object",
+            "properties": {
+                "assignment": {
+                    "$ref": "#/definitions/models.Assignment"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AssignmentStudentStatus"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/handlers.AssignmentSummary"
+                }
+            }
+        },
+        "handlers.AssignmentStudentStatus": {
+            "type": "object",
+            "properties": {
+                "completedAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "Ana"
+                },
+                "late": {
+                    "description": "Late indica que se completó después de la fecha de entrega o que, sin\ncompletarse, la fecha ya pasó",
+                    "type": "boolean",
+                    "example": false
+                },
+                "onTime": {
+                    "description": "OnTime indica que la misión se completó hasta la fecha de entrega",
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AssignmentStatus"
+                        }
+                    ],
+                    "example": "completed"
+                },
+                "studentId": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ana"
+                }
+            }
+        },
+        "handlers.AssignmentSummary": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer",
+                    "example": 16
+                },
+                "inProgress": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "late": {
+                    "description": "Late cuenta las completadas fuera de plazo y las vencidas sin completar",
+                    "type": "integer",
+                    "example": 4
+                },
+                "notStarted": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "onTime": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "students": {
+                    "type": "integer",
+                    "example": 24
+                }
+            }
+        },
+        "handlers.ClassroomDashboard": {
+            "type": "object",
+            "properties": {
+                "classroom": {
+                    "$ref": "#/definitions/models.Classroom"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.StudentProgressSummary"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/handlers.ClassroomSummary"
+                }
+            }
+        },
+        "handlers.ClassroomSummary": {
+            "type": "object",
+            "properties": {
+                "activeCount": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "averageCompleted": {
+                    "description": "Los promedios son por estudiante, salvo AverageDuration, que es el\npromedio de todas las misiones completadas en la clase",
+                    "type": "number",
+                    "example": 4
+                },
+                "averageDuration": {
+                    "type": "number",
+                    "example": 540000
+                },
+                "averageProgress": {
+                    "type": "number",
+                    "example": 38.5
+                },
+                "averageXp": {
+                    "type": "number",
+                    "example": 420
+                },
+                "students": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "totalCompleted": {
+                    "type": "integer",
+                    "example": 96
+                }
+            }
+        },
+        "handlers.CompleteStepRequest": {
+            "description": "Estructura del request para completar un paso de una misión",
+            "type": "object",
+            "required": [
+                "mission_id",
+                "step_id"
+            ],
+            "properties": {
+                "mission_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b6"
+                },
+                "step_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b7"
+                }
+            }
+        },
+        "handlers.CreateAchievementRequest": {
+            "description": "Estructura para crear un logro",
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Active es true si no se indica",
+                    "type": "boolean",
+                    "example": true
+                },
+                "code": {
+                    "type": "string",
+                    "example": "cinco-misiones"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Completa 5 misiones"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Explorador constante"
+                },
+                "rule": {
+                    "$ref": "#/definitions/models.AchievementRule"
+                }
+            }
+        },
+        "handlers.CreateAssignmentRequest": {
+            "description": "Misión asignada a una clase o a un estudiante",
+            "type": "object",
+            "required": [
+                "due_at",
+                "mission_id"
+            ],
+            "properties": {
+                "classroom_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b8"
+                },
+                "due_at": {
+                    "type": "string",
+                    "example": "2025-03-17T23:59:00Z"
+                },
+                "mission_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b6"
+                },
+                "opens_at": {
+                    "description": "OpensAt es opcional; por defecto la asignación se abre de inmediato",
+                    "type": "string",
+                    "example": "2025-03-10T08:00:00Z"
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "handlers.CreateClassroomRequest": {
+            "description": "Datos de una clase nueva",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Ciencias 5.º B"
+                }
+            }
+        },
+        "handlers.CreateMissionRequest": {
+            "description": "Estructura para crear una misión",
+            "type": "object",
+            "required": [
+                "description",
+                "title"
+            ],
+            "properties": {
+                "category": {
+                    "description": "Category, Tags, Difficulty (1 a 5), GradeLevel (1 a 12) y\nEstimatedMinutes clasifican la misión; en cero no se indican",
+                    "type": "string",
+                    "example": "astronomia"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Recorre la superficie marciana"
+                },
+                "difficulty": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "difficulty_multiplier": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "estimated_minutes": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "global": {
+                    "description": "Global ofrece la misión en todas las organizaciones; solo pueden\ncrearlas los administradores de la organización por defecto",
+                    "type": "boolean",
+                    "example": false
+                },
+                "grade_level": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "locale": {
+                    "description": "Locale es el idioma en que está escrita; por defecto, español",
+                    "type": "string",
+                    "example": "es"
+                },
+                "prerequisites": {
+                    "description": "Prerequisites son los IDs de las misiones que hay que completar antes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "60a7b97f5e41c42e7c2e30b5"
+                    ]
+                },
+                "quiz": {
+                    "$ref": "#/definitions/handlers.QuizInput"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MissionStepInput"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "planetas",
+                        "marte"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explorar Marte"
+                },
+                "xp_reward": {
+                    "description": "XPReward es la XP base; si no se indica se usa models.DefaultXPReward",
+                    "type": "integer",
+                    "example": 150
+                }
+            }
+        },
+        "handlers.CreateTenantRequest": {
+            "description": "Estructura para dar de alta una organización",
+            "type": "object",
+            "required": [
+                "id",
+                "name"
+            ],
+            "properties": {
+                "id": {
+                    "description": "ID identifica a la organización en la cabecera X-Tenant-ID: de 2 a 40\nletras minúsculas, dígitos o guiones",
+                    "type": "string",
+                    "example": "colegio-central"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Colegio Central"
+                }
+            }
+        },
+        "handlers.GenericResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.JoinClassroomRequest": {
+            "description": "Código de la clase",
+            "type": "object",
+            "required": [
+                "join_code"
+            ],
+            "properties": {
+                "join_code": {
+                    "type": "string",
+                    "example": "K7QX-M2PA"
+                }
+            }
+        },
+        "handlers.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string",
+                    "example": "cohete"
+                },
+                "completedCount": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "Cometa Veloz 42"
+                },
+                "level": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "me": {
+                    "description": "Me marca la entrada del usuario autenticado en /missions/leaderboard/me",
+                    "type": "boolean"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "xp": {
+                    "type": "integer",
+                    "example": 450
+                }
+            }
+        },
+        "handlers.LeaderboardPosition": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries incluye al usuario, precedido y seguido por sus vecinos",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LeaderboardEntry"
+                    }
+                },
+                "period": {
+                    "type": "string",
+                    "example": "weekly"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "handlers.LearningPathRequest": {
+            "description": "Datos de una ruta de aprendizaje",
+            "type": "object",
+            "required": [
+                "chapters",
+                "title"
+            ],
+            "properties": {
+                "chapters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PathChapterInput"
+                    }
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Del Sol a Neptuno"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Sistema solar"
+                }
+            }
+        },
+        "handlers.LearningPathView": {
+            "type": "object",
+            "properties": {
+                "chapters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PathChapterView"
+                    }
+                },
+                "completed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Sistema solar"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "handlers.LoginRequest": {
+            "description": "Estructura para iniciar sesión",
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "usuario@email.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "handlers.LogoutRequest": {
+            "description": "Estructura para cerrar sesión",
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handlers.MissionActionRequest": {
+            "description": "Estructura del request para cambiar el estado de una misión",
+            "type": "object",
+            "required": [
+                "mission_id"
+            ],
+            "properties": {
+                "mission_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b6"
+                }
+            }
+        },
+        "handlers.MissionCatalog": {
+            "type": "object",
+            "properties": {
+                "facets": {
+                    "$ref": "#/definitions/models.MissionFacets"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total cuenta todas las misiones que cumplen los filtros, no solo las de la página",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handlers.MissionHighlights": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Description es un fragmento de la descripción alrededor de la primera\ncoincidencia, con \"…\" si se recortó",
+                    "type": "string",
+                    "example": "…la superficie de \u003cmark\u003eMarte\u003c/mark\u003e en un rover…"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explorar \u003cmark\u003eMarte\u003c/mark\u003e"
+                }
+            }
+        },
+        "handlers.MissionProgressView": {
+            "description": "Progreso de una misión con su porcentaje de avance",
+            "type": "object",
+            "properties": {
+                "abandonedAt": {
+                    "type": "string"
+                },
+                "activeDurationMs": {
+                    "type": "integer"
+                },
+                "completionPercentage": {
+                    "type": "number",
+                    "example": 50
+                },
+                "elapsedMs": {
+                    "description": "ElapsedMs es el tiempo activo hasta el momento de la respuesta: a\ndiferencia de activeDurationMs, incluye el tramo en curso",
+                    "type": "integer",
+                    "example": 420000
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastResumedAt": {
+                    "type": "string"
+                },
+                "mission": {
+                    "description": "Mission es la misión, en el idioma del usuario, con expand=mission",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Mission"
+                        }
+                    ]
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "pausedAt": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProgressStatus"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StepProgress"
+                    }
+                },
+                "stepsCompleted": {
+                    "description": "El avance en los pasos necesita la misión: en las listas de progreso\nsolo se incluye con expand=mission, salvo el 100% de las completadas",
+                    "type": "integer",
+                    "example": 2
+                },
+                "stepsRequired": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "xpAwarded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MissionSearchHit": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "$ref": "#/definitions/handlers.MissionHighlights"
+                },
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 10.5
+                }
+            }
+        },
+        "handlers.MissionStepInput": {
+            "description": "Paso de una misión",
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Elige un punto de aterrizaje seguro"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b7"
+                },
+                "optional": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Aterriza en el cráter"
+                }
+            }
+        },
+        "handlers.MissionTranslationRequest": {
+            "description": "Traducción de una misión",
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Travel across the Martian surface"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.StepTranslationInput"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explore Mars"
+                }
+            }
+        },
+        "handlers.Page-handlers_LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LeaderboardEntry"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-handlers_MissionProgressView": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MissionProgressView"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-handlers_MissionSearchHit": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MissionSearchHit"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-handlers_StudentAssignment": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.StudentAssignment"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-handlers_StudentClassroom": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.StudentClassroom"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-handlers_TranslationStatus": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TranslationStatus"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_Achievement": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Achievement"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_Assignment": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Assignment"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_Classroom": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Classroom"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_LearningPath": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LearningPath"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_Mission": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Mission"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_QuizAttempt": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuizAttempt"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_Tenant": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tenant"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Page-models_UserAchievement": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserAchievement"
+                    }
+                },
+                "nextCursor": {
+                    "description": "NextCursor es el cursor de la página siguiente, si la hay; también se\nenvía en las cabeceras X-Next-Cursor y Link",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PatchAchievementRequest": {
+            "description": "Estructura para actualizar parcialmente un logro",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Completa 5 misiones"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Explorador constante"
+                },
+                "rule": {
+                    "$ref": "#/definitions/models.AchievementRule"
+                }
+            }
+        },
+        "handlers.PatchMissionRequest": {
+            "description": "Estructura para actualizar parcialmente una misión",
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "category": {
+                    "type": "string",
+                    "example": "astronomia"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Recorre la superficie marciana"
+                },
+                "difficulty": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "difficulty_multiplier": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "estimated_minutes": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "grade_level": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "prerequisites": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quiz": {
+                    "$ref": "#/definitions/handlers.QuizInput"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MissionStepInput"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explorar Marte"
+                },
+                "xp_reward": {
+                    "type": "integer",
+                    "example": 150
+                }
+            }
+        },
+        "handlers.PathChapterInput": {
+            "description": "Capítulo de una ruta de aprendizaje",
+            "type": "object",
+            "properties": {
+                "mission_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "60a7b97f5e41c42e7c2e30b6"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Los planetas interiores"
+                }
+            }
+        },
+        "handlers.PathChapterView": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PathNodeView"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Los planetas interiores"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handlers.PathNodeView": {
+            "type": "object",
+            "properties": {
+                "missingPrerequisites": {
+                    "description": "MissingPrerequisites son los prerrequisitos que faltan para desbloquearla",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mission": {
+                    "$ref": "#/definitions/models.Mission"
+                },
+                "state": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PathNodeState"
+                        }
+                    ],
+                    "example": "locked"
+                }
+            }
+        },
+        "handlers.QuizAnswerInput": {
+            "description": "Respuesta a una pregunta",
+            "type": "object",
+            "required": [
+                "question_id"
+            ],
+            "properties": {
+                "number": {
+                    "type": "number"
+                },
+                "option": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "question_id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b8"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.QuizInput": {
+            "description": "Cuestionario de una misión",
+            "type": "object",
+            "properties": {
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "passing_score": {
+                    "type": "number",
+                    "example": 70
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.QuizQuestionInput"
+                    }
+                }
+            }
+        },
+        "handlers.QuizQuestionInput": {
+            "description": "Pregunta de un cuestionario",
+            "type": "object",
+            "properties": {
+                "accepted_answers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "correct_option": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b8"
+                },
+                "numeric_answer": {
+                    "type": "number"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prompt": {
+                    "type": "string",
+                    "example": "¿Cuál es el planeta rojo?"
+                },
+                "tolerance": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "multiple_choice"
+                }
+            }
+        },
+        "handlers.RefreshRequest": {
+            "description": "Estructura para renovar tokens",
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "b3BhcXVlLXJlZnJlc2gtdG9rZW4"
+                }
+            }
+        },
+        "handlers.RegisterRequest": {
+            "description": "Estructura para registrar un usuario",
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "birth_date": {
+                    "description": "BirthDate (AAAA-MM-DD) es opcional; sin ella el usuario se trata como\nmenor y aparece con un seudónimo en los rankings",
+                    "type": "string",
+                    "example": "2012-05-01"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "usuario@email.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "timezone": {
+                    "description": "Timezone es una zona horaria IANA opcional; por defecto UTC",
+                    "type": "string",
+                    "example": "America/Guatemala"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "usuario123"
+                }
+            }
+        },
+        "handlers.StepTranslationInput": {
+            "description": "Traducción de un paso",
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Choose a safe landing spot"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "60a7b97f5e41c42e7c2e30b7"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Land in the crater"
+                }
+            }
+        },
+        "handlers.StreakResponse": {
+            "description": "Racha diaria y calendario de actividad",
+            "type": "object",
+            "properties": {
+                "activeToday": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "current": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "freezes": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "heatmap": {
+                    "description": "Heatmap tiene un elemento por día, del más antiguo a hoy",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HeatmapDay"
+                    }
+                },
+                "lastActiveDay": {
+                    "type": "string",
+                    "example": "2025-03-14"
+                },
+                "longest": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "America/Guatemala"
+                }
+            }
+        },
+        "handlers.StudentAssignment": {
+            "type": "object",
+            "properties": {
+                "classroomId": {
+                    "type": "string"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "dueAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "late": {
+                    "description": "Late indica que se completó después de la fecha de entrega o que, sin\ncompletarse, la fecha ya pasó",
+                    "type": "boolean",
+                    "example": false
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "missionTitle": {
+                    "type": "string",
+                    "example": "Explorar Marte"
+                },
+                "onTime": {
+                    "description": "OnTime indica que la misión se completó hasta la fecha de entrega",
+                    "type": "boolean",
+                    "example": true
+                },
+                "opensAt": {
+                    "description": "OpensAt es desde cuándo los estudiantes ven la misión asignada",
+                    "type": "string"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AssignmentStatus"
+                        }
+                    ],
+                    "example": "completed"
+                },
+                "studentId": {
+                    "type": "string"
+                },
+                "teacherId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.StudentClassroom": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ciencias 5.º B"
+                }
+            }
+        },
+        "handlers.StudentProgressSummary": {
+            "type": "object",
+            "properties": {
+                "activeCount": {
+                    "description": "ActiveCount cuenta las misiones iniciadas o en pausa",
+                    "type": "integer",
+                    "example": 1
+                },
+                "averageDuration": {
+                    "type": "number"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "Ana"
+                },
+                "lastCompletedAt": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "progressPercentage": {
+                    "type": "number"
+                },
+                "studentId": {
+                    "type": "string"
+                },
+                "totalCompleted": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "ana"
+                },
+                "xp": {
+                    "type": "integer"
+                },
+                "xpToNextLevel": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StudentReport": {
+            "type": "object",
+            "properties": {
+                "activeMissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MissionProgress"
+                    }
+                },
+                "completedMissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MissionProgress"
+                    }
+                },
+                "student": {
+                    "$ref": "#/definitions/handlers.StudentProgressSummary"
+                }
+            }
+        },
+        "handlers.SubmitQuizRequest": {
+            "description": "Respuestas a un cuestionario",
+            "type": "object",
+            "required": [
+                "answers"
+            ],
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.QuizAnswerInput"
+                    }
+                }
+            }
+        },
+        "handlers.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TranslationStatus": {
+            "type": "object",
+            "properties": {
+                "complete": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "title",
+                        "description"
+                    ]
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explorar Marte"
+                }
+            }
+        },
+        "handlers.UpdateMissionRequest": {
+            "description": "Estructura para reemplazar una misión",
+            "type": "object",
+            "required": [
+                "description",
+                "title"
+            ],
+            "properties": {
+                "category": {
+                    "description": "Category, Tags, Difficulty (1 a 5), GradeLevel (1 a 12) y\nEstimatedMinutes clasifican la misión; en cero no se indican",
+                    "type": "string",
+                    "example": "astronomia"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Recorre la superficie marciana"
+                },
+                "difficulty": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "difficulty_multiplier": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "estimated_minutes": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "grade_level": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "prerequisites": {
+                    "description": "Prerequisites son los IDs de las misiones que hay que completar antes",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "60a7b97f5e41c42e7c2e30b5"
+                    ]
+                },
+                "quiz": {
+                    "$ref": "#/definitions/handlers.QuizInput"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.MissionStepInput"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "planetas",
+                        "marte"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Explorar Marte"
+                },
+                "xp_reward": {
+                    "description": "XPReward y DifficultyMultiplier en 0 usan los valores por defecto",
+                    "type": "integer",
+                    "example": 150
+                }
+            }
+        },
+        "handlers.UpdatePreferencesRequest": {
+            "description": "Preferencias del usuario",
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "description": "Avatar es uno de models.Avatars; vacío vuelve al asignado por defecto",
+                    "type": "string",
+                    "example": "cohete"
+                },
+                "birth_date": {
+                    "description": "BirthDate (AAAA-MM-DD) decide si el nombre público puede mostrarse",
+                    "type": "string",
+                    "example": "2012-05-01"
+                },
+                "display_name": {
+                    "description": "DisplayName solo se muestra en público si el usuario es mayor de edad",
+                    "type": "string",
+                    "example": "Exploradora"
+                },
+                "hide_from_leaderboard": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "locale": {
+                    "description": "Locale es el idioma de las misiones (es, en o pt); vacío usa el de la\ncabecera Accept-Language",
+                    "type": "string",
+                    "example": "en"
+                },
+                "timezone": {
+                    "description": "Timezone es una zona horaria IANA; vacía vuelve a UTC",
+                    "type": "string",
+                    "example": "America/Guatemala"
+                }
+            }
+        },
+        "handlers.UserStatistics": {
+            "type": "object",
+            "properties": {
+                "averageDuration": {
+                    "type": "number"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "progressPercentage": {
+                    "type": "number"
+                },
+                "totalCompleted": {
+                    "type": "integer"
+                },
+                "xp": {
+                    "type": "integer"
+                },
+                "xpToNextLevel": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Achievement": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rule": {
+                    "$ref": "#/definitions/models.AchievementRule"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AchievementRule": {
+            "type": "object",
+            "properties": {
+                "threshold": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RuleType"
+                        }
+                    ],
+                    "example": "missions_completed"
+                }
+            }
+        },
+        "models.Assignment": {
+            "type": "object",
+            "properties": {
+                "classroomId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "dueAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "opensAt": {
+                    "description": "OpensAt es desde cuándo los estudiantes ven la misión asignada",
+                    "type": "string"
+                },
+                "studentId": {
+                    "type": "string"
+                },
+                "teacherId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AssignmentStatus": {
+            "type": "string",
+            "enum": [
+                "not_started",
+                "in_progress",
+                "completed"
+            ],
+            "x-enum-varnames": [
+                "AssignmentNotStarted",
+                "AssignmentInProgress",
+                "AssignmentCompleted"
+            ]
+        },
+        "models.Classroom": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "joinCode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "studentIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "teacherId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeatmapDay": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-03-14"
+                },
+                "frozen": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.LearningPath": {
+            "type": "object",
+            "properties": {
+                "chapters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PathChapter"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Sistema solar"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Mission": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "type": "boolean"
+                },
+                "archivedAt": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category, Tags, Difficulty, GradeLevel y EstimatedMinutes clasifican la\nmisión en el catálogo; en cero no se indicaron",
+                    "type": "string",
+                    "example": "astronomia"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "difficulty": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "difficultyMultiplier": {
+                    "type": "number"
+                },
+                "estimatedMinutes": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "global": {
+                    "type": "boolean"
+                },
+                "gradeLevel": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale es el idioma del título, la descripción y los pasos; vacío\nequivale a DefaultLocale. Translations los traduce a otros idiomas.",
+                    "type": "string",
+                    "example": "es"
+                },
+                "prerequisites": {
+                    "description": "Prerequisites son las misiones que hay que completar antes de iniciarla",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quiz": {
+                    "$ref": "#/definitions/models.Quiz"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MissionStep"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "planetas",
+                        "marte"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                },
+                "translations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.MissionTranslation"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "xpReward": {
+                    "description": "XPReward es la XP base de la misión; DifficultyMultiplier la escala.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MissionFacets": {
             "type": "object",
             "properties": {
-                "error": {
-                    "description": "Si hay error, lo incluye",
+                "categories": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "difficulties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "gradeLevels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.MissionProgress": {
+            "type": "object",
+            "properties": {
+                "abandonedAt": {
                     "type": "string"
                 },
-                "message": {
+                "activeDurationMs": {
+                    "type": "integer"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastResumedAt": {
+                    "type": "string"
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "pausedAt": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProgressStatus"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StepProgress"
+                    }
+                },
+                "userId": {
                     "type": "string"
+                },
+                "xpAwarded": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.LeaderboardEntry": {
+        "models.MissionStep": {
             "type": "object",
             "properties": {
-                "completed_count": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "username": {
+                "optional": {
+                    "type": "boolean"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "handlers.LoginRequest": {
-            "description": "Estructura para iniciar sesión",
+        "models.MissionTranslation": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "usuario@email.com"
+                "description": {
+                    "type": "string"
                 },
-                "password": {
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StepTranslation"
+                    }
+                },
+                "title": {
                     "type": "string",
-                    "example": "123456"
+                    "example": "Explore Mars"
                 }
             }
         },
-        "handlers.RegisterRequest": {
-            "description": "Estructura para registrar un usuario",
+        "models.PathChapter": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "usuario@email.com"
+                "missionIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "password": {
+                "title": {
                     "type": "string",
-                    "example": "123456"
+                    "example": "Los planetas interiores"
+                }
+            }
+        },
+        "models.PathNodeState": {
+            "type": "string",
+            "enum": [
+                "locked",
+                "unlocked",
+                "completed"
+            ],
+            "x-enum-varnames": [
+                "NodeLocked",
+                "NodeUnlocked",
+                "NodeCompleted"
+            ]
+        },
+        "models.ProgressStatus": {
+            "type": "string",
+            "enum": [
+                "iniciada",
+                "pausada",
+                "completada",
+                "abandonada"
+            ],
+            "x-enum-varnames": [
+                "ProgressStarted",
+                "ProgressPaused",
+                "ProgressCompleted",
+                "ProgressAbandoned"
+            ]
+        },
+        "models.QuestionType": {
+            "type": "string",
+            "enum": [
+                "multiple_choice",
+                "numeric",
+                "short_text"
+            ],
+            "x-enum-varnames": [
+                "QuestionMultipleChoice",
+                "QuestionNumeric",
+                "QuestionShortText"
+            ]
+        },
+        "models.Quiz": {
+            "type": "object",
+            "properties": {
+                "maxAttempts": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string",
-                    "example": "usuario123"
+                "passingScore": {
+                    "type": "number"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuizQuestion"
+                    }
                 }
             }
         },
-        "handlers.UserStatistics": {
+        "models.QuizAnswer": {
             "type": "object",
             "properties": {
-                "average_duration": {
+                "correct": {
+                    "type": "boolean"
+                },
+                "number": {
                     "type": "number"
                 },
-                "progress_percentage": {
+                "option": {
+                    "type": "integer"
+                },
+                "questionId": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.QuizAttempt": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QuizAnswer"
+                    }
+                },
+                "correct": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "missionId": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "score": {
                     "type": "number"
                 },
-                "total_completed": {
+                "submittedAt": {
+                    "type": "string"
+                },
+                "total": {
                     "type": "integer"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "models.Mission": {
+        "models.QuizQuestion": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "acceptedAnswers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "correctOption": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "numericAnswer": {
+                    "type": "number"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "tolerance": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.QuestionType"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "string",
+            "enum": [
+                "student",
+                "teacher",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "RoleStudent",
+                "RoleTeacher",
+                "RoleAdmin"
+            ]
+        },
+        "models.RuleType": {
+            "type": "string",
+            "enum": [
+                "missions_completed",
+                "missions_started",
+                "fast_completion",
+                "streak_days",
+                "xp_total"
+            ],
+            "x-enum-varnames": [
+                "RuleMissionsCompleted",
+                "RuleMissionsStarted",
+                "RuleFastCompletion",
+                "RuleStreakDays",
+                "RuleXPTotal"
+            ]
+        },
+        "models.StepProgress": {
+            "type": "object",
+            "properties": {
+                "completedAt": {
                     "type": "string"
                 },
+                "stepId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StepTranslation": {
+            "type": "object",
+            "properties": {
                 "description": {
                     "type": "string"
                 },
@@ -668,23 +6086,114 @@ const docTemplate = `{
                 }
             }
         },
-        "models.MissionProgress": {
+        "models.Streak": {
             "type": "object",
             "properties": {
-                "endDate": {
+                "current": {
+                    "type": "integer"
+                },
+                "freezes": {
+                    "type": "integer"
+                },
+                "frozenDays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lastActiveDay": {
+                    "type": "string",
+                    "example": "2025-03-14"
+                },
+                "longest": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Tenant": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
                     "type": "string"
                 },
                 "id": {
+                    "description": "ID es un identificador corto en minúsculas, como \"colegio-central\"",
+                    "type": "string",
+                    "example": "colegio-central"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Colegio Central"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "avatar": {
                     "type": "string"
                 },
-                "missionId": {
+                "birthDate": {
+                    "description": "BirthDate decide si el usuario es menor; ver PublicName.",
                     "type": "string"
                 },
-                "startDate": {
+                "createdAt": {
                     "type": "string"
                 },
-                "status": {
-                    "description": "\"iniciada\" o \"completada\"",
+                "displayName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "hideFromLeaderboard": {
+                    "description": "HideFromLeaderboard excluye al usuario de los rankings públicos.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale es el idioma preferido; vacío usa el de Accept-Language.",
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/models.Role"
+                },
+                "streak": {
+                    "$ref": "#/definitions/models.Streak"
+                },
+                "timezone": {
+                    "description": "Timezone es una zona horaria IANA; vacía equivale a UTC.",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "xp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UserAchievement": {
+            "type": "object",
+            "properties": {
+                "achievementId": {
+                    "type": "string"
+                },
+                "awardedAt": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
                 },
                 "userId": {
@@ -0,0 +1,230 @@
+// Package apierror define el formato único de las respuestas de error de la
+// API: un código estable que los clientes pueden interpretar, un mensaje en
+// el idioma de la petición, el detalle de los campos inválidos y el ID de la
+// petición para rastrearla en los logs.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"explorax-backend/internal/i18n"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Code identifica un error de forma estable; el mensaje puede cambiar o
+// traducirse, el código no.
+type Code string
+
+// Error es un error de la API. Params completa el mensaje y queda en la
+// respuesta para que los clientes no tengan que interpretar el texto.
+// @Description Error de la API
+type Error struct {
+	Code    Code   `json:"code" example:"MISSION_NOT_FOUND"`
+	Message string `json:"message" example:"Misión no encontrada"`
+	// Params son los datos del error, por ejemplo el estado de la misión
+	Params map[string]interface{} `json:"params,omitempty" swaggertype:"object"`
+	// Details son los campos inválidos de la petición
+	Details []FieldError `json:"details,omitempty"`
+	// RequestID es el de la cabecera X-Request-ID
+	RequestID string `json:"requestId,omitempty" example:"4f6c2a9e8b1d4c7a"`
+}
+
+// FieldError es un campo inválido de la petición. Field es su ruta con los
+// nombres del JSON o de la query string, por ejemplo "steps[0].title".
+// @Description Campo inválido
+type FieldError struct {
+	Field   string                 `json:"field" example:"steps[0].title"`
+	Code    Code                   `json:"code" example:"REQUIRED"`
+	Message string                 `json:"message" example:"Es obligatorio"`
+	Params  map[string]interface{} `json:"params,omitempty" swaggertype:"object"`
+}
+
+// Response es el cuerpo de todas las respuestas de error.
+// @Description Respuesta de error
+type Response struct {
+	Error Error `json:"error"`
+}
+
+// New crea un error de la API con el código indicado.
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+// With agrega un dato al error.
+func (e *Error) With(name string, value interface{}) *Error {
+	if e.Params == nil {
+		e.Params = map[string]interface{}{}
+	}
+	e.Params[name] = value
+	return e
+}
+
+// Error devuelve el código y el mensaje en el idioma por defecto.
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.localize(models.DefaultLocale).Message
+}
+
+// Status devuelve el código HTTP con que se responde el error.
+func (e *Error) Status() int {
+	if definition, ok := errorCatalog[e.Code]; ok {
+		return definition.status
+	}
+	return http.StatusInternalServerError
+}
+
+// localize devuelve una copia del error con los mensajes en locale.
+func (e *Error) localize(locale string) Error {
+	localized := *e
+	localized.Message = i18n.Format(translate(errorCatalog[e.Code].messages, locale), e.Params)
+	localized.Details = make([]FieldError, len(e.Details))
+	for i, detail := range e.Details {
+		localized.Details[i] = detail.localize(locale)
+	}
+	if len(localized.Details) == 0 {
+		localized.Details = nil
+	}
+	return localized
+}
+
+// Field crea el error de un campo inválido. Implementa error para que los
+// validadores puedan devolverlo; Invalid lo convierte en un error de la API.
+func Field(field string, code Code) *FieldError {
+	return &FieldError{Field: field, Code: code}
+}
+
+// With agrega un dato al error del campo.
+func (f *FieldError) With(name string, value interface{}) *FieldError {
+	if f.Params == nil {
+		f.Params = map[string]interface{}{}
+	}
+	f.Params[name] = value
+	return f
+}
+
+// Error devuelve el campo y el mensaje en el idioma por defecto.
+func (f *FieldError) Error() string {
+	return f.Field + ": " + f.localize(models.DefaultLocale).Message
+}
+
+func (f FieldError) localize(locale string) FieldError {
+	f.Message = i18n.Format(translate(fieldCatalog[f.Code], locale), f.Params)
+	return f
+}
+
+// translate elige el mensaje en locale o, si falta, en el idioma por defecto.
+func translate(messages map[string]string, locale string) string {
+	if message, ok := messages[locale]; ok {
+		return message
+	}
+	return messages[models.DefaultLocale]
+}
+
+// Respond responde con el error en el idioma de la petición (ver
+// i18n.Locale), con el ID de la petición, y corta la cadena de handlers.
+func Respond(c *gin.Context, err *Error) {
+	locale := i18n.Locale(c)
+	body := err.localize(locale)
+	// RequestID lo coloca en el contexto middleware.RequestID
+	body.RequestID = c.GetString("request_id")
+	c.Header("Content-Language", locale)
+	c.AbortWithStatusJSON(err.Status(), Response{Error: body})
+}
+
+// Invalid convierte el error de validar un cuerpo JSON en ValidationFailed,
+// con el detalle de cada campo inválido. Acepta los errores de
+// gin.Context.ShouldBindJSON y los *FieldError de los validadores propios.
+func Invalid(err error) *Error {
+	return withDetails(New(ValidationFailed), err)
+}
+
+// InvalidQuery es como Invalid, para los parámetros de la query string.
+func InvalidQuery(err error) *Error {
+	return withDetails(New(InvalidQueryParams), err)
+}
+
+func withDetails(apiErr *Error, err error) *Error {
+	var fieldErr *FieldError
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &fieldErr):
+		apiErr.Details = []FieldError{*fieldErr}
+	case errors.As(err, &validationErrs):
+		for _, fe := range validationErrs {
+			apiErr.Details = append(apiErr.Details, *validationField(fe))
+		}
+	case errors.As(err, &typeErr):
+		apiErr.Details = []FieldError{*Field(typeErr.Field, InvalidType).With("expected", jsonType(typeErr.Type))}
+	case errors.As(err, &timeErr):
+		apiErr.Details = []FieldError{*Field("", InvalidDate).With("format", time.RFC3339)}
+	case errors.As(err, &syntaxErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		apiErr.Details = []FieldError{*Field("", InvalidJSON)}
+	}
+	return apiErr
+}
+
+// validationField traduce un error de las etiquetas binding de gin.
+func validationField(fe validator.FieldError) *FieldError {
+	// El namespace empieza con el nombre del struct: "RegisterRequest.email"
+	field := fe.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	switch fe.Tag() {
+	case "required":
+		return Field(field, Required)
+	case "email":
+		return Field(field, InvalidEmail)
+	}
+	return Field(field, InvalidFormat)
+}
+
+// jsonType nombra el tipo JSON que se esperaba en lugar del tipo de Go.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
+
+var registerOnce sync.Once
+
+// UseJSONFieldNames hace que los errores de validación de gin nombren los
+// campos como en el JSON ("grade_level") y no como en Go ("GradeLevel").
+func UseJSONFieldNames() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package apierror
+
+import (
+	"testing"
+
+	"explorax-backend/internal/models"
+)
+
+func TestCatalogsCoverSupportedLocales(t *testing.T) {
+	for code, definition := range errorCatalog {
+		if definition.status == 0 {
+			t.Errorf("%s has no status", code)
+		}
+		for _, locale := range models.SupportedLocales {
+			if definition.messages[locale] == "" {
+				t.Errorf("%s has no %s message", code, locale)
+			}
+		}
+	}
+	for code, messages := range fieldCatalog {
+		for _, locale := range models.SupportedLocales {
+			if messages[locale] == "" {
+				t.Errorf("field %s has no %s message", code, locale)
+			}
+		}
+	}
+}
+
+func TestLocalizeFillsParams(t *testing.T) {
+	err := Invalid(Field("steps[0].title", InvalidLength).With("min", 1).With("max", 100))
+	localized := err.localize("en")
+	if localized.Message != "Invalid data" {
+		t.Errorf("Expected top-level message, got %q", localized.Message)
+	}
+	if got := localized.Details[0].Message; got != "Must be between 1 and 100 characters long" {
+		t.Errorf("Expected filled field message, got %q", got)
+	}
+	if got := New(MissionNotFound).localize("fr").Message; got != "Misión no encontrada" {
+		t.Errorf("Expected default locale fallback, got %q", got)
+	}
+	if New("UNKNOWN").Status() != 500 {
+		t.Errorf("Expected unknown codes to be internal errors")
+	}
+}
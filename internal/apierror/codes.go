@@ -0,0 +1,455 @@
+package apierror
+
+import "net/http"
+
+// Códigos de los errores de la API.
+const (
+	// ValidationFailed indica un cuerpo inválido; Details dice qué campos
+	ValidationFailed   Code = "VALIDATION_FAILED"
+	InvalidQueryParams Code = "INVALID_QUERY"
+	// InvalidObjectID indica un ID mal formado; el parámetro field dice cuál
+	InvalidObjectID Code = "INVALID_OBJECT_ID"
+	InvalidCursor   Code = "INVALID_CURSOR"
+	Unauthenticated Code = "UNAUTHENTICATED"
+	Forbidden       Code = "FORBIDDEN"
+	// InternalError no da detalles; el error original queda en el log con
+	// el ID de la petición
+	InternalError Code = "INTERNAL_ERROR"
+
+	TokenMissing        Code = "TOKEN_MISSING"
+	TokenInvalid        Code = "TOKEN_INVALID"
+	SessionExpired      Code = "SESSION_EXPIRED"
+	InvalidCredentials  Code = "INVALID_CREDENTIALS"
+	RefreshTokenInvalid Code = "REFRESH_TOKEN_INVALID"
+	RefreshTokenReused  Code = "REFRESH_TOKEN_REUSED"
+	TenantNotFound      Code = "TENANT_NOT_FOUND"
+	TenantMismatch      Code = "TENANT_MISMATCH"
+	TenantExists        Code = "TENANT_EXISTS"
+
+	UserNotFound        Code = "USER_NOT_FOUND"
+	MissionNotFound     Code = "MISSION_NOT_FOUND"
+	StepNotFound        Code = "STEP_NOT_FOUND"
+	PathNotFound        Code = "PATH_NOT_FOUND"
+	AchievementNotFound Code = "ACHIEVEMENT_NOT_FOUND"
+	ClassroomNotFound   Code = "CLASSROOM_NOT_FOUND"
+	StudentNotFound     Code = "STUDENT_NOT_FOUND"
+	AssignmentNotFound  Code = "ASSIGNMENT_NOT_FOUND"
+	TranslationNotFound Code = "TRANSLATION_NOT_FOUND"
+	StudentNotEnrolled  Code = "STUDENT_NOT_ENROLLED"
+	NotEnrolled         Code = "NOT_ENROLLED"
+	InvalidJoinCode     Code = "INVALID_JOIN_CODE"
+	NotOnLeaderboard    Code = "NOT_ON_LEADERBOARD"
+
+	MissionNotStarted     Code = "MISSION_NOT_STARTED"
+	MissionAlreadyStarted Code = "MISSION_ALREADY_STARTED"
+	// InvalidTransition indica que la misión, en el estado del parámetro
+	// status, no admite la acción del parámetro action
+	InvalidTransition    Code = "INVALID_TRANSITION"
+	StepsIncomplete      Code = "STEPS_INCOMPLETE"
+	StepAlreadyCompleted Code = "STEP_ALREADY_COMPLETED"
+	QuizRequired         Code = "QUIZ_REQUIRED"
+	NoQuiz               Code = "NO_QUIZ"
+	// QuizAttemptsExhausted lleva en el parámetro max los intentos permitidos
+	QuizAttemptsExhausted Code = "QUIZ_ATTEMPTS_EXHAUSTED"
+	ProgressConflict      Code = "PROGRESS_CONFLICT"
+	// MissionLocked lleva en el parámetro prerequisites los que faltan
+	MissionLocked          Code = "MISSION_LOCKED"
+	MissionInUse           Code = "MISSION_IN_USE"
+	GlobalMissionForbidden Code = "GLOBAL_MISSION_FORBIDDEN"
+	AchievementCodeTaken   Code = "ACHIEVEMENT_CODE_TAKEN"
+	SourceLocale           Code = "SOURCE_LOCALE"
+	// UnsupportedLocale lleva en el parámetro allowed los idiomas disponibles
+	UnsupportedLocale Code = "UNSUPPORTED_LOCALE"
+)
+
+// Códigos de los campos inválidos de FieldError.
+const (
+	Required           Code = "REQUIRED"
+	InvalidJSON        Code = "INVALID_JSON"
+	InvalidType        Code = "INVALID_TYPE"
+	InvalidFormat      Code = "INVALID_FORMAT"
+	InvalidEmail       Code = "INVALID_EMAIL"
+	InvalidDate        Code = "INVALID_DATE"
+	OutOfRange         Code = "OUT_OF_RANGE"
+	TooSmall           Code = "TOO_SMALL"
+	InvalidLength      Code = "INVALID_LENGTH"
+	TooMany            Code = "TOO_MANY"
+	TooFew             Code = "TOO_FEW"
+	Duplicate          Code = "DUPLICATE"
+	NotFound           Code = "NOT_FOUND"
+	UnknownValue       Code = "UNKNOWN_VALUE"
+	InvalidCharacter   Code = "INVALID_CHARACTER"
+	InvalidTimezone    Code = "INVALID_TIMEZONE"
+	MustBeAfter        Code = "MUST_BE_AFTER"
+	MutuallyExclusive  Code = "MUTUALLY_EXCLUSIVE"
+	NoChanges          Code = "NO_CHANGES"
+	NoText             Code = "NO_TEXT"
+	InvalidRule        Code = "INVALID_RULE"
+	GlobalPrerequisite Code = "GLOBAL_PREREQUISITE"
+	PrerequisiteCycle  Code = "PREREQUISITE_CYCLE"
+	PrerequisiteOrder  Code = "PREREQUISITE_ORDER"
+)
+
+// definition es el estado HTTP y los mensajes, por idioma, de un código.
+type definition struct {
+	status   int
+	messages map[string]string
+}
+
+// errorCatalog tiene un mensaje por cada idioma de models.SupportedLocales.
+// Los {nombre} se reemplazan por los parámetros del error.
+var errorCatalog = map[Code]definition{
+	ValidationFailed: {http.StatusBadRequest, map[string]string{
+		"es": "Datos inválidos",
+		"en": "Invalid data",
+		"pt": "Dados inválidos",
+	}},
+	InvalidQueryParams: {http.StatusBadRequest, map[string]string{
+		"es": "Parámetros inválidos",
+		"en": "Invalid query parameters",
+		"pt": "Parâmetros inválidos",
+	}},
+	InvalidObjectID: {http.StatusBadRequest, map[string]string{
+		"es": "{field} no es un ID válido",
+		"en": "{field} is not a valid ID",
+		"pt": "{field} não é um ID válido",
+	}},
+	InvalidCursor: {http.StatusBadRequest, map[string]string{
+		"es": "Cursor inválido o de otra consulta",
+		"en": "Invalid cursor or cursor from another query",
+		"pt": "Cursor inválido ou de outra consulta",
+	}},
+	Unauthenticated: {http.StatusUnauthorized, map[string]string{
+		"es": "Usuario no autenticado",
+		"en": "User not authenticated",
+		"pt": "Usuário não autenticado",
+	}},
+	Forbidden: {http.StatusForbidden, map[string]string{
+		"es": "No tienes permisos para acceder a este recurso",
+		"en": "You do not have permission to access this resource",
+		"pt": "Você não tem permissão para acessar este recurso",
+	}},
+	InternalError: {http.StatusInternalServerError, map[string]string{
+		"es": "Ocurrió un error interno; inténtalo de nuevo",
+		"en": "An internal error occurred; please try again",
+		"pt": "Ocorreu um erro interno; tente novamente",
+	}},
+
+	TokenMissing: {http.StatusUnauthorized, map[string]string{
+		"es": "No se proporcionó token",
+		"en": "No token provided",
+		"pt": "Nenhum token fornecido",
+	}},
+	TokenInvalid: {http.StatusUnauthorized, map[string]string{
+		"es": "Token inválido",
+		"en": "Invalid token",
+		"pt": "Token inválido",
+	}},
+	SessionExpired: {http.StatusUnauthorized, map[string]string{
+		"es": "Sesión revocada o expirada",
+		"en": "Session revoked or expired",
+		"pt": "Sessão revogada ou expirada",
+	}},
+	InvalidCredentials: {http.StatusUnauthorized, map[string]string{
+		"es": "Email o contraseña incorrectos",
+		"en": "Incorrect email or password",
+		"pt": "Email ou senha incorretos",
+	}},
+	RefreshTokenInvalid: {http.StatusUnauthorized, map[string]string{
+		"es": "Refresh token inválido",
+		"en": "Invalid refresh token",
+		"pt": "Refresh token inválido",
+	}},
+	RefreshTokenReused: {http.StatusUnauthorized, map[string]string{
+		"es": "Refresh token reutilizado, la sesión fue revocada",
+		"en": "Refresh token reused; the session was revoked",
+		"pt": "Refresh token reutilizado; a sessão foi revogada",
+	}},
+	TenantNotFound: {http.StatusNotFound, map[string]string{
+		"es": "La organización no existe",
+		"en": "The organization does not exist",
+		"pt": "A organização não existe",
+	}},
+	TenantMismatch: {http.StatusForbidden, map[string]string{
+		"es": "El token pertenece a otra organización",
+		"en": "The token belongs to another organization",
+		"pt": "O token pertence a outra organização",
+	}},
+	TenantExists: {http.StatusConflict, map[string]string{
+		"es": "Ya existe una organización con ese ID",
+		"en": "An organization with that ID already exists",
+		"pt": "Já existe uma organização com esse ID",
+	}},
+
+	UserNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Usuario no encontrado",
+		"en": "User not found",
+		"pt": "Usuário não encontrado",
+	}},
+	MissionNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Misión no encontrada",
+		"en": "Mission not found",
+		"pt": "Missão não encontrada",
+	}},
+	StepNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Paso no encontrado",
+		"en": "Step not found",
+		"pt": "Etapa não encontrada",
+	}},
+	PathNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Ruta no encontrada",
+		"en": "Learning path not found",
+		"pt": "Trilha não encontrada",
+	}},
+	AchievementNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Logro no encontrado",
+		"en": "Achievement not found",
+		"pt": "Conquista não encontrada",
+	}},
+	ClassroomNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Clase no encontrada",
+		"en": "Classroom not found",
+		"pt": "Turma não encontrada",
+	}},
+	StudentNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Estudiante no encontrado",
+		"en": "Student not found",
+		"pt": "Aluno não encontrado",
+	}},
+	AssignmentNotFound: {http.StatusNotFound, map[string]string{
+		"es": "Asignación no encontrada",
+		"en": "Assignment not found",
+		"pt": "Tarefa não encontrada",
+	}},
+	TranslationNotFound: {http.StatusNotFound, map[string]string{
+		"es": "La misión no tiene traducción a ese idioma",
+		"en": "The mission has no translation to that language",
+		"pt": "A missão não tem tradução para esse idioma",
+	}},
+	StudentNotEnrolled: {http.StatusNotFound, map[string]string{
+		"es": "El estudiante no está inscrito en la clase",
+		"en": "The student is not enrolled in the classroom",
+		"pt": "O aluno não está matriculado na turma",
+	}},
+	NotEnrolled: {http.StatusNotFound, map[string]string{
+		"es": "No estás inscrito en la clase",
+		"en": "You are not enrolled in the classroom",
+		"pt": "Você não está matriculado na turma",
+	}},
+	InvalidJoinCode: {http.StatusNotFound, map[string]string{
+		"es": "Código de clase inválido",
+		"en": "Invalid classroom code",
+		"pt": "Código de turma inválido",
+	}},
+	NotOnLeaderboard: {http.StatusNotFound, map[string]string{
+		"es": "No apareces en este ranking: completa una misión en el periodo o vuelve a mostrar tu perfil",
+		"en": "You are not on this leaderboard: complete a mission in the period or show your profile again",
+		"pt": "Você não aparece neste ranking: conclua uma missão no período ou volte a mostrar seu perfil",
+	}},
+
+	MissionNotStarted: {http.StatusBadRequest, map[string]string{
+		"es": "No has iniciado esta misión",
+		"en": "You have not started this mission",
+		"pt": "Você não iniciou esta missão",
+	}},
+	MissionAlreadyStarted: {http.StatusConflict, map[string]string{
+		"es": "Ya iniciaste esta misión",
+		"en": "You already started this mission",
+		"pt": "Você já iniciou esta missão",
+	}},
+	InvalidTransition: {http.StatusConflict, map[string]string{
+		"es": "La misión está en estado \"{status}\" y no admite esta acción",
+		"en": "The mission is \"{status}\" and does not allow this action",
+		"pt": "A missão está no estado \"{status}\" e não permite esta ação",
+	}},
+	StepsIncomplete: {http.StatusConflict, map[string]string{
+		"es": "Faltan pasos obligatorios por completar ({done} de {required})",
+		"en": "Required steps are still pending ({done} of {required} completed)",
+		"pt": "Ainda faltam etapas obrigatórias ({done} de {required} concluídas)",
+	}},
+	StepAlreadyCompleted: {http.StatusConflict, map[string]string{
+		"es": "Ya completaste este paso",
+		"en": "You already completed this step",
+		"pt": "Você já concluiu esta etapa",
+	}},
+	QuizRequired: {http.StatusConflict, map[string]string{
+		"es": "Esta misión se completa aprobando su cuestionario",
+		"en": "This mission is completed by passing its quiz",
+		"pt": "Esta missão é concluída ao passar no questionário",
+	}},
+	NoQuiz: {http.StatusBadRequest, map[string]string{
+		"es": "La misión no tiene cuestionario",
+		"en": "The mission has no quiz",
+		"pt": "A missão não tem questionário",
+	}},
+	QuizAttemptsExhausted: {http.StatusConflict, map[string]string{
+		"es": "Ya usaste los {max} intentos de este cuestionario",
+		"en": "You already used the {max} attempts of this quiz",
+		"pt": "Você já usou as {max} tentativas deste questionário",
+	}},
+	ProgressConflict: {http.StatusConflict, map[string]string{
+		"es": "El progreso cambió mientras se procesaba la solicitud, inténtalo de nuevo",
+		"en": "The progress changed while the request was being processed; please try again",
+		"pt": "O progresso mudou enquanto a solicitação era processada; tente novamente",
+	}},
+	MissionLocked: {http.StatusConflict, map[string]string{
+		"es": "La misión está bloqueada: primero completa sus prerrequisitos",
+		"en": "The mission is locked: complete its prerequisites first",
+		"pt": "A missão está bloqueada: primeiro conclua seus pré-requisitos",
+	}},
+	MissionInUse: {http.StatusConflict, map[string]string{
+		"es": "La misión tiene progreso registrado; archívala en lugar de eliminarla",
+		"en": "The mission has recorded progress; archive it instead of deleting it",
+		"pt": "A missão tem progresso registrado; arquive-a em vez de excluí-la",
+	}},
+	GlobalMissionForbidden: {http.StatusForbidden, map[string]string{
+		"es": "Solo la organización por defecto puede crear misiones globales",
+		"en": "Only the default organization can create global missions",
+		"pt": "Somente a organização padrão pode criar missões globais",
+	}},
+	AchievementCodeTaken: {http.StatusConflict, map[string]string{
+		"es": "Ya existe un logro con ese código",
+		"en": "An achievement with that code already exists",
+		"pt": "Já existe uma conquista com esse código",
+	}},
+	SourceLocale: {http.StatusBadRequest, map[string]string{
+		"es": "La misión está escrita en ese idioma; edita la misión en lugar de traducirla",
+		"en": "The mission is written in that language; edit the mission instead of translating it",
+		"pt": "A missão está escrita nesse idioma; edite a missão em vez de traduzi-la",
+	}},
+	UnsupportedLocale: {http.StatusBadRequest, map[string]string{
+		"es": "Idioma no soportado; los disponibles son: {allowed}",
+		"en": "Unsupported language; the available ones are: {allowed}",
+		"pt": "Idioma não suportado; os disponíveis são: {allowed}",
+	}},
+}
+
+// fieldCatalog tiene los mensajes de los campos inválidos, que se muestran
+// junto al nombre del campo.
+var fieldCatalog = map[Code]map[string]string{
+	Required: {
+		"es": "Es obligatorio",
+		"en": "Is required",
+		"pt": "É obrigatório",
+	},
+	InvalidJSON: {
+		"es": "El cuerpo no es un JSON válido",
+		"en": "The body is not valid JSON",
+		"pt": "O corpo não é um JSON válido",
+	},
+	InvalidType: {
+		"es": "Debe ser de tipo {expected}",
+		"en": "Must be of type {expected}",
+		"pt": "Deve ser do tipo {expected}",
+	},
+	InvalidFormat: {
+		"es": "No tiene un formato válido",
+		"en": "Has an invalid format",
+		"pt": "Não tem um formato válido",
+	},
+	InvalidEmail: {
+		"es": "No es un email válido",
+		"en": "Is not a valid email",
+		"pt": "Não é um email válido",
+	},
+	InvalidDate: {
+		"es": "Debe ser una fecha con el formato {format}",
+		"en": "Must be a date in the {format} format",
+		"pt": "Deve ser uma data no formato {format}",
+	},
+	InvalidObjectID: {
+		"es": "No es un ID válido",
+		"en": "Is not a valid ID",
+		"pt": "Não é um ID válido",
+	},
+	OutOfRange: {
+		"es": "Debe estar entre {min} y {max}",
+		"en": "Must be between {min} and {max}",
+		"pt": "Deve estar entre {min} e {max}",
+	},
+	TooSmall: {
+		"es": "Debe ser como mínimo {min}",
+		"en": "Must be at least {min}",
+		"pt": "Deve ser no mínimo {min}",
+	},
+	InvalidLength: {
+		"es": "Debe tener entre {min} y {max} caracteres",
+		"en": "Must be between {min} and {max} characters long",
+		"pt": "Deve ter entre {min} e {max} caracteres",
+	},
+	TooMany: {
+		"es": "Admite hasta {max} elementos",
+		"en": "Allows at most {max} items",
+		"pt": "Permite no máximo {max} itens",
+	},
+	TooFew: {
+		"es": "Necesita al menos {min} elementos",
+		"en": "Needs at least {min} items",
+		"pt": "Precisa de pelo menos {min} itens",
+	},
+	Duplicate: {
+		"es": "Está repetido",
+		"en": "Is duplicated",
+		"pt": "Está repetido",
+	},
+	NotFound: {
+		"es": "No existe",
+		"en": "Does not exist",
+		"pt": "Não existe",
+	},
+	UnknownValue: {
+		"es": "Debe ser uno de: {allowed}",
+		"en": "Must be one of: {allowed}",
+		"pt": "Deve ser um de: {allowed}",
+	},
+	InvalidCharacter: {
+		"es": "No puede contener {character}",
+		"en": "Cannot contain {character}",
+		"pt": "Não pode conter {character}",
+	},
+	InvalidTimezone: {
+		"es": "No es una zona horaria IANA conocida",
+		"en": "Is not a known IANA time zone",
+		"pt": "Não é um fuso horário IANA conhecido",
+	},
+	MustBeAfter: {
+		"es": "Debe ser posterior a {other}",
+		"en": "Must be after {other}",
+		"pt": "Deve ser posterior a {other}",
+	},
+	MutuallyExclusive: {
+		"es": "Indica este campo o {other}, uno solo",
+		"en": "Give either this field or {other}, not both",
+		"pt": "Informe este campo ou {other}, apenas um",
+	},
+	NoChanges: {
+		"es": "No se indicó ningún campo a modificar",
+		"en": "No field to update was given",
+		"pt": "Nenhum campo para alterar foi informado",
+	},
+	NoText: {
+		"es": "No se envió ningún texto",
+		"en": "No text was given",
+		"pt": "Nenhum texto foi enviado",
+	},
+	InvalidRule: {
+		"es": "Regla desconocida o umbral no positivo",
+		"en": "Unknown rule or non-positive threshold",
+		"pt": "Regra desconhecida ou limite não positivo",
+	},
+	GlobalPrerequisite: {
+		"es": "Una misión global solo puede depender de misiones globales",
+		"en": "A global mission can only depend on global missions",
+		"pt": "Uma missão global só pode depender de missões globais",
+	},
+	PrerequisiteCycle: {
+		"es": "Los prerrequisitos forman un ciclo: {cycle}",
+		"en": "The prerequisites form a cycle: {cycle}",
+		"pt": "Os pré-requisitos formam um ciclo: {cycle}",
+	},
+	PrerequisiteOrder: {
+		"es": "Aparece antes que su prerrequisito «{prerequisite}»",
+		"en": "Appears before its prerequisite “{prerequisite}”",
+		"pt": "Aparece antes do seu pré-requisito «{prerequisite}»",
+	},
+}
@@ -0,0 +1,939 @@
+// /internal/database/memory.go
+package database
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/textnorm"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryStore implementa Store en memoria. Reproduce la semántica de las
+// consultas y agregaciones de MongoStore, por lo que sirve para pruebas
+// herméticas y para ejecutar la API en modo desarrollo sin MongoDB.
+// Los datos se pierden al terminar el proceso.
+type MemoryStore struct {
+	*memoryData
+	// tenant es la organización de la vista; ver ForTenant
+	tenant string
+}
+
+// memoryData son los datos que comparten las vistas de todas las organizaciones.
+type memoryData struct {
+	mu       sync.RWMutex
+	users    []models.User
+	sessions []models.Session
+	missions []models.Mission
+	progress []models.MissionProgress
+	attempts []models.QuizAttempt
+
+	achievements     []models.Achievement
+	userAchievements []models.UserAchievement
+	leaderboard      []leaderboardDoc
+	classrooms       []models.Classroom
+	assignments      []models.Assignment
+	paths            []models.LearningPath
+	tenants          []models.Tenant
+}
+
+// NewMemoryStore crea un Store en memoria vacío, de la organización
+// DefaultTenantID.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{memoryData: &memoryData{}, tenant: models.DefaultTenantID}
+}
+
+// ForTenant devuelve una vista de los mismos datos limitada a la organización
+// tenantID.
+func (s *MemoryStore) ForTenant(tenantID string) Store {
+	return &MemoryStore{memoryData: s.memoryData, tenant: tenantID}
+}
+
+// visible indica si la misión es de la organización o es global.
+func (s *MemoryStore) visible(m models.Mission) bool {
+	return m.TenantID == s.tenant || m.Global
+}
+
+// InsertUser inserta un nuevo usuario.
+func (s *MemoryStore) InsertUser(user models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.TenantID = s.tenant
+	for _, u := range s.users {
+		if u.ID == user.ID {
+			return ErrDuplicate
+		}
+	}
+	s.users = append(s.users, user)
+	return nil
+}
+
+// FindUserByEmail busca un usuario por email.
+func (s *MemoryStore) FindUserByEmail(email string) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, u := range s.users {
+		if u.TenantID == s.tenant && u.Email == email {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// FindUserByID busca un usuario por su ID.
+func (s *MemoryStore) FindUserByID(id primitive.ObjectID) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, u := range s.users {
+		if u.ID == id && u.TenantID == s.tenant {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// UpdateUser actualiza las preferencias del usuario.
+func (s *MemoryStore) UpdateUser(id primitive.ObjectID, update UserUpdate) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.users {
+		u := &s.users[i]
+		if u.ID != id || u.TenantID != s.tenant {
+			continue
+		}
+		if update.Timezone != nil {
+			u.Timezone = *update.Timezone
+		}
+		if update.BirthDate != nil {
+			birthDate := *update.BirthDate
+			u.BirthDate = &birthDate
+		}
+		if update.DisplayName != nil {
+			u.DisplayName = *update.DisplayName
+		}
+		if update.Avatar != nil {
+			u.Avatar = *update.Avatar
+		}
+		if update.Locale != nil {
+			u.Locale = *update.Locale
+		}
+		if update.HideFromLeaderboard != nil {
+			u.HideFromLeaderboard = *update.HideFromLeaderboard
+			for j := range s.leaderboard {
+				if s.leaderboard[j].TenantID == s.tenant && s.leaderboard[j].UserID == id {
+					s.leaderboard[j].Hidden = u.HideFromLeaderboard
+				}
+			}
+		}
+		updated := *u
+		return &updated, nil
+	}
+	return nil, ErrNotFound
+}
+
+// AddUserXP incrementa la experiencia del usuario.
+func (s *MemoryStore) AddUserXP(id primitive.ObjectID, xp int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.users {
+		if s.users[i].ID == id && s.users[i].TenantID == s.tenant {
+			s.users[i].XP += xp
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// UpdateUserStreak reemplaza la racha diaria del usuario.
+func (s *MemoryStore) UpdateUserStreak(id primitive.ObjectID, streak models.Streak) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.users {
+		if s.users[i].ID == id && s.users[i].TenantID == s.tenant {
+			streak.FrozenDays = append([]string(nil), streak.FrozenDays...)
+			s.users[i].Streak = streak
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// InsertSession inserta una nueva sesión.
+func (s *MemoryStore) InsertSession(session models.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	session.TenantID = s.tenant
+	for _, existing := range s.sessions {
+		if existing.ID == session.ID {
+			return ErrDuplicate
+		}
+	}
+	s.sessions = append(s.sessions, session)
+	return nil
+}
+
+// FindSessionByRefreshHash busca la sesión cuyo refresh token vigente o
+// inmediatamente anterior coincide con el hash dado.
+func (s *MemoryStore) FindSessionByRefreshHash(hash string) (*models.Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, session := range s.sessions {
+		if session.TenantID != s.tenant {
+			continue
+		}
+		if session.RefreshTokenHash == hash || session.PreviousRefreshTokenHash == hash {
+			return &session, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// RotateSession reemplaza el refresh token de una sesión activa si el hash
+// vigente sigue siendo oldHash.
+func (s *MemoryStore) RotateSession(id primitive.ObjectID, oldHash, newHash string, usedAt, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.sessions {
+		session := &s.sessions[i]
+		if session.ID != id || session.TenantID != s.tenant || session.RefreshTokenHash != oldHash || session.RevokedAt != nil {
+			continue
+		}
+		session.PreviousRefreshTokenHash = oldHash
+		session.RefreshTokenHash = newHash
+		session.LastUsedAt = usedAt
+		session.ExpiresAt = expiresAt
+		return nil
+	}
+	return ErrNotFound
+}
+
+// RevokeSession marca una sesión como revocada.
+func (s *MemoryStore) RevokeSession(id primitive.ObjectID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.sessions {
+		if s.sessions[i].ID == id && s.sessions[i].TenantID == s.tenant && s.sessions[i].RevokedAt == nil {
+			s.sessions[i].RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// RevokeUserSessions revoca todas las sesiones activas de un usuario.
+func (s *MemoryStore) RevokeUserSessions(userID primitive.ObjectID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.sessions {
+		if s.sessions[i].UserID == userID && s.sessions[i].TenantID == s.tenant && s.sessions[i].RevokedAt == nil {
+			s.sessions[i].RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// IsSessionActive indica si una sesión existe, no ha sido revocada y no ha expirado.
+func (s *MemoryStore) IsSessionActive(id primitive.ObjectID, now time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, session := range s.sessions {
+		if session.ID == id && session.TenantID == s.tenant {
+			return session.Active(now), nil
+		}
+	}
+	return false, nil
+}
+
+// InsertMission inserta una misión.
+func (s *MemoryStore) InsertMission(mission models.Mission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if mission.ID.IsZero() {
+		mission.ID = primitive.NewObjectID()
+	}
+	mission.TenantID = s.tenant
+	for _, m := range s.missions {
+		if m.ID == mission.ID {
+			return ErrDuplicate
+		}
+	}
+	s.missions = append(s.missions, mission)
+	return nil
+}
+
+// GetAllMissions obtiene las misiones de la organización y las globales en
+// orden de inserción, omitiendo las archivadas salvo que includeArchived sea
+// true.
+func (s *MemoryStore) GetAllMissions(includeArchived bool) ([]models.Mission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	missions := []models.Mission{}
+	for _, m := range s.missions {
+		if s.visible(m) && (includeArchived || !m.Archived) {
+			missions = append(missions, m)
+		}
+	}
+	return missions, nil
+}
+
+// ListMissions devuelve una página de las misiones por fecha de creación.
+func (s *MemoryStore) ListMissions(list MissionList) ([]models.Mission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	missions := []models.Mission{}
+	for _, m := range s.missions {
+		switch {
+		case list.OwnOnly && m.TenantID != s.tenant, !s.visible(m):
+		case m.Archived && !list.IncludeArchived:
+		case list.IDs != nil && !slices.Contains(list.IDs, m.ID):
+		default:
+			missions = append(missions, m)
+		}
+	}
+	return listPage(missions, ListQuery[models.Mission]{After: list.After, Limit: list.Limit}, func(a, b models.Mission) bool {
+		return dateThenID(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+// FindMissions filtra, ordena y pagina el catálogo como MongoStore.FindMissions.
+func (s *MemoryStore) FindMissions(query MissionQuery) (MissionPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids map[primitive.ObjectID]bool
+	if query.IDs != nil {
+		ids = make(map[primitive.ObjectID]bool, len(query.IDs))
+		for _, id := range query.IDs {
+			ids[id] = true
+		}
+	}
+	matches := func(m models.Mission) bool {
+		switch {
+		case !s.visible(m) || m.Archived:
+			return false
+		case query.Category != "" && m.Category != query.Category:
+			return false
+		case query.Difficulty > 0 && m.Difficulty != query.Difficulty:
+			return false
+		case query.GradeLevel > 0 && m.GradeLevel != query.GradeLevel:
+			return false
+		case query.MaxMinutes > 0 && (m.EstimatedMinutes <= 0 || m.EstimatedMinutes > query.MaxMinutes):
+			return false
+		case ids != nil && !ids[m.ID]:
+			return false
+		}
+		for _, tag := range query.Tags {
+			if !containsString(m.Tags, tag) {
+				return false
+			}
+		}
+		return true
+	}
+
+	missions := []models.Mission{}
+	facets := models.NewMissionFacets()
+	for _, m := range s.missions {
+		if matches(m) {
+			missions = append(missions, m)
+			facets.Add(m)
+		}
+	}
+
+	// compare ordena por el campo pedido; a igual valor, por fecha de creación
+	compare := func(a, b models.Mission) int {
+		switch query.Sort {
+		case SortByTitle:
+			return strings.Compare(a.Title, b.Title)
+		case SortByDifficulty:
+			return a.Difficulty - b.Difficulty
+		case SortByGradeLevel:
+			return a.GradeLevel - b.GradeLevel
+		case SortByDuration:
+			return a.EstimatedMinutes - b.EstimatedMinutes
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	}
+	less := func(a, b models.Mission) bool {
+		c := compare(a, b)
+		if query.SortDescending {
+			c = -c
+		}
+		if c == 0 {
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if c == 0 {
+			return a.ID.Hex() < b.ID.Hex()
+		}
+		return c < 0
+	}
+	sort.Slice(missions, func(i, j int) bool { return less(missions[i], missions[j]) })
+	page := window(seek(missions, query.After, less), query.Limit)
+	return MissionPage{Missions: page, Total: len(missions), Facets: facets}, nil
+}
+
+// SearchMissions imita el índice de texto de MongoStore: cada palabra del
+// texto que coincide con una de la búsqueda, sin acentos y sin plural, suma
+// el peso de su campo en searchWeights.
+func (s *MemoryStore) SearchMissions(search MissionSearch) ([]MissionMatch, error) {
+	stems := map[string]bool{}
+	for _, term := range textnorm.Terms(search.Text) {
+		stems[textnorm.Stem(term)] = true
+	}
+	if len(stems) == 0 {
+		return []MissionMatch{}, nil
+	}
+	score := func(field, text string) float64 {
+		var total float64
+		for _, term := range textnorm.Terms(text) {
+			if stems[textnorm.Stem(term)] {
+				total += float64(searchWeights[field])
+			}
+		}
+		return total
+	}
+
+	s.mu.RLock()
+	matches := []MissionMatch{}
+	for _, m := range s.missions {
+		if !s.visible(m) || m.Archived {
+			continue
+		}
+		total := score("title", m.Title) + score("description", m.Description)
+		for _, tag := range m.Tags {
+			total += score("tags", tag)
+		}
+		if total > 0 {
+			matches = append(matches, MissionMatch{Mission: m, Score: total})
+		}
+	}
+	s.mu.RUnlock()
+
+	less := func(a, b MissionMatch) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Mission.ID.Hex() < b.Mission.ID.Hex()
+	}
+	sort.Slice(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+	return window(seek(matches, search.After, less), search.Limit), nil
+}
+
+// seek devuelve los elementos de items, ordenados según less, que van
+// después de after, como keysetAfter en MongoStore; sin after, todos.
+func seek[T any](items []T, after *T, less func(a, b T) bool) []T {
+	if after == nil {
+		return items
+	}
+	i := sort.Search(len(items), func(i int) bool { return less(*after, items[i]) })
+	return items[i:]
+}
+
+// window devuelve hasta limit elementos de items si limit es mayor que 0,
+// como SetLimit en MongoStore.
+func window[T any](items []T, limit int) []T {
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// listPage ordena items según less y devuelve la página de query, como
+// listOptions y listAfter en MongoStore.
+func listPage[T any](items []T, query ListQuery[T], less func(a, b T) bool) []T {
+	sort.Slice(items, func(i, j int) bool { return less(items[i], items[j]) })
+	return window(seek(items, query.After, less), query.Limit)
+}
+
+// dateThenID compara por fecha y luego por ID, el orden de listOptions.
+func dateThenID(a, b time.Time, idA, idB primitive.ObjectID) bool {
+	if !a.Equal(b) {
+		return a.Before(b)
+	}
+	return bytes.Compare(idA[:], idB[:]) < 0
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateMission aplica los cambios indicados y devuelve la misión actualizada.
+func (s *MemoryStore) UpdateMission(id primitive.ObjectID, update MissionUpdate, at time.Time) (*models.Mission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.missions {
+		m := &s.missions[i]
+		if m.ID != id || m.TenantID != s.tenant {
+			continue
+		}
+		if update.Title != nil {
+			m.Title = *update.Title
+		}
+		if update.Description != nil {
+			m.Description = *update.Description
+		}
+		if update.Steps != nil {
+			m.Steps = append([]models.MissionStep(nil), (*update.Steps)...)
+		}
+		if update.Quiz != nil {
+			quiz := *update.Quiz
+			m.Quiz = &quiz
+		} else if update.RemoveQuiz {
+			m.Quiz = nil
+		}
+		if update.XPReward != nil {
+			m.XPReward = *update.XPReward
+		}
+		if update.DifficultyMultiplier != nil {
+			m.DifficultyMultiplier = *update.DifficultyMultiplier
+		}
+		if update.Prerequisites != nil {
+			m.Prerequisites = nil
+			if len(*update.Prerequisites) > 0 {
+				m.Prerequisites = append([]primitive.ObjectID(nil), (*update.Prerequisites)...)
+			}
+		}
+		if update.Category != nil {
+			m.Category = *update.Category
+		}
+		if update.Tags != nil {
+			m.Tags = nil
+			if len(*update.Tags) > 0 {
+				m.Tags = append([]string(nil), (*update.Tags)...)
+			}
+		}
+		if update.Difficulty != nil {
+			m.Difficulty = *update.Difficulty
+		}
+		if update.GradeLevel != nil {
+			m.GradeLevel = *update.GradeLevel
+		}
+		if update.EstimatedMinutes != nil {
+			m.EstimatedMinutes = *update.EstimatedMinutes
+		}
+		if len(update.Translations) > 0 {
+			// Se arma un mapa nuevo para no modificar las copias ya devueltas
+			translations := make(map[string]models.MissionTranslation, len(m.Translations))
+			for locale, translation := range m.Translations {
+				translations[locale] = translation
+			}
+			for locale, translation := range update.Translations {
+				if translation == nil {
+					delete(translations, locale)
+					continue
+				}
+				t := *translation
+				t.Steps = append([]models.StepTranslation(nil), t.Steps...)
+				translations[locale] = t
+			}
+			m.Translations = translations
+			if len(translations) == 0 {
+				m.Translations = nil
+			}
+		}
+		if update.Archived != nil {
+			m.Archived = *update.Archived
+			m.ArchivedAt = nil
+			if m.Archived {
+				archivedAt := at
+				m.ArchivedAt = &archivedAt
+			}
+		}
+		m.UpdatedAt = at
+		updated := *m
+		return &updated, nil
+	}
+	return nil, ErrNotFound
+}
+
+// DeleteMission elimina definitivamente una misión.
+func (s *MemoryStore) DeleteMission(id primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, m := range s.missions {
+		if m.ID == id && m.TenantID == s.tenant {
+			s.missions = append(s.missions[:i], s.missions[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// HasMissionProgress indica si algún usuario, de cualquier organización, ha
+// iniciado la misión.
+func (s *MemoryStore) HasMissionProgress(missionID primitive.ObjectID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.progress {
+		if p.MissionID == missionID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetMissionByID busca una misión por su ID.
+func (s *MemoryStore) GetMissionByID(id primitive.ObjectID) (*models.Mission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	mission, err := s.findMission(id)
+	if err != nil || !s.visible(*mission) {
+		return nil, ErrNotFound
+	}
+	return mission, nil
+}
+
+// findMission busca una misión de cualquier organización, como el $lookup de
+// MongoStore.
+func (s *MemoryStore) findMission(id primitive.ObjectID) (*models.Mission, error) {
+	for _, m := range s.missions {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// InsertMissionProgress inserta un nuevo documento de progreso de misión.
+func (s *MemoryStore) InsertMissionProgress(progress models.MissionProgress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if progress.ID.IsZero() {
+		progress.ID = primitive.NewObjectID()
+	}
+	progress.TenantID = s.tenant
+	for _, p := range s.progress {
+		// Equivale al índice único (userId, missionId) de MongoStore
+		if p.ID == progress.ID || (p.UserID == progress.UserID && p.MissionID == progress.MissionID) {
+			return ErrDuplicate
+		}
+	}
+	s.progress = append(s.progress, progress)
+	return nil
+}
+
+// FindMissionProgress obtiene el progreso de un usuario en una misión.
+func (s *MemoryStore) FindMissionProgress(userID, missionID primitive.ObjectID) (*models.MissionProgress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.progress {
+		if p.TenantID == s.tenant && p.UserID == userID && p.MissionID == missionID {
+			return &p, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// UpdateMissionProgress guarda el progreso tras una transición de estado,
+// siempre que el documento siga en el estado from.
+func (s *MemoryStore) UpdateMissionProgress(progress models.MissionProgress, from models.ProgressStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.progress {
+		if s.progress[i].ID == progress.ID && s.progress[i].TenantID == s.tenant && s.progress[i].Status == from {
+			progress.TenantID = s.tenant
+			s.progress[i] = progress
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// CreditPendingXP suma al usuario la XP pendiente de sus misiones completadas.
+// Con el bloqueo tomado, la suma y la marca del progreso ocurren juntas.
+func (s *MemoryStore) CreditPendingXP(userID primitive.ObjectID) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var user *models.User
+	for i := range s.users {
+		if s.users[i].ID == userID && s.users[i].TenantID == s.tenant {
+			user = &s.users[i]
+		}
+	}
+	credited := 0
+	for i := range s.progress {
+		p := &s.progress[i]
+		if p.UserID != userID || p.TenantID != s.tenant || !p.XPPending {
+			continue
+		}
+		if user == nil {
+			return credited, ErrNotFound
+		}
+		user.XP += p.XPAwarded
+		credited += p.XPAwarded
+		p.XPPending = false
+	}
+	return credited, nil
+}
+
+// CompleteMissionStep agrega el paso a los pasos completados si la misión
+// está iniciada y el paso no estaba completado.
+func (s *MemoryStore) CompleteMissionStep(id primitive.ObjectID, step models.StepProgress) (*models.MissionProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.progress {
+		p := &s.progress[i]
+		if p.ID != id || p.TenantID != s.tenant {
+			continue
+		}
+		if p.Status != models.ProgressStarted || p.HasCompletedStep(step.StepID) {
+			return nil, ErrNotFound
+		}
+		// Copiar la lista para no compartirla con progresos ya devueltos
+		steps := make([]models.StepProgress, 0, len(p.Steps)+1)
+		p.Steps = append(append(steps, p.Steps...), step)
+		updated := *p
+		return &updated, nil
+	}
+	return nil, ErrNotFound
+}
+
+// GetProgressForMissions obtiene el progreso del usuario en las misiones
+// indicadas.
+func (s *MemoryStore) GetProgressForMissions(userID primitive.ObjectID, missionIDs []primitive.ObjectID) ([]models.MissionProgress, error) {
+	return s.filterProgress(func(p models.MissionProgress) bool {
+		return p.UserID == userID && slices.Contains(missionIDs, p.MissionID)
+	}), nil
+}
+
+// GetProgressSummary resume el progreso del usuario como
+// MongoStore.GetProgressSummary.
+func (s *MemoryStore) GetProgressSummary(userID primitive.ObjectID) (ProgressSummary, error) {
+	var summary ProgressSummary
+	for _, p := range s.filterProgress(func(p models.MissionProgress) bool { return p.UserID == userID }) {
+		summary.Started++
+		switch p.Status {
+		case models.ProgressStarted, models.ProgressPaused:
+			summary.Active++
+		case models.ProgressCompleted:
+			if duration := p.CompletedDuration(); summary.Completed == 0 || duration < summary.FastestCompletion {
+				summary.FastestCompletion = duration
+			}
+			if p.EndDate.After(summary.LastCompletedAt) {
+				summary.LastCompletedAt = p.EndDate
+			}
+			summary.Completed++
+		}
+	}
+	return summary, nil
+}
+
+// CountCompletionsByDay cuenta las misiones completadas por el día de su
+// fecha de fin en la zona horaria loc.
+func (s *MemoryStore) CountCompletionsByDay(userID primitive.ObjectID, since time.Time, loc *time.Location) (map[string]int, error) {
+	days := map[string]int{}
+	for _, p := range s.filterProgress(func(p models.MissionProgress) bool {
+		return p.UserID == userID && p.Status == models.ProgressCompleted && !p.EndDate.Before(since)
+	}) {
+		days[models.DayKey(p.EndDate, loc)]++
+	}
+	return days, nil
+}
+
+// ListMissionProgress ordena y pagina el progreso como
+// MongoStore.ListMissionProgress; sin fecha de fin se ordena primero.
+func (s *MemoryStore) ListMissionProgress(query ProgressQuery) ([]ProgressWithMission, error) {
+	progress := s.filterProgress(func(p models.MissionProgress) bool {
+		return p.UserID == query.UserID && (len(query.Statuses) == 0 || slices.Contains(query.Statuses, p.Status))
+	})
+	date := func(p models.MissionProgress) time.Time {
+		if query.Sort == SortByEndDate {
+			return p.EndDate
+		}
+		return p.StartDate
+	}
+	less := func(a, b models.MissionProgress) bool {
+		c := date(a).Compare(date(b))
+		if query.SortDescending {
+			c = -c
+		}
+		if c == 0 {
+			return a.ID.Hex() < b.ID.Hex()
+		}
+		return c < 0
+	}
+	sort.Slice(progress, func(i, j int) bool { return less(progress[i], progress[j]) })
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	page := []ProgressWithMission{}
+	for _, p := range window(seek(progress, query.After, less), query.Limit) {
+		entry := ProgressWithMission{Progress: p}
+		if query.WithMission {
+			if mission, err := s.findMission(p.MissionID); err == nil {
+				entry.Mission = mission
+			}
+		}
+		page = append(page, entry)
+	}
+	return page, nil
+}
+
+func (s *MemoryStore) filterProgress(match func(models.MissionProgress) bool) []models.MissionProgress {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []models.MissionProgress{}
+	for _, p := range s.progress {
+		if p.TenantID == s.tenant && match(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// GetUserStatistics retorna estadísticas para un usuario, como total de
+// misiones completadas y duración promedio en milisegundos.
+func (s *MemoryStore) GetUserStatistics(userID primitive.ObjectID) (bson.M, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var totalCompleted int64
+	var durationSum float64
+	var durationCount int
+	for _, p := range s.progress {
+		if p.TenantID != s.tenant || p.UserID != userID || p.Status != models.ProgressCompleted {
+			continue
+		}
+		totalCompleted++
+		// Como en $avg, los documentos sin fecha final no cuentan para el promedio
+		if p.ActiveDurationMs != 0 || !p.EndDate.IsZero() {
+			durationSum += completedDurationMs(p)
+			durationCount++
+		}
+	}
+
+	var avgDuration any = 0
+	if totalCompleted > 0 {
+		avgDuration = nil
+		if durationCount > 0 {
+			avgDuration = durationSum / float64(durationCount)
+		}
+	}
+
+	// El avance solo considera misiones distintas que sigan disponibles
+	completedAvailable := map[primitive.ObjectID]struct{}{}
+	for _, p := range s.progress {
+		if p.TenantID != s.tenant || p.UserID != userID || p.Status != models.ProgressCompleted {
+			continue
+		}
+		if mission, err := s.findMission(p.MissionID); err == nil && !mission.Archived {
+			completedAvailable[p.MissionID] = struct{}{}
+		}
+	}
+	totalMissions := 0
+	for _, m := range s.missions {
+		if s.visible(m) && !m.Archived {
+			totalMissions++
+		}
+	}
+
+	progressPercentage := 0.0
+	if totalMissions > 0 {
+		progressPercentage = (float64(len(completedAvailable)) / float64(totalMissions)) * 100
+	}
+
+	return bson.M{
+		"totalCompleted":     totalCompleted,
+		"averageDuration":    avgDuration,
+		"progressPercentage": progressPercentage,
+	}, nil
+}
+
+// GetMissionsOverview calcula la misión más popular y el tiempo promedio de
+// finalización por misión, ignorando las misiones que ya no existen.
+func (s *MemoryStore) GetMissionsOverview() (bson.M, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type missionAgg struct {
+		count         int32
+		durationSum   float64
+		durationCount int
+	}
+	aggs := map[primitive.ObjectID]*missionAgg{}
+	var order []primitive.ObjectID
+	for _, p := range s.progress {
+		if p.TenantID != s.tenant || p.Status != models.ProgressCompleted {
+			continue
+		}
+		agg, ok := aggs[p.MissionID]
+		if !ok {
+			agg = &missionAgg{}
+			aggs[p.MissionID] = agg
+			order = append(order, p.MissionID)
+		}
+		agg.count++
+		if p.ActiveDurationMs != 0 || !p.EndDate.IsZero() {
+			agg.durationSum += completedDurationMs(p)
+			agg.durationCount++
+		}
+	}
+
+	// Como en la agregación, la misión más popular se elige antes del $lookup:
+	// si ya no existe, el overview no reporta ninguna.
+	var mostPopular interface{}
+	var popularID primitive.ObjectID
+	var popularCount int32
+	for _, missionID := range order {
+		if aggs[missionID].count > popularCount {
+			popularID, popularCount = missionID, aggs[missionID].count
+		}
+	}
+
+	avgResults := []bson.M{}
+	for _, missionID := range order {
+		mission, err := s.findMission(missionID)
+		if err != nil {
+			continue
+		}
+		// Como la proyección de MongoStore, solo el ID y el título
+		missionDoc := bson.M{"_id": mission.ID, "title": mission.Title}
+		agg := aggs[missionID]
+
+		var avgDuration interface{}
+		if agg.durationCount > 0 {
+			avgDuration = agg.durationSum / float64(agg.durationCount)
+		}
+		avgResults = append(avgResults, bson.M{
+			"missionId":       missionID,
+			"averageDuration": avgDuration,
+			"count":           agg.count,
+			"mission":         missionDoc,
+		})
+
+		if missionID == popularID {
+			mostPopular = bson.M{
+				"missionId": missionID,
+				"count":     agg.count,
+				"mission":   missionDoc,
+			}
+		}
+	}
+
+	return bson.M{
+		"mostPopularMission": mostPopular,
+		"avgCompletionTimes": avgResults,
+	}, nil
+}
+
+// completedDurationMs replica activeDurationExpr: el tiempo activo acumulado o,
+// para progresos anteriores a las pausas, endDate - startDate.
+func completedDurationMs(p models.MissionProgress) float64 {
+	return float64(p.CompletedDuration().Milliseconds())
+}
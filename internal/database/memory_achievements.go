@@ -0,0 +1,118 @@
+// /internal/database/memory_achievements.go
+package database
+
+import (
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertAchievement inserta la definición de un logro.
+func (s *MemoryStore) InsertAchievement(achievement models.Achievement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if achievement.ID.IsZero() {
+		achievement.ID = primitive.NewObjectID()
+	}
+	achievement.TenantID = s.tenant
+	for _, a := range s.achievements {
+		// Equivale al índice único (tenantId, code) de MongoStore
+		if a.ID == achievement.ID || (a.TenantID == s.tenant && a.Code == achievement.Code) {
+			return ErrDuplicate
+		}
+	}
+	s.achievements = append(s.achievements, achievement)
+	return nil
+}
+
+// GetAchievements obtiene las definiciones de logros ordenadas por fecha de creación.
+func (s *MemoryStore) GetAchievements(includeInactive bool, query ListQuery[models.Achievement]) ([]models.Achievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	achievements := []models.Achievement{}
+	for _, a := range s.achievements {
+		if a.TenantID == s.tenant && (includeInactive || a.Active) {
+			achievements = append(achievements, a)
+		}
+	}
+	return listPage(achievements, query, func(a, b models.Achievement) bool {
+		return dateThenID(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+// UpdateAchievement aplica los cambios y devuelve la definición actualizada.
+func (s *MemoryStore) UpdateAchievement(id primitive.ObjectID, update AchievementUpdate, at time.Time) (*models.Achievement, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.achievements {
+		a := &s.achievements[i]
+		if a.ID != id || a.TenantID != s.tenant {
+			continue
+		}
+		if update.Name != nil {
+			a.Name = *update.Name
+		}
+		if update.Description != nil {
+			a.Description = *update.Description
+		}
+		if update.Rule != nil {
+			a.Rule = *update.Rule
+		}
+		if update.Active != nil {
+			a.Active = *update.Active
+		}
+		a.UpdatedAt = at
+		updated := *a
+		return &updated, nil
+	}
+	return nil, ErrNotFound
+}
+
+// DeleteAchievement elimina la definición de un logro. Los logros ya
+// otorgados se conservan.
+func (s *MemoryStore) DeleteAchievement(id primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, a := range s.achievements {
+		if a.ID == id && a.TenantID == s.tenant {
+			s.achievements = append(s.achievements[:i], s.achievements[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// InsertUserAchievement otorga un logro a un usuario.
+func (s *MemoryStore) InsertUserAchievement(award models.UserAchievement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if award.ID.IsZero() {
+		award.ID = primitive.NewObjectID()
+	}
+	award.TenantID = s.tenant
+	for _, a := range s.userAchievements {
+		// Equivale al índice único (userId, achievementId) de MongoStore
+		if a.ID == award.ID || (a.UserID == award.UserID && a.AchievementID == award.AchievementID) {
+			return ErrDuplicate
+		}
+	}
+	s.userAchievements = append(s.userAchievements, award)
+	return nil
+}
+
+// GetUserAchievements obtiene los logros del usuario por fecha de obtención.
+func (s *MemoryStore) GetUserAchievements(userID primitive.ObjectID, query ListQuery[models.UserAchievement]) ([]models.UserAchievement, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	awards := []models.UserAchievement{}
+	for _, a := range s.userAchievements {
+		if a.TenantID == s.tenant && a.UserID == userID {
+			awards = append(awards, a)
+		}
+	}
+	return listPage(awards, query, func(a, b models.UserAchievement) bool {
+		return dateThenID(a.AwardedAt, b.AwardedAt, a.ID, b.ID)
+	}), nil
+}
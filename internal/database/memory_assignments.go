@@ -0,0 +1,78 @@
+// /internal/database/memory_assignments.go
+package database
+
+import (
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertAssignment inserta una misión asignada.
+func (s *MemoryStore) InsertAssignment(assignment models.Assignment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if assignment.ID.IsZero() {
+		assignment.ID = primitive.NewObjectID()
+	}
+	assignment.TenantID = s.tenant
+	for _, a := range s.assignments {
+		if a.ID == assignment.ID {
+			return ErrDuplicate
+		}
+	}
+	s.assignments = append(s.assignments, assignment)
+	return nil
+}
+
+// GetAssignmentByID obtiene una asignación por su ID.
+func (s *MemoryStore) GetAssignmentByID(id primitive.ObjectID) (*models.Assignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, a := range s.assignments {
+		if a.ID == id && a.TenantID == s.tenant {
+			return &a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetTeacherAssignments obtiene las asignaciones creadas por el docente.
+func (s *MemoryStore) GetTeacherAssignments(teacherID primitive.ObjectID, query ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	return s.filterAssignments(func(a models.Assignment) bool { return a.TeacherID == teacherID }, query), nil
+}
+
+// GetStudentAssignments obtiene las asignaciones del estudiante y de sus clases.
+func (s *MemoryStore) GetStudentAssignments(studentID primitive.ObjectID, classroomIDs []primitive.ObjectID, openAt time.Time, query ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	classrooms := map[primitive.ObjectID]bool{}
+	for _, id := range classroomIDs {
+		classrooms[id] = true
+	}
+	return s.filterAssignments(func(a models.Assignment) bool {
+		if !openAt.IsZero() && !a.IsOpen(openAt) {
+			return false
+		}
+		if !a.StudentID.IsZero() {
+			return a.StudentID == studentID
+		}
+		return classrooms[a.ClassroomID]
+	}, query), nil
+}
+
+// filterAssignments devuelve la página de query de las asignaciones que
+// cumplen match, ordenadas como en MongoStore: por fecha de entrega y luego
+// por ID.
+func (s *MemoryStore) filterAssignments(match func(models.Assignment) bool, query ListQuery[models.Assignment]) []models.Assignment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []models.Assignment{}
+	for _, a := range s.assignments {
+		if a.TenantID == s.tenant && match(a) {
+			result = append(result, a)
+		}
+	}
+	return listPage(result, query, func(a, b models.Assignment) bool {
+		return dateThenID(a.DueAt, b.DueAt, a.ID, b.ID)
+	})
+}
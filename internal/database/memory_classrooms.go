@@ -0,0 +1,115 @@
+// /internal/database/memory_classrooms.go
+package database
+
+import (
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// copyClassroom evita que quien llama comparta la lista de estudiantes con el store.
+func copyClassroom(c models.Classroom) models.Classroom {
+	c.StudentIDs = append([]primitive.ObjectID{}, c.StudentIDs...)
+	return c
+}
+
+// InsertClassroom inserta una clase nueva.
+func (s *MemoryStore) InsertClassroom(classroom models.Classroom) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if classroom.ID.IsZero() {
+		classroom.ID = primitive.NewObjectID()
+	}
+	classroom.TenantID = s.tenant
+	for _, c := range s.classrooms {
+		// Equivale al índice único de joinCode en MongoStore
+		if c.ID == classroom.ID || c.JoinCode == classroom.JoinCode {
+			return ErrDuplicate
+		}
+	}
+	s.classrooms = append(s.classrooms, copyClassroom(classroom))
+	return nil
+}
+
+// GetClassroomByID obtiene una clase por su ID.
+func (s *MemoryStore) GetClassroomByID(id primitive.ObjectID) (*models.Classroom, error) {
+	return s.findClassroom(func(c models.Classroom) bool { return c.ID == id })
+}
+
+// FindClassroomByJoinCode busca una clase por su código.
+func (s *MemoryStore) FindClassroomByJoinCode(code string) (*models.Classroom, error) {
+	return s.findClassroom(func(c models.Classroom) bool { return c.JoinCode == code })
+}
+
+func (s *MemoryStore) findClassroom(match func(models.Classroom) bool) (*models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, c := range s.classrooms {
+		if c.TenantID == s.tenant && match(c) {
+			classroom := copyClassroom(c)
+			return &classroom, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetTeacherClassrooms obtiene las clases del docente.
+func (s *MemoryStore) GetTeacherClassrooms(teacherID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	return s.findClassrooms(func(c models.Classroom) bool { return c.TeacherID == teacherID }, query)
+}
+
+// GetStudentClassrooms obtiene las clases en que está inscrito el estudiante.
+func (s *MemoryStore) GetStudentClassrooms(studentID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	return s.findClassrooms(func(c models.Classroom) bool { return c.HasStudent(studentID) }, query)
+}
+
+func (s *MemoryStore) findClassrooms(match func(models.Classroom) bool, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	classrooms := []models.Classroom{}
+	for _, c := range s.classrooms {
+		if c.TenantID == s.tenant && match(c) {
+			classrooms = append(classrooms, copyClassroom(c))
+		}
+	}
+	return listPage(classrooms, query, func(a, b models.Classroom) bool {
+		return dateThenID(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+// AddClassroomStudent inscribe al estudiante en la clase.
+func (s *MemoryStore) AddClassroomStudent(classroomID, studentID primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.classrooms {
+		c := &s.classrooms[i]
+		if c.ID == classroomID && c.TenantID == s.tenant {
+			if !c.HasStudent(studentID) {
+				c.StudentIDs = append(c.StudentIDs, studentID)
+			}
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// RemoveClassroomStudent da de baja al estudiante de la clase.
+func (s *MemoryStore) RemoveClassroomStudent(classroomID, studentID primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.classrooms {
+		c := &s.classrooms[i]
+		if c.ID != classroomID || c.TenantID != s.tenant {
+			continue
+		}
+		kept := c.StudentIDs[:0]
+		for _, id := range c.StudentIDs {
+			if id != studentID {
+				kept = append(kept, id)
+			}
+		}
+		c.StudentIDs = kept
+		return nil
+	}
+	return ErrNotFound
+}
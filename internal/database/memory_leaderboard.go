@@ -0,0 +1,149 @@
+// /internal/database/memory_leaderboard.go
+package database
+
+import (
+	"sort"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publicProfile copia los campos del usuario que proyecta withUsers.
+func publicProfile(u models.User) models.User {
+	return models.User{
+		ID:          u.ID,
+		XP:          u.XP,
+		BirthDate:   u.BirthDate,
+		DisplayName: u.DisplayName,
+		Avatar:      u.Avatar,
+	}
+}
+
+// addLeaderboardCompletion suma una misión completada a las entradas del
+// usuario en cada periodo. Debe llamarse con el lock tomado.
+func (s *MemoryStore) addLeaderboardCompletion(userID primitive.ObjectID, hidden bool, xp int, at time.Time) {
+	for _, bucket := range leaderboardBuckets(at) {
+		i := 0
+		for i < len(s.leaderboard) && (s.leaderboard[i].TenantID != s.tenant || s.leaderboard[i].Bucket != bucket || s.leaderboard[i].UserID != userID) {
+			i++
+		}
+		if i == len(s.leaderboard) {
+			s.leaderboard = append(s.leaderboard, leaderboardDoc{TenantID: s.tenant, Bucket: bucket, UserID: userID, Hidden: hidden})
+		}
+		doc := &s.leaderboard[i]
+		doc.XP += xp
+		doc.CompletedCount++
+		if at.After(doc.ReachedAt) {
+			doc.ReachedAt = at
+		}
+	}
+}
+
+// RecordLeaderboardCompletion suma la misión a los rankings de sus periodos.
+func (s *MemoryStore) RecordLeaderboardCompletion(userID primitive.ObjectID, xp int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, u := range s.users {
+		if u.ID == userID && u.TenantID == s.tenant {
+			s.addLeaderboardCompletion(userID, u.HideFromLeaderboard, xp, at)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// ranking devuelve las entradas visibles del bucket en el orden del ranking.
+// Omite a los usuarios que ya no existen, como el $unwind de MongoStore.
+// Debe llamarse con el lock tomado.
+func (s *MemoryStore) ranking(bucket string) []models.LeaderboardEntry {
+	users := map[primitive.ObjectID]models.User{}
+	for _, u := range s.users {
+		users[u.ID] = u
+	}
+	leaderboard := []models.LeaderboardEntry{}
+	for _, doc := range s.leaderboard {
+		if doc.TenantID != s.tenant || doc.Bucket != bucket || doc.Hidden {
+			continue
+		}
+		u, ok := users[doc.UserID]
+		if !ok {
+			continue
+		}
+		leaderboard = append(leaderboard, models.LeaderboardEntry{
+			UserID:         doc.UserID,
+			XP:             doc.XP,
+			CompletedCount: doc.CompletedCount,
+			ReachedAt:      doc.ReachedAt,
+			User:           publicProfile(u),
+		})
+	}
+	sort.Slice(leaderboard, func(i, j int) bool {
+		return leaderboard[i].Precedes(leaderboard[j])
+	})
+	return leaderboard
+}
+
+// GetLeaderboard devuelve una página del ranking del bucket.
+func (s *MemoryStore) GetLeaderboard(query LeaderboardQuery) ([]models.LeaderboardEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	page := []models.LeaderboardEntry{}
+	for _, entry := range s.ranking(query.Bucket) {
+		if query.After != nil && !query.After.Precedes(entry) {
+			continue
+		}
+		if query.Before != nil && !entry.Precedes(*query.Before) {
+			continue
+		}
+		page = append(page, entry)
+	}
+	if query.Limit > 0 && len(page) > query.Limit {
+		// Con Before se conservan las entradas más cercanas a la referencia
+		if query.Before != nil {
+			page = page[len(page)-query.Limit:]
+		} else {
+			page = page[:query.Limit]
+		}
+	}
+	return page, nil
+}
+
+// GetLeaderboardPosition devuelve la entrada del usuario en el bucket y
+// cuántos usuarios lo preceden.
+func (s *MemoryStore) GetLeaderboardPosition(userID primitive.ObjectID, bucket string) (*models.LeaderboardEntry, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i, entry := range s.ranking(bucket) {
+		if entry.UserID == userID {
+			return &entry, i, nil
+		}
+	}
+	return nil, 0, ErrNotFound
+}
+
+// RebuildLeaderboard recalcula los rankings de la organización a partir de
+// las misiones completadas.
+func (s *MemoryStore) RebuildLeaderboard() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hidden := map[primitive.ObjectID]bool{}
+	for _, u := range s.users {
+		hidden[u.ID] = u.HideFromLeaderboard
+	}
+	kept := []leaderboardDoc{}
+	for _, doc := range s.leaderboard {
+		if doc.TenantID != s.tenant {
+			kept = append(kept, doc)
+		}
+	}
+	s.leaderboard = kept
+	for _, p := range s.progress {
+		if p.TenantID == s.tenant && p.Status == models.ProgressCompleted {
+			s.addLeaderboardCompletion(p.UserID, hidden[p.UserID], p.XPAwarded, p.EndDate)
+		}
+	}
+	return nil
+}
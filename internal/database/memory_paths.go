@@ -0,0 +1,96 @@
+// /internal/database/memory_paths.go
+package database
+
+import (
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// copyPath evita que quien llama comparta los capítulos con el store.
+func copyPath(p models.LearningPath) models.LearningPath {
+	chapters := make([]models.PathChapter, len(p.Chapters))
+	for i, chapter := range p.Chapters {
+		chapter.MissionIDs = append([]primitive.ObjectID{}, chapter.MissionIDs...)
+		chapters[i] = chapter
+	}
+	p.Chapters = chapters
+	return p
+}
+
+// InsertPath inserta una ruta de aprendizaje.
+func (s *MemoryStore) InsertPath(path models.LearningPath) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if path.ID.IsZero() {
+		path.ID = primitive.NewObjectID()
+	}
+	path.TenantID = s.tenant
+	for _, p := range s.paths {
+		if p.ID == path.ID {
+			return ErrDuplicate
+		}
+	}
+	s.paths = append(s.paths, copyPath(path))
+	return nil
+}
+
+// GetPaths obtiene las rutas de la organización por fecha de creación.
+func (s *MemoryStore) GetPaths(query ListQuery[models.LearningPath]) ([]models.LearningPath, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	paths := []models.LearningPath{}
+	for _, p := range s.paths {
+		if p.TenantID == s.tenant {
+			paths = append(paths, copyPath(p))
+		}
+	}
+	return listPage(paths, query, func(a, b models.LearningPath) bool {
+		return dateThenID(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}), nil
+}
+
+// GetPathByID obtiene una ruta por su ID.
+func (s *MemoryStore) GetPathByID(id primitive.ObjectID) (*models.LearningPath, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.paths {
+		if p.ID == id && p.TenantID == s.tenant {
+			path := copyPath(p)
+			return &path, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// ReplacePath reemplaza los datos editables de la ruta.
+func (s *MemoryStore) ReplacePath(path models.LearningPath) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.paths {
+		p := &s.paths[i]
+		if p.ID != path.ID || p.TenantID != s.tenant {
+			continue
+		}
+		replaced := copyPath(path)
+		p.Title = replaced.Title
+		p.Description = replaced.Description
+		p.Chapters = replaced.Chapters
+		p.UpdatedAt = replaced.UpdatedAt
+		return nil
+	}
+	return ErrNotFound
+}
+
+// DeletePath elimina una ruta; las misiones no se modifican.
+func (s *MemoryStore) DeletePath(id primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, p := range s.paths {
+		if p.ID == id && p.TenantID == s.tenant {
+			s.paths = append(s.paths[:i], s.paths[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
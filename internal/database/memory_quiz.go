@@ -0,0 +1,60 @@
+// /internal/database/memory_quiz.go
+package database
+
+import (
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertQuizAttempt guarda un intento calificado de un cuestionario.
+func (s *MemoryStore) InsertQuizAttempt(attempt models.QuizAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if attempt.ID.IsZero() {
+		attempt.ID = primitive.NewObjectID()
+	}
+	attempt.TenantID = s.tenant
+	for _, a := range s.attempts {
+		if a.ID == attempt.ID {
+			return ErrDuplicate
+		}
+		if attempt.Number > 0 && a.Number == attempt.Number && a.TenantID == s.tenant &&
+			a.UserID == attempt.UserID && a.MissionID == attempt.MissionID {
+			return ErrDuplicate
+		}
+	}
+	s.attempts = append(s.attempts, attempt)
+	return nil
+}
+
+// GetQuizAttempts obtiene una página de los intentos de un usuario en una
+// misión en orden cronológico.
+func (s *MemoryStore) GetQuizAttempts(userID, missionID primitive.ObjectID, query ListQuery[models.QuizAttempt]) ([]models.QuizAttempt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	attempts := []models.QuizAttempt{}
+	for _, a := range s.attempts {
+		if a.TenantID == s.tenant && a.UserID == userID && a.MissionID == missionID {
+			attempts = append(attempts, a)
+		}
+	}
+	return listPage(attempts, query, func(a, b models.QuizAttempt) bool {
+		return dateThenID(a.SubmittedAt, b.SubmittedAt, a.ID, b.ID)
+	}), nil
+}
+
+// GetQuizAttemptSummary cuenta los intentos de un usuario en una misión y
+// comprueba si alguno aprobó.
+func (s *MemoryStore) GetQuizAttemptSummary(userID, missionID primitive.ObjectID) (QuizAttemptSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var summary QuizAttemptSummary
+	for _, a := range s.attempts {
+		if a.TenantID == s.tenant && a.UserID == userID && a.MissionID == missionID {
+			summary.Attempts++
+			summary.Passed = summary.Passed || a.Passed
+		}
+	}
+	return summary, nil
+}
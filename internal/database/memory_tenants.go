@@ -0,0 +1,43 @@
+// /internal/database/memory_tenants.go
+package database
+
+import "explorax-backend/internal/models"
+
+// InsertTenant registra una organización.
+func (s *MemoryStore) InsertTenant(tenant models.Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tenants {
+		if t.ID == tenant.ID {
+			return ErrDuplicate
+		}
+	}
+	s.tenants = append(s.tenants, tenant)
+	return nil
+}
+
+// GetTenant busca una organización por su ID.
+func (s *MemoryStore) GetTenant(id string) (*models.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.tenants {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	if id == models.DefaultTenantID {
+		tenant := defaultTenant
+		return &tenant, nil
+	}
+	return nil, ErrNotFound
+}
+
+// GetTenants devuelve las organizaciones registradas ordenadas por ID.
+func (s *MemoryStore) GetTenants(query ListQuery[models.Tenant]) ([]models.Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tenants := append([]models.Tenant{}, s.tenants...)
+	return listPage(tenants, query, func(a, b models.Tenant) bool {
+		return a.ID < b.ID
+	}), nil
+}
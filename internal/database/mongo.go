@@ -3,11 +3,15 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"explorax-backend/internal/models"
+	"explorax-backend/internal/textnorm"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,6 +29,17 @@ func Connect() {
 		log.Fatal("MONGO_URI no está configurada. Revisa tu .env o variables de entorno.")
 	}
 
+	client, err := Dial(mongoURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	Client = client
+	log.Println("Pinged your deployment. You successfully connected to MongoDB!")
+}
+
+// Dial abre un cliente contra mongoURI y comprueba la conexión con un ping.
+func Dial(mongoURI string) (*mongo.Client, error) {
 	// Configura el ServerAPIOptions con la versión estable de la API (ServerAPIVersion1)
 	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
 	opts := options.Client().ApplyURI(mongoURI).SetServerAPIOptions(serverAPI)
@@ -34,64 +49,608 @@ func Connect() {
 
 	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
-		log.Fatal("Error conectando a MongoDB: ", err)
+		return nil, fmt.Errorf("error conectando a MongoDB: %w", err)
 	}
 
 	// Realiza un ping para confirmar la conexión
-	if err := client.Database("admin").RunCommand(ctx, bson.D{{"ping", 1}}).Err(); err != nil {
-		log.Fatal("No se pudo hacer ping a MongoDB: ", err)
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err(); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("no se pudo hacer ping a MongoDB: %w", err)
 	}
 
-	Client = client
-	log.Println("Pinged your deployment. You successfully connected to MongoDB!")
+	return client, nil
+}
+
+// MongoStore implementa Store sobre una base de datos de MongoDB. Todas las
+// organizaciones comparten las colecciones; cada documento guarda la suya en
+// tenantId y cada consulta filtra por ella.
+type MongoStore struct {
+	db     *mongo.Database
+	tenant string
+}
+
+// NewMongoStore crea un Store de la organización DefaultTenantID respaldado
+// por la base de datos indicada.
+func NewMongoStore(db *mongo.Database) *MongoStore {
+	return &MongoStore{db: db, tenant: models.DefaultTenantID}
+}
+
+// ForTenant devuelve un Store sobre la misma base de datos limitado a la
+// organización tenantID.
+func (s *MongoStore) ForTenant(tenantID string) Store {
+	return &MongoStore{db: s.db, tenant: tenantID}
+}
+
+// scoped agrega a filter la condición de pertenecer a la organización del Store.
+func (s *MongoStore) scoped(filter bson.M) bson.M {
+	filter["tenantId"] = s.tenant
+	return filter
+}
+
+// visibleMissions agrega a filter la condición de ser una misión de la
+// organización o una misión global.
+func (s *MongoStore) visibleMissions(filter bson.M) bson.M {
+	filter["$or"] = []bson.M{{"tenantId": s.tenant}, {"global": true}}
+	return filter
+}
+
+func (s *MongoStore) users() *mongo.Collection {
+	return s.db.Collection("users")
+}
+
+func (s *MongoStore) sessions() *mongo.Collection {
+	return s.db.Collection("sessions")
+}
+
+func (s *MongoStore) missions() *mongo.Collection {
+	return s.db.Collection("missions")
+}
+
+func (s *MongoStore) missionProgress() *mongo.Collection {
+	return s.db.Collection("mission_progress")
 }
 
-func GetUserCollection() *mongo.Collection {
-	return Client.Database("explorax").Collection("users")
+func (s *MongoStore) quizAttempts() *mongo.Collection {
+	return s.db.Collection("quiz_attempts")
 }
 
-func GetMissionProgressCollection() *mongo.Collection {
-	return Client.Database("explorax").Collection("mission_progress")
+func (s *MongoStore) achievements() *mongo.Collection {
+	return s.db.Collection("achievements")
+}
+
+func (s *MongoStore) userAchievements() *mongo.Collection {
+	return s.db.Collection("user_achievements")
+}
+
+func (s *MongoStore) leaderboard() *mongo.Collection {
+	return s.db.Collection("leaderboard")
+}
+
+func (s *MongoStore) classrooms() *mongo.Collection {
+	return s.db.Collection("classrooms")
+}
+
+func (s *MongoStore) assignments() *mongo.Collection {
+	return s.db.Collection("assignments")
+}
+
+func (s *MongoStore) learningPaths() *mongo.Collection {
+	return s.db.Collection("learning_paths")
+}
+
+func (s *MongoStore) tenants() *mongo.Collection {
+	return s.db.Collection("tenants")
+}
+
+// EnsureIndexes crea los índices que la aplicación necesita. Es idempotente y
+// se ejecuta al arrancar. El índice único de mission_progress falla si ya hay
+// progresos duplicados de un mismo usuario y misión; deben depurarse antes.
+func (s *MongoStore) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := s.missionProgress().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "missionId", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("userId_missionId_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("índice único de mission_progress: %w", err)
+	}
+
+	// Las listas de progreso de un usuario se paginan por fecha de inicio o
+	// de fin, con el _id como desempate
+	_, err = s.missionProgress().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startDate", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "endDate", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de mission_progress: %w", err)
+	}
+
+	_, err = s.sessions().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "refreshTokenHash", Value: 1}}},
+		{Keys: bson.D{{Key: "previousRefreshTokenHash", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de sessions: %w", err)
+	}
+
+	// El número de intento es único por usuario y misión para que dos envíos
+	// simultáneos no superen el límite; los intentos anteriores no lo tienen
+	_, err = s.quizAttempts().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "missionId", Value: 1}, {Key: "submittedAt", Value: 1}}},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "missionId", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"number": bson.M{"$gt": 0}}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de quiz_attempts: %w", err)
+	}
+
+	// El catálogo se consulta por organización, o entre las globales, y se
+	// filtra por categoría; los demás filtros tienen su propio índice
+	_, err = s.missions().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "category", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "global", Value: 1}, {Key: "category", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+		{Keys: bson.D{{Key: "difficulty", Value: 1}}},
+		{Keys: bson.D{{Key: "gradeLevel", Value: 1}}},
+		{Keys: bson.D{{Key: "estimatedMinutes", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de missions: %w", err)
+	}
+
+	// Índice de texto de la búsqueda: en español, ignora mayúsculas y acentos
+	// y reconoce los plurales. Una colección admite un solo índice de texto
+	textWeights := bson.D{}
+	for _, field := range []string{"title", "tags", "description"} {
+		textWeights = append(textWeights, bson.E{Key: field, Value: searchWeights[field]})
+	}
+	_, err = s.missions().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "tags", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().
+			SetName("missions_text").
+			SetWeights(textWeights).
+			SetDefaultLanguage("spanish"),
+	})
+	if err != nil {
+		return fmt.Errorf("índice de texto de missions: %w", err)
+	}
+
+	_, err = s.users().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "email", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("índice de users: %w", err)
+	}
+
+	// Los códigos de logro se repiten entre organizaciones: el índice único
+	// global se reemplaza por uno por organización
+	if err := dropIndex(ctx, s.achievements(), "code_unique"); err != nil {
+		return fmt.Errorf("índice único de achievements: %w", err)
+	}
+	_, err = s.achievements().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenantId", Value: 1}, {Key: "code", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("tenantId_code_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("índice único de achievements: %w", err)
+	}
+
+	// Cada página del ranking se lee recorriendo el índice en orden; el índice
+	// anterior a las organizaciones no las distinguía
+	if err := dropIndex(ctx, s.leaderboard(), "bucket_ranking"); err != nil {
+		return fmt.Errorf("índices de leaderboard: %w", err)
+	}
+	_, err = s.leaderboard().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "bucket", Value: 1}, {Key: "userId", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("bucket_userId_unique"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "bucket", Value: 1},
+				{Key: "hidden", Value: 1},
+				{Key: "xp", Value: -1},
+				{Key: "completedCount", Value: -1},
+				{Key: "reachedAt", Value: 1},
+				{Key: "userId", Value: 1},
+			},
+			Options: options.Index().SetName("tenant_bucket_ranking"),
+		},
+		{Keys: bson.D{{Key: "userId", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de leaderboard: %w", err)
+	}
+
+	_, err = s.classrooms().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "joinCode", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("joinCode_unique"),
+		},
+		{Keys: bson.D{{Key: "teacherId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "studentIds", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de classrooms: %w", err)
+	}
+
+	_, err = s.assignments().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "teacherId", Value: 1}, {Key: "dueAt", Value: 1}}},
+		{Keys: bson.D{{Key: "classroomId", Value: 1}, {Key: "dueAt", Value: 1}}},
+		{Keys: bson.D{{Key: "studentId", Value: 1}, {Key: "dueAt", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("índices de assignments: %w", err)
+	}
+
+	_, err = s.learningPaths().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "createdAt", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("índice de learning_paths: %w", err)
+	}
+
+	// Evita otorgar dos veces el mismo logro aunque se evalúe en paralelo
+	_, err = s.userAchievements().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "achievementId", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("userId_achievementId_unique"),
+	})
+	if err != nil {
+		return fmt.Errorf("índice único de user_achievements: %w", err)
+	}
+	return nil
+}
+
+// dropIndex elimina un índice que fue reemplazado; si ya no existe no hace nada.
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	// 26: la colección no existe; 27: el índice no existe
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) {
+		return nil
+	}
+	return err
+}
+
+// MigrateDefaultTenant asigna la organización DefaultTenantID a los documentos
+// creados antes de existir las organizaciones. Es idempotente y se ejecuta al
+// arrancar, después de EnsureIndexes.
+func (s *MongoStore) MigrateDefaultTenant() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	collections := []*mongo.Collection{
+		s.users(), s.sessions(), s.missions(), s.missionProgress(), s.quizAttempts(),
+		s.achievements(), s.userAchievements(), s.leaderboard(), s.classrooms(), s.assignments(),
+	}
+	filter := bson.M{"tenantId": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"tenantId": models.DefaultTenantID}}
+	for _, collection := range collections {
+		if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+			return fmt.Errorf("asignar la organización por defecto en %s: %w", collection.Name(), err)
+		}
+	}
+	return nil
+}
+
+// translateError convierte los errores del driver en los errores del paquete.
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return fmt.Errorf("%w: %v", ErrDuplicate, err)
+	}
+	return err
+}
+
+// sortKey es un campo del orden de una consulta con el valor que tiene en el
+// último documento de la página anterior. Value nil representa el campo
+// ausente, que MongoDB ordena antes que cualquier valor.
+type sortKey struct {
+	Field      string
+	Value      interface{}
+	Descending bool
+}
+
+// keysetAfter filtra los documentos que van después del que tiene los valores
+// de keys en el orden que definen: como leaderboardAfter, los que son iguales
+// en los primeros campos y van después en el siguiente. Así cada página se lee
+// a partir de la anterior, sin saltar documentos.
+func keysetAfter(keys ...sortKey) bson.M {
+	var or []bson.M
+	equal := bson.M{}
+	for _, key := range keys {
+		for _, after := range key.after() {
+			clause := bson.M{key.Field: after}
+			for field, value := range equal {
+				clause[field] = value
+			}
+			or = append(or, clause)
+		}
+		// nil coincide tanto con null como con el campo ausente
+		equal[key.Field] = key.Value
+	}
+	return bson.M{"$or": or}
+}
+
+// after son las condiciones alternativas que cumple el campo en los
+// documentos que van después.
+func (k sortKey) after() []interface{} {
+	switch {
+	case k.Value == nil && k.Descending:
+		return nil
+	case k.Value == nil:
+		return []interface{}{bson.M{"$ne": nil}}
+	case k.Descending:
+		return []interface{}{bson.M{"$lt": k.Value}, nil}
+	}
+	return []interface{}{bson.M{"$gt": k.Value}}
+}
+
+// sortValue devuelve v, o nil si es el valor cero que omitempty no guarda.
+func sortValue[T comparable](v T) interface{} {
+	var zero T
+	if t, ok := interface{}(v).(time.Time); ok && t.IsZero() || v == zero {
+		return nil
+	}
+	return v
+}
+
+// listOptions ordena por field y luego por _id, ambos en orden ascendente, y
+// lee hasta limit documentos; limit en 0 no limita. Es el orden de las listas
+// que se piden con ListQuery.
+func listOptions(field string, limit int) *options.FindOptions {
+	sort := bson.D{{Key: "_id", Value: 1}}
+	if field != "_id" {
+		sort = append(bson.D{{Key: field, Value: 1}}, sort...)
+	}
+	opts := options.Find().SetSort(sort)
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	return opts
+}
+
+// listAfter limita filter a los documentos que van después del que tiene
+// value en field e id en _id, en el orden de listOptions.
+func listAfter(filter bson.M, field string, value, id interface{}) bson.M {
+	keys := []sortKey{{Field: "_id", Value: id}}
+	if field != "_id" {
+		keys = append([]sortKey{{Field: field, Value: value}}, keys...)
+	}
+	return bson.M{"$and": []bson.M{filter, keysetAfter(keys...)}}
 }
 
 // InsertUser inserta un nuevo usuario en la base de datos.
-func InsertUser(user models.User) error {
-	collection := GetUserCollection()
+func (s *MongoStore) InsertUser(user models.User) error {
+	collection := s.users()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	user.TenantID = s.tenant
 	_, err := collection.InsertOne(ctx, user)
-	return err
+	return translateError(err)
 }
 
 // FindUserByEmail busca un usuario por email.
-func FindUserByEmail(email string) (*models.User, error) {
-	collection := GetUserCollection()
+func (s *MongoStore) FindUserByEmail(email string) (*models.User, error) {
+	collection := s.users()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	var user models.User
-	err := collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := collection.FindOne(ctx, s.scoped(bson.M{"email": email})).Decode(&user)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+// FindUserByID busca un usuario por su ID.
+func (s *MongoStore) FindUserByID(id primitive.ObjectID) (*models.User, error) {
+	collection := s.users()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var user models.User
+	err := collection.FindOne(ctx, s.scoped(bson.M{"_id": id})).Decode(&user)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+// UpdateUser actualiza las preferencias del usuario y devuelve el documento
+// resultante.
+func (s *MongoStore) UpdateUser(id primitive.ObjectID, update UserUpdate) (*models.User, error) {
+	collection := s.users()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{}
+	if update.Timezone != nil {
+		set["timezone"] = *update.Timezone
+	}
+	if update.BirthDate != nil {
+		set["birthDate"] = *update.BirthDate
+	}
+	if update.DisplayName != nil {
+		set["displayName"] = *update.DisplayName
+	}
+	if update.Avatar != nil {
+		set["avatar"] = *update.Avatar
+	}
+	if update.HideFromLeaderboard != nil {
+		set["hideFromLeaderboard"] = *update.HideFromLeaderboard
+	}
+	if update.Locale != nil {
+		set["locale"] = *update.Locale
+	}
+	if len(set) == 0 {
+		return s.FindUserByID(id)
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var user models.User
+	err := collection.FindOneAndUpdate(ctx, s.scoped(bson.M{"_id": id}), bson.M{"$set": set}, opts).Decode(&user)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if update.HideFromLeaderboard != nil {
+		_, err = s.leaderboard().UpdateMany(ctx, s.scoped(bson.M{"userId": id}), bson.M{"$set": bson.M{"hidden": user.HideFromLeaderboard}})
+		if err != nil {
+			return nil, fmt.Errorf("ocultar al usuario del leaderboard: %w", err)
+		}
 	}
 	return &user, nil
 }
 
+// AddUserXP incrementa la experiencia del usuario con $inc.
+func (s *MongoStore) AddUserXP(id primitive.ObjectID, xp int) error {
+	collection := s.users()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.UpdateOne(ctx, s.scoped(bson.M{"_id": id}), bson.M{"$inc": bson.M{"xp": xp}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateUserStreak reemplaza la racha diaria del usuario.
+func (s *MongoStore) UpdateUserStreak(id primitive.ObjectID, streak models.Streak) error {
+	collection := s.users()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.UpdateOne(ctx, s.scoped(bson.M{"_id": id}), bson.M{"$set": bson.M{"streak": streak}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InsertSession inserta una nueva sesión.
+func (s *MongoStore) InsertSession(session models.Session) error {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, session)
+	return translateError(err)
+}
+
+// FindSessionByRefreshHash busca la sesión cuyo refresh token vigente o
+// inmediatamente anterior coincide con el hash dado.
+func (s *MongoStore) FindSessionByRefreshHash(hash string) (*models.Session, error) {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"$or": []bson.M{
+		{"refreshTokenHash": hash},
+		{"previousRefreshTokenHash": hash},
+	}})
+	var session models.Session
+	err := collection.FindOne(ctx, filter).Decode(&session)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &session, nil
+}
+
+// RotateSession reemplaza el refresh token de una sesión activa. Solo tiene
+// efecto si el hash vigente sigue siendo oldHash, de modo que dos refrescos
+// concurrentes con el mismo token no pueden rotarlo dos veces.
+func (s *MongoStore) RotateSession(id primitive.ObjectID, oldHash, newHash string, usedAt, expiresAt time.Time) error {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"_id": id, "refreshTokenHash": oldHash, "revokedAt": bson.M{"$exists": false}})
+	update := bson.M{
+		"$set": bson.M{
+			"refreshTokenHash":         newHash,
+			"previousRefreshTokenHash": oldHash,
+			"lastUsedAt":               usedAt,
+			"expiresAt":                expiresAt,
+		},
+	}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeSession marca una sesión como revocada.
+func (s *MongoStore) RevokeSession(id primitive.ObjectID, at time.Time) error {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"_id": id, "revokedAt": bson.M{"$exists": false}})
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revokedAt": at}})
+	return err
+}
+
+// RevokeUserSessions revoca todas las sesiones activas de un usuario.
+func (s *MongoStore) RevokeUserSessions(userID primitive.ObjectID, at time.Time) error {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"userId": userID, "revokedAt": bson.M{"$exists": false}})
+	_, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revokedAt": at}})
+	return err
+}
+
+// IsSessionActive indica si una sesión existe, no ha sido revocada y no ha expirado.
+func (s *MongoStore) IsSessionActive(id primitive.ObjectID, now time.Time) (bool, error) {
+	collection := s.sessions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var session models.Session
+	err := collection.FindOne(ctx, s.scoped(bson.M{"_id": id})).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return session.Active(now), nil
+}
+
 // InsertMission inserta una misión.
-func InsertMission(mission models.Mission) error {
-	collection := Client.Database("explorax").Collection("missions")
+func (s *MongoStore) InsertMission(mission models.Mission) error {
+	collection := s.missions()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	mission.TenantID = s.tenant
 	_, err := collection.InsertOne(ctx, mission)
-	return err
+	return translateError(err)
 }
 
-// GetAllMissions obtiene todas las misiones.
-func GetAllMissions() ([]models.Mission, error) {
-	collection := Client.Database("explorax").Collection("missions")
+// GetAllMissions obtiene las misiones de la organización y las globales,
+// omitiendo las archivadas salvo que includeArchived sea true.
+func (s *MongoStore) GetAllMissions(includeArchived bool) ([]models.Mission, error) {
+	collection := s.missions()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := collection.Find(ctx, bson.M{})
+	filter := s.visibleMissions(bson.M{})
+	if !includeArchived {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -102,161 +661,673 @@ func GetAllMissions() ([]models.Mission, error) {
 	return missions, nil
 }
 
+// ListMissions lee una página de las misiones por fecha de creación, sin
+// calcular el total ni las facetas.
+func (s *MongoStore) ListMissions(list MissionList) ([]models.Mission, error) {
+	collection := s.missions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := s.visibleMissions(bson.M{})
+	if list.OwnOnly {
+		filter = s.scoped(bson.M{})
+	}
+	if !list.IncludeArchived {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+	if list.IDs != nil {
+		filter["_id"] = bson.M{"$in": list.IDs}
+	}
+	if list.After != nil {
+		filter = listAfter(filter, "createdAt", list.After.CreatedAt, list.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("createdAt", list.Limit))
+	if err != nil {
+		return nil, err
+	}
+	missions := []models.Mission{}
+	if err = cursor.All(ctx, &missions); err != nil {
+		return nil, err
+	}
+	return missions, nil
+}
+
+// FindMissions filtra, ordena y pagina el catálogo en la base de datos, y
+// calcula el total y las facetas con una agregación sobre todas las misiones
+// que cumplen los filtros.
+func (s *MongoStore) FindMissions(query MissionQuery) (MissionPage, error) {
+	collection := s.missions()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := s.visibleMissions(bson.M{"archived": bson.M{"$ne": true}})
+	if query.Category != "" {
+		filter["category"] = query.Category
+	}
+	if len(query.Tags) > 0 {
+		filter["tags"] = bson.M{"$all": query.Tags}
+	}
+	if query.Difficulty > 0 {
+		filter["difficulty"] = query.Difficulty
+	}
+	if query.GradeLevel > 0 {
+		filter["gradeLevel"] = query.GradeLevel
+	}
+	if query.MaxMinutes > 0 {
+		filter["estimatedMinutes"] = bson.M{"$gt": 0, "$lte": query.MaxMinutes}
+	}
+	if query.IDs != nil {
+		filter["_id"] = bson.M{"$in": query.IDs}
+	}
+
+	field := query.Sort
+	if field == "" {
+		field = SortByCreatedAt
+	}
+	direction := 1
+	if query.SortDescending {
+		direction = -1
+	}
+	sort := bson.D{{Key: field, Value: direction}}
+	if field != SortByCreatedAt {
+		sort = append(sort, bson.E{Key: "createdAt", Value: 1})
+	}
+	sort = append(sort, bson.E{Key: "_id", Value: 1})
+
+	// La página sigue a query.After; el total y las facetas cuentan todas
+	// las misiones que cumplen los filtros
+	pageFilter := filter
+	if query.After != nil {
+		keys := []sortKey{{Field: field, Value: missionSortValue(*query.After, field), Descending: query.SortDescending}}
+		if field != SortByCreatedAt {
+			keys = append(keys, sortKey{Field: "createdAt", Value: query.After.CreatedAt})
+		}
+		keys = append(keys, sortKey{Field: "_id", Value: query.After.ID})
+		pageFilter = bson.M{"$and": []bson.M{filter, keysetAfter(keys...)}}
+	}
+	opts := options.Find().SetSort(sort)
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+	cursor, err := collection.Find(ctx, pageFilter, opts)
+	if err != nil {
+		return MissionPage{}, err
+	}
+	page := MissionPage{Missions: []models.Mission{}, Facets: models.NewMissionFacets()}
+	if err := cursor.All(ctx, &page.Missions); err != nil {
+		return MissionPage{}, err
+	}
+
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$exists": true}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: bson.M{
+			"categories":   countBy("category"),
+			"tags":         append(bson.A{bson.M{"$unwind": "$tags"}}, countBy("tags")...),
+			"difficulties": countBy("difficulty"),
+			"gradeLevels":  countBy("gradeLevel"),
+			"total":        bson.A{bson.M{"$count": "count"}},
+		}}},
+	}
+	aggregation, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return MissionPage{}, err
+	}
+	// Las facetas de texto y las numéricas se decodifican por separado
+	type textBucket struct {
+		Value string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	type numberBucket struct {
+		Value int `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	var results []struct {
+		Categories   []textBucket   `bson:"categories"`
+		Tags         []textBucket   `bson:"tags"`
+		Difficulties []numberBucket `bson:"difficulties"`
+		GradeLevels  []numberBucket `bson:"gradeLevels"`
+		Total        []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+	}
+	if err := aggregation.All(ctx, &results); err != nil {
+		return MissionPage{}, err
+	}
+
+	if len(results) > 0 {
+		for _, b := range results[0].Categories {
+			page.Facets.Categories[b.Value] = b.Count
+		}
+		for _, b := range results[0].Tags {
+			page.Facets.Tags[b.Value] = b.Count
+		}
+		for _, b := range results[0].Difficulties {
+			page.Facets.Difficulties[b.Value] = b.Count
+		}
+		for _, b := range results[0].GradeLevels {
+			page.Facets.GradeLevels[b.Value] = b.Count
+		}
+		if len(results[0].Total) > 0 {
+			page.Total = results[0].Total[0].Count
+		}
+	}
+	return page, nil
+}
+
+// missionSortValue devuelve el valor del campo de orden field en la misión.
+func missionSortValue(m models.Mission, field string) interface{} {
+	switch field {
+	case SortByTitle:
+		return m.Title
+	case SortByDifficulty:
+		return sortValue(m.Difficulty)
+	case SortByGradeLevel:
+		return sortValue(m.GradeLevel)
+	case SortByDuration:
+		return sortValue(m.EstimatedMinutes)
+	}
+	return m.CreatedAt
+}
+
+// SearchMissions busca con el índice de texto missions_text y ordena por su
+// puntaje de relevancia. El puntaje no se puede filtrar en un find, así que
+// la búsqueda es una agregación que lo agrega como campo para seguir desde
+// search.After.
+func (s *MongoStore) SearchMissions(search MissionSearch) ([]MissionMatch, error) {
+	collection := s.missions()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Solo se buscan las palabras, sin la sintaxis de frases ni exclusiones de $text
+	terms := textnorm.Terms(search.Text)
+	if len(terms) == 0 {
+		return []MissionMatch{}, nil
+	}
+	filter := s.visibleMissions(bson.M{
+		"archived": bson.M{"$ne": true},
+		"$text":    bson.M{"$search": strings.Join(terms, " ")},
+	})
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+	}
+	if search.After != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: keysetAfter(
+			sortKey{Field: "score", Value: search.After.Score, Descending: true},
+			sortKey{Field: "_id", Value: search.After.Mission.ID},
+		)}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}}})
+	if search.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: search.Limit}})
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		models.Mission `bson:",inline"`
+		Score          float64 `bson:"score"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	matches := make([]MissionMatch, 0, len(docs))
+	for _, doc := range docs {
+		matches = append(matches, MissionMatch{Mission: doc.Mission, Score: doc.Score})
+	}
+	return matches, nil
+}
+
+// UpdateMission aplica los cambios indicados y devuelve la misión
+// actualizada. Las misiones globales solo se modifican desde su organización.
+func (s *MongoStore) UpdateMission(id primitive.ObjectID, update MissionUpdate, at time.Time) (*models.Mission, error) {
+	collection := s.missions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"updatedAt": at}
+	unset := bson.M{}
+	if update.Title != nil {
+		set["title"] = *update.Title
+	}
+	if update.Description != nil {
+		set["description"] = *update.Description
+	}
+	if update.Steps != nil {
+		set["steps"] = *update.Steps
+	}
+	if update.Quiz != nil {
+		set["quiz"] = update.Quiz
+	} else if update.RemoveQuiz {
+		unset["quiz"] = ""
+	}
+	if update.XPReward != nil {
+		set["xpReward"] = *update.XPReward
+	}
+	if update.DifficultyMultiplier != nil {
+		set["difficultyMultiplier"] = *update.DifficultyMultiplier
+	}
+	if update.Prerequisites != nil {
+		if len(*update.Prerequisites) > 0 {
+			set["prerequisites"] = *update.Prerequisites
+		} else {
+			unset["prerequisites"] = ""
+		}
+	}
+	if update.Tags != nil {
+		if len(*update.Tags) > 0 {
+			set["tags"] = *update.Tags
+		} else {
+			unset["tags"] = ""
+		}
+	}
+	// Los campos omitempty se eliminan en cero, como al insertar la misión
+	setOrUnset := func(field string, value interface{}, zero bool) {
+		if zero {
+			unset[field] = ""
+		} else {
+			set[field] = value
+		}
+	}
+	if update.Category != nil {
+		setOrUnset("category", *update.Category, *update.Category == "")
+	}
+	if update.Difficulty != nil {
+		setOrUnset("difficulty", *update.Difficulty, *update.Difficulty == 0)
+	}
+	if update.GradeLevel != nil {
+		setOrUnset("gradeLevel", *update.GradeLevel, *update.GradeLevel == 0)
+	}
+	if update.EstimatedMinutes != nil {
+		setOrUnset("estimatedMinutes", *update.EstimatedMinutes, *update.EstimatedMinutes == 0)
+	}
+	for locale, translation := range update.Translations {
+		setOrUnset("translations."+locale, translation, translation == nil)
+	}
+	if update.Archived != nil {
+		set["archived"] = *update.Archived
+		if *update.Archived {
+			set["archivedAt"] = at
+		} else {
+			unset["archivedAt"] = ""
+		}
+	}
+	changes := bson.M{"$set": set}
+	if len(unset) > 0 {
+		changes["$unset"] = unset
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var mission models.Mission
+	err := collection.FindOneAndUpdate(ctx, s.scoped(bson.M{"_id": id}), changes, opts).Decode(&mission)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &mission, nil
+}
+
+// DeleteMission elimina definitivamente una misión.
+func (s *MongoStore) DeleteMission(id primitive.ObjectID) error {
+	collection := s.missions()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.DeleteOne(ctx, s.scoped(bson.M{"_id": id}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// HasMissionProgress indica si algún usuario, de cualquier organización, ha
+// iniciado la misión.
+func (s *MongoStore) HasMissionProgress(missionID primitive.ObjectID) (bool, error) {
+	collection := s.missionProgress()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	count, err := collection.CountDocuments(ctx, bson.M{"missionId": missionID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // InsertMissionProgress inserta un nuevo documento de progreso de misión.
-func InsertMissionProgress(progress models.MissionProgress) error {
-	collection := GetMissionProgressCollection()
+func (s *MongoStore) InsertMissionProgress(progress models.MissionProgress) error {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	progress.TenantID = s.tenant
 	_, err := collection.InsertOne(ctx, progress)
-	return err
+	return translateError(err)
 }
 
-// UpdateMissionProgress actualiza el progreso de una misión a "completada" y registra la fecha final.
-func UpdateMissionProgress(userID, missionID primitive.ObjectID) error {
-	collection := GetMissionProgressCollection()
+// FindMissionProgress obtiene el progreso de un usuario en una misión.
+func (s *MongoStore) FindMissionProgress(userID, missionID primitive.ObjectID) (*models.MissionProgress, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	filter := bson.M{"userId": userID, "missionId": missionID, "status": "iniciada"}
-	update := bson.M{
-		"$set": bson.M{
-			"status":  "completada",
-			"endDate": time.Now(),
-		},
+	var progress models.MissionProgress
+	err := collection.FindOne(ctx, s.scoped(bson.M{"userId": userID, "missionId": missionID})).Decode(&progress)
+	if err != nil {
+		return nil, translateError(err)
 	}
-	result, err := collection.UpdateOne(ctx, filter, update)
+	return &progress, nil
+}
+
+// UpdateMissionProgress guarda el progreso tras una transición de estado,
+// siempre que el documento siga en el estado from.
+func (s *MongoStore) UpdateMissionProgress(progress models.MissionProgress, from models.ProgressStatus) error {
+	collection := s.missionProgress()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"_id": progress.ID, "status": from})
+	progress.TenantID = s.tenant
+	result, err := collection.ReplaceOne(ctx, filter, progress)
 	if err != nil {
 		return err
 	}
 	if result.MatchedCount == 0 {
-		return mongo.ErrNoDocuments
+		return ErrNotFound
 	}
 	return nil
 }
 
-// GetMissionProgress obtiene todos los documentos de progreso de misión para un usuario.
-func GetMissionProgress(userID primitive.ObjectID) ([]models.MissionProgress, error) {
-	collection := GetMissionProgressCollection()
+// CreditPendingXP suma al usuario la XP pendiente de sus misiones completadas.
+// Cada crédito se hace en tres pasos que se pueden repetir sin riesgo, sin
+// depender de transacciones: el $inc de la XP anota el progreso en xpCredits
+// del usuario y no se aplica si ya estaba anotado; luego se desmarca el
+// progreso y por último se borra la anotación. Si el proceso se corta entre
+// medias, el progreso sigue pendiente y la siguiente llamada lo termina.
+func (s *MongoStore) CreditPendingXP(userID primitive.ObjectID) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := s.missionProgress().Find(ctx, s.scoped(bson.M{"userId": userID, "xpPending": true}))
+	if err != nil {
+		return 0, err
+	}
+	var pending []models.MissionProgress
+	if err := cursor.All(ctx, &pending); err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, p := range pending {
+		result, err := s.users().UpdateOne(ctx,
+			s.scoped(bson.M{"_id": userID, "xpCredits": bson.M{"$ne": p.ID}}),
+			bson.M{"$inc": bson.M{"xp": p.XPAwarded}, "$push": bson.M{"xpCredits": p.ID}})
+		if err != nil {
+			return credited, err
+		}
+		if result.MatchedCount == 0 {
+			// O ya se había sumado en una llamada interrumpida, o el usuario no existe
+			exists, err := s.users().CountDocuments(ctx, s.scoped(bson.M{"_id": userID}))
+			if err != nil {
+				return credited, err
+			}
+			if exists == 0 {
+				return credited, ErrNotFound
+			}
+		} else {
+			credited += p.XPAwarded
+		}
+		_, err = s.missionProgress().UpdateOne(ctx, s.scoped(bson.M{"_id": p.ID}), bson.M{"$unset": bson.M{"xpPending": ""}})
+		if err != nil {
+			return credited, err
+		}
+		_, err = s.users().UpdateOne(ctx, s.scoped(bson.M{"_id": userID}), bson.M{"$pull": bson.M{"xpCredits": p.ID}})
+		if err != nil {
+			return credited, err
+		}
+	}
+	return credited, nil
+}
+
+// CompleteMissionStep agrega el paso a los pasos completados con una sola
+// operación atómica, de modo que dos peticiones simultáneas no se pisen.
+func (s *MongoStore) CompleteMissionStep(id primitive.ObjectID, step models.StepProgress) (*models.MissionProgress, error) {
+	collection := s.missionProgress()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{
+		"_id":          id,
+		"status":       models.ProgressStarted,
+		"steps.stepId": bson.M{"$ne": step.StepID},
+	})
+	update := bson.M{"$push": bson.M{"steps": step}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var progress models.MissionProgress
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&progress); err != nil {
+		return nil, translateError(err)
+	}
+	return &progress, nil
+}
+
+// GetProgressForMissions obtiene el progreso del usuario en las misiones
+// indicadas.
+func (s *MongoStore) GetProgressForMissions(userID primitive.ObjectID, missionIDs []primitive.ObjectID) ([]models.MissionProgress, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	cursor, err := collection.Find(ctx, bson.M{"userId": userID})
+	if len(missionIDs) == 0 {
+		return []models.MissionProgress{}, nil
+	}
+	cursor, err := collection.Find(ctx, s.scoped(bson.M{"userId": userID, "missionId": bson.M{"$in": missionIDs}}))
 	if err != nil {
 		return nil, err
 	}
-	var progress []models.MissionProgress
+	progress := []models.MissionProgress{}
 	if err = cursor.All(ctx, &progress); err != nil {
 		return nil, err
 	}
 	return progress, nil
 }
 
-// GetActiveMissions retorna las misiones con estado "iniciada" para un usuario.
-func GetActiveMissions(userID primitive.ObjectID) ([]models.MissionProgress, error) {
-	collection := GetMissionProgressCollection()
+// GetProgressSummary resume el progreso del usuario con una agregación, sin
+// traer sus documentos.
+func (s *MongoStore) GetProgressSummary(userID primitive.ObjectID) (ProgressSummary, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	cursor, err := collection.Find(ctx, bson.M{"userId": userID, "status": "iniciada"})
+
+	isCompleted := bson.M{"$eq": bson.A{"$status", models.ProgressCompleted}}
+	isActive := bson.M{"$in": bson.A{"$status", bson.A{models.ProgressStarted, models.ProgressPaused}}}
+	// $min y $max ignoran los null de los progresos no completados
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: s.scoped(bson.M{"userId": userID})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":             nil,
+			"started":         bson.M{"$sum": 1},
+			"active":          bson.M{"$sum": bson.M{"$cond": bson.A{isActive, 1, 0}}},
+			"completed":       bson.M{"$sum": bson.M{"$cond": bson.A{isCompleted, 1, 0}}},
+			"fastestMs":       bson.M{"$min": bson.M{"$cond": bson.A{isCompleted, activeDurationExpr, nil}}},
+			"lastCompletedAt": bson.M{"$max": bson.M{"$cond": bson.A{isCompleted, "$endDate", nil}}},
+		}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, err
+		return ProgressSummary{}, err
 	}
-	var active []models.MissionProgress
-	if err = cursor.All(ctx, &active); err != nil {
-		return nil, err
+	var results []struct {
+		Started         int       `bson:"started"`
+		Active          int       `bson:"active"`
+		Completed       int       `bson:"completed"`
+		FastestMs       int64     `bson:"fastestMs"`
+		LastCompletedAt time.Time `bson:"lastCompletedAt"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return ProgressSummary{}, err
 	}
-	return active, nil
+	if len(results) == 0 {
+		return ProgressSummary{}, nil
+	}
+	r := results[0]
+	return ProgressSummary{
+		Started:           r.Started,
+		Active:            r.Active,
+		Completed:         r.Completed,
+		FastestCompletion: time.Duration(r.FastestMs) * time.Millisecond,
+		LastCompletedAt:   r.LastCompletedAt,
+	}, nil
 }
 
-// GetCompletedMissions retorna las misiones con estado "completada" para un usuario.
-func GetCompletedMissions(userID primitive.ObjectID) ([]models.MissionProgress, error) {
-	collection := GetMissionProgressCollection()
+// CountCompletionsByDay agrupa en la base de datos las misiones completadas
+// por el día de su fecha de fin en la zona horaria loc.
+func (s *MongoStore) CountCompletionsByDay(userID primitive.ObjectID, since time.Time, loc *time.Location) (map[string]int, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	cursor, err := collection.Find(ctx, bson.M{"userId": userID, "status": "completada"})
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: s.scoped(bson.M{
+			"userId":  userID,
+			"status":  models.ProgressCompleted,
+			"endDate": bson.M{"$gte": since},
+		})}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$endDate",
+				"timezone": loc.String(),
+			}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
-	var completed []models.MissionProgress
-	if err = cursor.All(ctx, &completed); err != nil {
+	var results []struct {
+		Day   string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
 		return nil, err
 	}
-	return completed, nil
+	days := make(map[string]int, len(results))
+	for _, r := range results {
+		days[r.Day] = r.Count
+	}
+	return days, nil
 }
 
-// GetLeaderboard retorna un ranking de todos los usuarios basado en misiones completadas.
-// Incluye a los usuarios con 0 completadas.
-func GetLeaderboard() ([]bson.M, error) {
-	userCollection := Client.Database("explorax").Collection("users")
+// ListMissionProgress pagina el progreso del usuario en la base de datos, con
+// el _id como desempate para que cada página siga exactamente a query.After,
+// y trae la misión de cada progreso de la página con un $lookup.
+func (s *MongoStore) ListMissionProgress(query ProgressQuery) ([]ProgressWithMission, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	filter := bson.M{"userId": query.UserID}
+	if len(query.Statuses) > 0 {
+		filter["status"] = bson.M{"$in": query.Statuses}
+	}
+	field := query.Sort
+	if field == "" {
+		field = SortByStartDate
+	}
+	direction := 1
+	if query.SortDescending {
+		direction = -1
+	}
+	filter = s.scoped(filter)
+	if query.After != nil {
+		date := query.After.StartDate
+		if field == SortByEndDate {
+			date = query.After.EndDate
+		}
+		filter = bson.M{"$and": []bson.M{filter, keysetAfter(
+			sortKey{Field: field, Value: sortValue(date), Descending: query.SortDescending},
+			sortKey{Field: "_id", Value: query.After.ID},
+		)}}
+	}
 	pipeline := mongo.Pipeline{
-		// 1) Realiza un lookup para unir con "mission_progress"
-		bson.D{{Key: "$lookup", Value: bson.M{
-			"from":         "mission_progress",
-			"localField":   "_id",    // _id de users
-			"foreignField": "userId", // userId en mission_progress
-			"as":           "progress",
-		}}},
-		// 2) Desenrolla el array "progress" para contar cada registro individualmente
-		bson.D{{Key: "$unwind", Value: bson.M{
-			"path":                       "$progress",
-			"preserveNullAndEmptyArrays": true,
-		}}},
-		// 3) Agrupa por usuario y suma 1 para cada misión completada
-		bson.D{{Key: "$group", Value: bson.M{
-			"_id":      "$_id",
-			"username": bson.M{"$first": "$username"},
-			"email":    bson.M{"$first": "$email"},
-			"completedCount": bson.M{
-				"$sum": bson.M{
-					"$cond": []interface{}{
-						bson.M{"$eq": []interface{}{"$progress.status", "completada"}},
-						1,
-						0,
-					},
-				},
-			},
-		}}},
-		// 4) Ordena de mayor a menor por completedCount
-		bson.D{{Key: "$sort", Value: bson.M{"completedCount": -1}}},
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: field, Value: direction}, {Key: "_id", Value: 1}}}},
+	}
+	if query.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: query.Limit}})
+	}
+	// La misión se busca solo si se pidió y para los progresos de la página
+	// y, como en las estadísticas, sin filtrar por organización: puede ser
+	// global
+	if query.WithMission {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         "missions",
+				"localField":   "missionId",
+				"foreignField": "_id",
+				"as":           "mission",
+			}}},
+			bson.D{{Key: "$unwind", Value: bson.M{"path": "$mission", "preserveNullAndEmptyArrays": true}}},
+		)
 	}
 
-	cursor, err := userCollection.Aggregate(ctx, pipeline)
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
-	var leaderboard []bson.M
-	if err = cursor.All(ctx, &leaderboard); err != nil {
+	var docs []struct {
+		models.MissionProgress `bson:",inline"`
+		Mission                *models.Mission `bson:"mission"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
 		return nil, err
 	}
-	return leaderboard, nil
+	progress := make([]ProgressWithMission, 0, len(docs))
+	for _, doc := range docs {
+		progress = append(progress, ProgressWithMission{Progress: doc.MissionProgress, Mission: doc.Mission})
+	}
+	return progress, nil
+}
+
+// activeDurationExpr calcula el tiempo activo de un progreso completado en
+// milisegundos. Los progresos anteriores a las pausas no guardan activeDurationMs
+// y usan endDate - startDate.
+var activeDurationExpr = bson.M{
+	"$ifNull": []interface{}{
+		"$activeDurationMs",
+		bson.M{"$subtract": []interface{}{"$endDate", "$startDate"}},
+	},
 }
 
 // GetUserStatistics retorna estadísticas para un usuario, como total de misiones completadas y duración promedio.
-func GetUserStatistics(userID primitive.ObjectID) (bson.M, error) {
+func (s *MongoStore) GetUserStatistics(userID primitive.ObjectID) (bson.M, error) {
 	// Contexto para las consultas.
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// 1. Total de misiones completadas por el usuario.
-	progressCollection := GetMissionProgressCollection()
-	totalCompleted, err := progressCollection.CountDocuments(ctx, bson.M{
+	progressCollection := s.missionProgress()
+	totalCompleted, err := progressCollection.CountDocuments(ctx, s.scoped(bson.M{
 		"userId": userID,
-		"status": "completada",
-	})
+		"status": models.ProgressCompleted,
+	}))
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Calcular duración promedio de misiones completadas.
 	pipeline := mongo.Pipeline{
-		bson.D{{Key: "$match", Value: bson.M{
+		bson.D{{Key: "$match", Value: s.scoped(bson.M{
 			"userId": userID,
-			"status": "completada",
-		}}},
+			"status": models.ProgressCompleted,
+		})}},
 		bson.D{{Key: "$project", Value: bson.M{
-			"duration": bson.M{
-				"$subtract": []interface{}{"$endDate", "$startDate"},
-			},
+			"duration": activeDurationExpr,
 		}}},
 		bson.D{{Key: "$group", Value: bson.M{
 			"_id":             nil,
@@ -278,17 +1349,50 @@ func GetUserStatistics(userID primitive.ObjectID) (bson.M, error) {
 		avgDuration = 0
 	}
 
-	// 3. Total de misiones disponibles en el sistema.
-	missionsCollection := Client.Database("explorax").Collection("missions")
-	totalMissions, err := missionsCollection.CountDocuments(ctx, bson.M{})
+	// 3. Total de misiones disponibles (no archivadas) para la organización.
+	missionsCollection := s.missions()
+	totalMissions, err := missionsCollection.CountDocuments(ctx, s.visibleMissions(bson.M{"archived": bson.M{"$ne": true}}))
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Misiones disponibles distintas que el usuario completó; las archivadas
+	// o eliminadas no cuentan para el avance.
+	pipelineAvailable := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: s.scoped(bson.M{
+			"userId": userID,
+			"status": models.ProgressCompleted,
+		})}},
+		bson.D{{Key: "$group", Value: bson.M{"_id": "$missionId"}}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         "missions",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "mission",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$mission"}},
+		bson.D{{Key: "$match", Value: bson.M{"mission.archived": bson.M{"$ne": true}}}},
+		bson.D{{Key: "$count", Value: "count"}},
+	}
+	cursor, err = progressCollection.Aggregate(ctx, pipelineAvailable)
 	if err != nil {
 		return nil, err
 	}
+	var availableResults []struct {
+		Count int64 `bson:"count"`
+	}
+	if err = cursor.All(ctx, &availableResults); err != nil {
+		return nil, err
+	}
+	var completedAvailable int64
+	if len(availableResults) > 0 {
+		completedAvailable = availableResults[0].Count
+	}
 
-	// 4. Calcular porcentaje de avance.
+	// 5. Calcular porcentaje de avance.
 	progressPercentage := 0.0
 	if totalMissions > 0 {
-		progressPercentage = (float64(totalCompleted) / float64(totalMissions)) * 100
+		progressPercentage = (float64(completedAvailable) / float64(totalMissions)) * 100
 	}
 
 	// Retorna estadísticas del usuario.
@@ -299,31 +1403,31 @@ func GetUserStatistics(userID primitive.ObjectID) (bson.M, error) {
 	}, nil
 }
 
-func GetMissionByID(id primitive.ObjectID) (*models.Mission, error) {
-	collection := Client.Database("explorax").Collection("missions")
+func (s *MongoStore) GetMissionByID(id primitive.ObjectID) (*models.Mission, error) {
+	collection := s.missions()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	var mission models.Mission
-	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&mission)
+	err := collection.FindOne(ctx, s.visibleMissions(bson.M{"_id": id})).Decode(&mission)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &mission, nil
 }
 
-// GetMissionsOverview calcula estadísticas globales:
+// GetMissionsOverview calcula estadísticas de la organización:
 // - Misión más popular (mayor número de completadas).
 // - Tiempo promedio de finalización por misión.
-func GetMissionsOverview() (bson.M, error) {
-	collection := GetMissionProgressCollection()
+func (s *MongoStore) GetMissionsOverview() (bson.M, error) {
+	collection := s.missionProgress()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Pipeline para la misión más popular:
 	pipelineMostPopular := mongo.Pipeline{
 		// Filtra solo las misiones completadas.
-		bson.D{{Key: "$match", Value: bson.M{"status": "completada"}}},
+		bson.D{{Key: "$match", Value: s.scoped(bson.M{"status": models.ProgressCompleted})}},
 		// Agrupa por missionId y cuenta cuántas veces se completó.
 		bson.D{{Key: "$group", Value: bson.M{
 			"_id":   "$missionId",
@@ -342,12 +1446,14 @@ func GetMissionsOverview() (bson.M, error) {
 		}}},
 		// Desenrolla el array "mission".
 		bson.D{{Key: "$unwind", Value: "$mission"}},
-		// Proyecta el resultado final.
+		// Proyecta el resultado final. El overview es público: de la misión
+		// solo sale el título, nunca el cuestionario ni sus respuestas.
 		bson.D{{Key: "$project", Value: bson.M{
-			"_id":       0,
-			"missionId": "$_id",
-			"count":     1,
-			"mission":   1,
+			"_id":           0,
+			"missionId":     "$_id",
+			"count":         1,
+			"mission._id":   1,
+			"mission.title": 1,
 		}}},
 	}
 
@@ -370,12 +1476,12 @@ func GetMissionsOverview() (bson.M, error) {
 	// Pipeline para calcular el promedio de duración por misión:
 	pipelineAvgTime := mongo.Pipeline{
 		// Filtra solo las misiones completadas.
-		bson.D{{Key: "$match", Value: bson.M{"status": "completada"}}},
-		// Agrupa por missionId y calcula el promedio de (endDate - startDate).
+		bson.D{{Key: "$match", Value: s.scoped(bson.M{"status": models.ProgressCompleted})}},
+		// Agrupa por missionId y calcula el promedio del tiempo activo.
 		bson.D{{Key: "$group", Value: bson.M{
 			"_id": "$missionId",
 			"averageDuration": bson.M{
-				"$avg": bson.M{"$subtract": []interface{}{"$endDate", "$startDate"}},
+				"$avg": activeDurationExpr,
 			},
 			"count": bson.M{"$sum": 1},
 		}}},
@@ -393,7 +1499,8 @@ func GetMissionsOverview() (bson.M, error) {
 			"missionId":       "$_id",
 			"averageDuration": 1,
 			"count":           1,
-			"mission":         1,
+			"mission._id":     1,
+			"mission.title":   1,
 		}}},
 	}
 
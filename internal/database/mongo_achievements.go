@@ -0,0 +1,123 @@
+// /internal/database/mongo_achievements.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertAchievement inserta la definición de un logro.
+func (s *MongoStore) InsertAchievement(achievement models.Achievement) error {
+	collection := s.achievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	achievement.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, achievement)
+	return translateError(err)
+}
+
+// GetAchievements obtiene una página de las definiciones de logros ordenadas
+// por fecha de creación.
+func (s *MongoStore) GetAchievements(includeInactive bool, query ListQuery[models.Achievement]) ([]models.Achievement, error) {
+	collection := s.achievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{})
+	if !includeInactive {
+		filter["active"] = true
+	}
+	if query.After != nil {
+		filter = listAfter(filter, "createdAt", query.After.CreatedAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("createdAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	achievements := []models.Achievement{}
+	if err := cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+// UpdateAchievement aplica los cambios y devuelve la definición actualizada.
+func (s *MongoStore) UpdateAchievement(id primitive.ObjectID, update AchievementUpdate, at time.Time) (*models.Achievement, error) {
+	collection := s.achievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"updatedAt": at}
+	if update.Name != nil {
+		set["name"] = *update.Name
+	}
+	if update.Description != nil {
+		set["description"] = *update.Description
+	}
+	if update.Rule != nil {
+		set["rule"] = *update.Rule
+	}
+	if update.Active != nil {
+		set["active"] = *update.Active
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var achievement models.Achievement
+	err := collection.FindOneAndUpdate(ctx, s.scoped(bson.M{"_id": id}), bson.M{"$set": set}, opts).Decode(&achievement)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &achievement, nil
+}
+
+// DeleteAchievement elimina la definición de un logro. Los logros ya
+// otorgados se conservan.
+func (s *MongoStore) DeleteAchievement(id primitive.ObjectID) error {
+	collection := s.achievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.DeleteOne(ctx, s.scoped(bson.M{"_id": id}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// InsertUserAchievement otorga un logro a un usuario.
+func (s *MongoStore) InsertUserAchievement(award models.UserAchievement) error {
+	collection := s.userAchievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	award.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, award)
+	return translateError(err)
+}
+
+// GetUserAchievements obtiene una página de los logros del usuario por fecha
+// de obtención.
+func (s *MongoStore) GetUserAchievements(userID primitive.ObjectID, query ListQuery[models.UserAchievement]) ([]models.UserAchievement, error) {
+	collection := s.userAchievements()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"userId": userID})
+	if query.After != nil {
+		filter = listAfter(filter, "awardedAt", query.After.AwardedAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("awardedAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	awards := []models.UserAchievement{}
+	if err := cursor.All(ctx, &awards); err != nil {
+		return nil, err
+	}
+	return awards, nil
+}
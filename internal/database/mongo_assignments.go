@@ -0,0 +1,72 @@
+// /internal/database/mongo_assignments.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertAssignment inserta una misión asignada.
+func (s *MongoStore) InsertAssignment(assignment models.Assignment) error {
+	collection := s.assignments()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assignment.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, assignment)
+	return translateError(err)
+}
+
+// GetAssignmentByID obtiene una asignación por su ID.
+func (s *MongoStore) GetAssignmentByID(id primitive.ObjectID) (*models.Assignment, error) {
+	collection := s.assignments()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var assignment models.Assignment
+	if err := collection.FindOne(ctx, s.scoped(bson.M{"_id": id})).Decode(&assignment); err != nil {
+		return nil, translateError(err)
+	}
+	return &assignment, nil
+}
+
+// GetTeacherAssignments obtiene una página de las asignaciones creadas por el
+// docente.
+func (s *MongoStore) GetTeacherAssignments(teacherID primitive.ObjectID, query ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	return s.findAssignments(bson.M{"teacherId": teacherID}, query)
+}
+
+// GetStudentAssignments obtiene una página de las asignaciones abiertas del
+// estudiante y de sus clases.
+func (s *MongoStore) GetStudentAssignments(studentID primitive.ObjectID, classroomIDs []primitive.ObjectID, openAt time.Time, query ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	filter := bson.M{"$or": []bson.M{
+		{"studentId": studentID},
+		{"classroomId": bson.M{"$in": append([]primitive.ObjectID{}, classroomIDs...)}},
+	}}
+	if !openAt.IsZero() {
+		filter["opensAt"] = bson.M{"$lte": openAt}
+	}
+	return s.findAssignments(filter, query)
+}
+
+func (s *MongoStore) findAssignments(filter bson.M, query ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	collection := s.assignments()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter = s.scoped(filter)
+	if query.After != nil {
+		filter = listAfter(filter, "dueAt", query.After.DueAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("dueAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	assignments := []models.Assignment{}
+	if err := cursor.All(ctx, &assignments); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
@@ -0,0 +1,101 @@
+// /internal/database/mongo_classrooms.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertClassroom inserta una clase nueva.
+func (s *MongoStore) InsertClassroom(classroom models.Classroom) error {
+	collection := s.classrooms()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// $addToSet falla sobre un campo nulo, así que la lista se guarda vacía
+	if classroom.StudentIDs == nil {
+		classroom.StudentIDs = []primitive.ObjectID{}
+	}
+	classroom.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, classroom)
+	return translateError(err)
+}
+
+// GetClassroomByID obtiene una clase por su ID.
+func (s *MongoStore) GetClassroomByID(id primitive.ObjectID) (*models.Classroom, error) {
+	return s.findClassroom(bson.M{"_id": id})
+}
+
+// FindClassroomByJoinCode busca una clase por su código.
+func (s *MongoStore) FindClassroomByJoinCode(code string) (*models.Classroom, error) {
+	return s.findClassroom(bson.M{"joinCode": code})
+}
+
+func (s *MongoStore) findClassroom(filter bson.M) (*models.Classroom, error) {
+	collection := s.classrooms()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var classroom models.Classroom
+	if err := collection.FindOne(ctx, s.scoped(filter)).Decode(&classroom); err != nil {
+		return nil, translateError(err)
+	}
+	return &classroom, nil
+}
+
+// GetTeacherClassrooms obtiene una página de las clases del docente.
+func (s *MongoStore) GetTeacherClassrooms(teacherID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	return s.findClassrooms(bson.M{"teacherId": teacherID}, query)
+}
+
+// GetStudentClassrooms obtiene una página de las clases en que está inscrito
+// el estudiante.
+func (s *MongoStore) GetStudentClassrooms(studentID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	return s.findClassrooms(bson.M{"studentIds": studentID}, query)
+}
+
+func (s *MongoStore) findClassrooms(filter bson.M, query ListQuery[models.Classroom]) ([]models.Classroom, error) {
+	collection := s.classrooms()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter = s.scoped(filter)
+	if query.After != nil {
+		filter = listAfter(filter, "createdAt", query.After.CreatedAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("createdAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	classrooms := []models.Classroom{}
+	if err := cursor.All(ctx, &classrooms); err != nil {
+		return nil, err
+	}
+	return classrooms, nil
+}
+
+// AddClassroomStudent inscribe al estudiante en la clase.
+func (s *MongoStore) AddClassroomStudent(classroomID, studentID primitive.ObjectID) error {
+	return s.updateClassroomStudents(classroomID, bson.M{"$addToSet": bson.M{"studentIds": studentID}})
+}
+
+// RemoveClassroomStudent da de baja al estudiante de la clase.
+func (s *MongoStore) RemoveClassroomStudent(classroomID, studentID primitive.ObjectID) error {
+	return s.updateClassroomStudents(classroomID, bson.M{"$pull": bson.M{"studentIds": studentID}})
+}
+
+func (s *MongoStore) updateClassroomStudents(classroomID primitive.ObjectID, update bson.M) error {
+	collection := s.classrooms()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.UpdateOne(ctx, s.scoped(bson.M{"_id": classroomID}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// /internal/database/mongo_leaderboard.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// leaderboardDoc es la entrada materializada de un usuario en un ranking.
+type leaderboardDoc struct {
+	TenantID       string             `bson:"tenantId"`
+	Bucket         string             `bson:"bucket"`
+	UserID         primitive.ObjectID `bson:"userId"`
+	Hidden         bool               `bson:"hidden"`
+	XP             int                `bson:"xp"`
+	CompletedCount int                `bson:"completedCount"`
+	ReachedAt      time.Time          `bson:"reachedAt"`
+}
+
+// leaderboardBuckets devuelve los rankings que cuentan una misión completada en at.
+func leaderboardBuckets(at time.Time) []string {
+	buckets := make([]string, 0, len(models.LeaderboardPeriods))
+	for _, period := range models.LeaderboardPeriods {
+		bucket, _ := models.LeaderboardBucket(period, at)
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+// leaderboardSort es el orden de models.LeaderboardEntry.Precedes; con
+// reverse se invierte. Coincide con el índice tenant_bucket_ranking.
+func leaderboardSort(reverse bool) bson.D {
+	dir := 1
+	if reverse {
+		dir = -1
+	}
+	return bson.D{
+		{Key: "xp", Value: -dir},
+		{Key: "completedCount", Value: -dir},
+		{Key: "reachedAt", Value: dir},
+		{Key: "userId", Value: dir},
+	}
+}
+
+// leaderboardAfter filtra las entradas que van después de entry en el
+// ranking; con before, las que van antes.
+func leaderboardAfter(entry models.LeaderboardEntry, before bool) bson.M {
+	less, greater := "$lt", "$gt"
+	if before {
+		less, greater = greater, less
+	}
+	return bson.M{"$or": []bson.M{
+		{"xp": bson.M{less: entry.XP}},
+		{"xp": entry.XP, "completedCount": bson.M{less: entry.CompletedCount}},
+		{"xp": entry.XP, "completedCount": entry.CompletedCount, "reachedAt": bson.M{greater: entry.ReachedAt}},
+		{"xp": entry.XP, "completedCount": entry.CompletedCount, "reachedAt": entry.ReachedAt, "userId": bson.M{greater: entry.UserID}},
+	}}
+}
+
+// withUsers completa las entradas con el perfil público y la XP total del
+// usuario. Se aplica después de $limit para unir solo los usuarios de la
+// página, y proyecta solo esos campos para que ningún dato personal salga de
+// la base de datos.
+var withUsers = []bson.D{
+	{{Key: "$lookup", Value: bson.M{
+		"from":         "users",
+		"localField":   "userId",
+		"foreignField": "_id",
+		"as":           "user",
+	}}},
+	{{Key: "$unwind", Value: "$user"}},
+	{{Key: "$project", Value: bson.M{
+		"_id":              "$userId",
+		"xp":               1,
+		"completedCount":   1,
+		"reachedAt":        1,
+		"user._id":         1,
+		"user.xp":          1,
+		"user.birthDate":   1,
+		"user.displayName": 1,
+		"user.avatar":      1,
+	}}},
+}
+
+// RecordLeaderboardCompletion suma la misión a los rankings con un upsert
+// por periodo.
+func (s *MongoStore) RecordLeaderboardCompletion(userID primitive.ObjectID, xp int, at time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"hideFromLeaderboard": 1})
+	if err := s.users().FindOne(ctx, s.scoped(bson.M{"_id": userID}), opts).Decode(&user); err != nil {
+		return translateError(err)
+	}
+
+	var writes []mongo.WriteModel
+	for _, bucket := range leaderboardBuckets(at) {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(s.scoped(bson.M{"bucket": bucket, "userId": userID})).
+			SetUpdate(bson.M{
+				"$inc":         bson.M{"xp": xp, "completedCount": 1},
+				"$max":         bson.M{"reachedAt": at},
+				"$setOnInsert": bson.M{"hidden": user.HideFromLeaderboard},
+			}).
+			SetUpsert(true))
+	}
+	_, err := s.leaderboard().BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// GetLeaderboard devuelve una página del ranking leyendo el índice
+// tenant_bucket_ranking, sin recorrer el progreso de las misiones.
+func (s *MongoStore) GetLeaderboard(query LeaderboardQuery) ([]models.LeaderboardEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	match := s.scoped(bson.M{"bucket": query.Bucket, "hidden": false})
+	var keyset []bson.M
+	if query.After != nil {
+		keyset = append(keyset, leaderboardAfter(*query.After, false))
+	}
+	if query.Before != nil {
+		keyset = append(keyset, leaderboardAfter(*query.Before, true))
+	}
+	if len(keyset) > 0 {
+		match["$and"] = keyset
+	}
+
+	reverse := query.Before != nil
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$sort", Value: leaderboardSort(reverse)}},
+	}
+	if query.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: query.Limit}})
+	}
+	pipeline = append(pipeline, withUsers...)
+
+	cursor, err := s.leaderboard().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	leaderboard := []models.LeaderboardEntry{}
+	if err = cursor.All(ctx, &leaderboard); err != nil {
+		return nil, err
+	}
+	if reverse {
+		for i, j := 0, len(leaderboard)-1; i < j; i, j = i+1, j-1 {
+			leaderboard[i], leaderboard[j] = leaderboard[j], leaderboard[i]
+		}
+	}
+	return leaderboard, nil
+}
+
+// GetLeaderboardPosition devuelve la entrada del usuario y cuenta, con el
+// mismo índice, cuántas entradas la preceden. Como en GetLeaderboard, no
+// cuentan las de usuarios que ya no existen.
+func (s *MongoStore) GetLeaderboardPosition(userID primitive.ObjectID, bucket string) (*models.LeaderboardEntry, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := append(mongo.Pipeline{
+		bson.D{{Key: "$match", Value: s.scoped(bson.M{"bucket": bucket, "userId": userID, "hidden": false})}},
+	}, withUsers...)
+	cursor, err := s.leaderboard().Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []models.LeaderboardEntry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+	if len(entries) == 0 {
+		return nil, 0, ErrNotFound
+	}
+	entry := entries[0]
+
+	filter := s.scoped(leaderboardAfter(entry, true))
+	filter["bucket"] = bucket
+	filter["hidden"] = false
+	cursor, err = s.leaderboard().Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "users",
+			"localField":   "userId",
+			"foreignField": "_id",
+			"as":           "user",
+		}}},
+		{{Key: "$match", Value: bson.M{"user.0": bson.M{"$exists": true}}}},
+		{{Key: "$count", Value: "ahead"}},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	var counts []struct {
+		Ahead int `bson:"ahead"`
+	}
+	if err = cursor.All(ctx, &counts); err != nil {
+		return nil, 0, err
+	}
+	ahead := 0
+	if len(counts) > 0 {
+		ahead = counts[0].Ahead
+	}
+	return &entry, ahead, nil
+}
+
+// RebuildLeaderboard recalcula los rankings de la organización a partir de
+// las misiones completadas y reemplaza sus entradas. Las misiones que se
+// completen mientras corre pueden perderse, así que conviene ejecutarlo con
+// la API detenida.
+func (s *MongoStore) RebuildLeaderboard() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	hidden := map[primitive.ObjectID]bool{}
+	values, err := s.users().Distinct(ctx, "_id", s.scoped(bson.M{"hideFromLeaderboard": true}))
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			hidden[id] = true
+		}
+	}
+
+	opts := options.Find().SetProjection(bson.M{"userId": 1, "xpAwarded": 1, "endDate": 1})
+	cursor, err := s.missionProgress().Find(ctx, s.scoped(bson.M{"status": models.ProgressCompleted}), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	docs := map[string]*leaderboardDoc{}
+	for cursor.Next(ctx) {
+		var p models.MissionProgress
+		if err := cursor.Decode(&p); err != nil {
+			return err
+		}
+		for _, bucket := range leaderboardBuckets(p.EndDate) {
+			key := bucket + "/" + p.UserID.Hex()
+			doc, ok := docs[key]
+			if !ok {
+				doc = &leaderboardDoc{TenantID: s.tenant, Bucket: bucket, UserID: p.UserID, Hidden: hidden[p.UserID]}
+				docs[key] = doc
+			}
+			doc.XP += p.XPAwarded
+			doc.CompletedCount++
+			if p.EndDate.After(doc.ReachedAt) {
+				doc.ReachedAt = p.EndDate
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	collection := s.leaderboard()
+	if _, err := collection.DeleteMany(ctx, s.scoped(bson.M{})); err != nil {
+		return err
+	}
+	const batchSize = 1000
+	batch := make([]interface{}, 0, batchSize)
+	for _, doc := range docs {
+		batch = append(batch, doc)
+		if len(batch) == batchSize {
+			if _, err := collection.InsertMany(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if _, err := collection.InsertMany(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// /internal/database/mongo_paths.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InsertPath inserta una ruta de aprendizaje.
+func (s *MongoStore) InsertPath(path models.LearningPath) error {
+	collection := s.learningPaths()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	path.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, path)
+	return translateError(err)
+}
+
+// GetPaths obtiene una página de las rutas de la organización por fecha de
+// creación.
+func (s *MongoStore) GetPaths(query ListQuery[models.LearningPath]) ([]models.LearningPath, error) {
+	collection := s.learningPaths()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{})
+	if query.After != nil {
+		filter = listAfter(filter, "createdAt", query.After.CreatedAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("createdAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	paths := []models.LearningPath{}
+	if err := cursor.All(ctx, &paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// GetPathByID obtiene una ruta por su ID.
+func (s *MongoStore) GetPathByID(id primitive.ObjectID) (*models.LearningPath, error) {
+	collection := s.learningPaths()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var path models.LearningPath
+	if err := collection.FindOne(ctx, s.scoped(bson.M{"_id": id})).Decode(&path); err != nil {
+		return nil, translateError(err)
+	}
+	return &path, nil
+}
+
+// ReplacePath reemplaza los datos editables de la ruta.
+func (s *MongoStore) ReplacePath(path models.LearningPath) error {
+	collection := s.learningPaths()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	update := bson.M{"$set": bson.M{
+		"title":       path.Title,
+		"description": path.Description,
+		"chapters":    path.Chapters,
+		"updatedAt":   path.UpdatedAt,
+	}}
+	result, err := collection.UpdateOne(ctx, s.scoped(bson.M{"_id": path.ID}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeletePath elimina una ruta; las misiones no se modifican.
+func (s *MongoStore) DeletePath(id primitive.ObjectID) error {
+	collection := s.learningPaths()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := collection.DeleteOne(ctx, s.scoped(bson.M{"_id": id}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
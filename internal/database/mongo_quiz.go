@@ -0,0 +1,67 @@
+// /internal/database/mongo_quiz.go
+package database
+
+import (
+	"context"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertQuizAttempt guarda un intento calificado de un cuestionario.
+func (s *MongoStore) InsertQuizAttempt(attempt models.QuizAttempt) error {
+	collection := s.quizAttempts()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	attempt.TenantID = s.tenant
+	_, err := collection.InsertOne(ctx, attempt)
+	return translateError(err)
+}
+
+// GetQuizAttempts obtiene una página de los intentos de un usuario en una
+// misión en orden cronológico.
+func (s *MongoStore) GetQuizAttempts(userID, missionID primitive.ObjectID, query ListQuery[models.QuizAttempt]) ([]models.QuizAttempt, error) {
+	collection := s.quizAttempts()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"userId": userID, "missionId": missionID})
+	if query.After != nil {
+		filter = listAfter(filter, "submittedAt", query.After.SubmittedAt, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("submittedAt", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	attempts := []models.QuizAttempt{}
+	if err := cursor.All(ctx, &attempts); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// GetQuizAttemptSummary cuenta los intentos de un usuario en una misión y
+// comprueba si alguno aprobó, sin leerlos.
+func (s *MongoStore) GetQuizAttemptSummary(userID, missionID primitive.ObjectID) (QuizAttemptSummary, error) {
+	collection := s.quizAttempts()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := s.scoped(bson.M{"userId": userID, "missionId": missionID})
+	attempts, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return QuizAttemptSummary{}, err
+	}
+	summary := QuizAttemptSummary{Attempts: int(attempts)}
+	if attempts > 0 {
+		filter["passed"] = true
+		passed, err := collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		if err != nil {
+			return QuizAttemptSummary{}, err
+		}
+		summary.Passed = passed > 0
+	}
+	return summary, nil
+}
@@ -0,0 +1,63 @@
+// /internal/database/mongo_tenants.go
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// defaultTenant es la organización DefaultTenantID cuando no tiene documento.
+var defaultTenant = models.Tenant{ID: models.DefaultTenantID, Name: "ExploraX"}
+
+// InsertTenant registra una organización.
+func (s *MongoStore) InsertTenant(tenant models.Tenant) error {
+	collection := s.tenants()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := collection.InsertOne(ctx, tenant)
+	return translateError(err)
+}
+
+// GetTenant busca una organización por su ID.
+func (s *MongoStore) GetTenant(id string) (*models.Tenant, error) {
+	collection := s.tenants()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var tenant models.Tenant
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&tenant)
+	if err != nil {
+		err = translateError(err)
+		if errors.Is(err, ErrNotFound) && id == models.DefaultTenantID {
+			tenant := defaultTenant
+			return &tenant, nil
+		}
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+// GetTenants devuelve una página de las organizaciones registradas ordenadas
+// por ID.
+func (s *MongoStore) GetTenants(query ListQuery[models.Tenant]) ([]models.Tenant, error) {
+	collection := s.tenants()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	filter := bson.M{}
+	if query.After != nil {
+		filter = listAfter(filter, "_id", nil, query.After.ID)
+	}
+	cursor, err := collection.Find(ctx, filter, listOptions("_id", query.Limit))
+	if err != nil {
+		return nil, err
+	}
+	tenants := []models.Tenant{}
+	if err = cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
@@ -4,6 +4,7 @@ package database_test
 import (
 	"context"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,279 +14,327 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func setup(t *testing.T) {
-	// Asegúrate de tener MONGO_URI configurado para tests.
-	if os.Getenv("MONGO_URI") == "" {
-		// Por ejemplo, usa un valor por defecto para test.
-		os.Setenv("MONGO_URI", "mongodb://localhost:27017")
+// testDatabase es la base de datos que se borra y recrea en cada prueba.
+const testDatabase = "explorax_test"
+
+var (
+	mongoOnce   sync.Once
+	mongoClient *mongo.Client
+	mongoErr    error
+)
+
+// connectMongo abre una única conexión para toda la ejecución de pruebas.
+func connectMongo() (*mongo.Client, error) {
+	mongoOnce.Do(func() {
+		// Asegúrate de tener MONGO_URI configurado para tests.
+		uri := os.Getenv("MONGO_URI")
+		if uri == "" {
+			// Por ejemplo, usa un valor por defecto para test.
+			uri = "mongodb://localhost:27017"
+		}
+		mongoClient, mongoErr = database.Dial(uri)
+	})
+	return mongoClient, mongoErr
+}
+
+// setupMongo devuelve un MongoStore sobre una base de datos de prueba vacía, u
+// omite la prueba si no hay un MongoDB disponible.
+func setupMongo(t *testing.T) database.Store {
+	client, err := connectMongo()
+	if err != nil {
+		t.Skipf("MongoDB no disponible: %v", err)
 	}
-	// Conecta a MongoDB.
-	database.Connect()
 	// Limpia la base de datos de prueba.
-	err := database.Client.Database("explorax").Drop(context.Background())
-	require.NoError(t, err)
+	db := client.Database(testDatabase)
+	require.NoError(t, db.Drop(context.Background()))
+	store := database.NewMongoStore(db)
+	require.NoError(t, store.EnsureIndexes())
+	return store
+}
+
+// forEachStore ejecuta la prueba contra cada implementación de Store para
+// garantizar que MemoryStore se comporta igual que MongoStore.
+func forEachStore(t *testing.T, test func(t *testing.T, store database.Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, database.NewMemoryStore())
+	})
+	t.Run("mongo", func(t *testing.T) {
+		test(t, setupMongo(t))
+	})
 }
 
 func TestConnect(t *testing.T) {
-	setup(t)
-	require.NotNil(t, database.Client)
+	client, err := connectMongo()
+	if err != nil {
+		t.Skipf("MongoDB no disponible: %v", err)
+	}
+	require.NotNil(t, client)
 	// Opcionalmente, podemos ejecutar un ping a la DB.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err := database.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err()
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "ping", Value: 1}}).Err()
 	require.NoError(t, err)
 }
 
 func TestInsertAndFindUser(t *testing.T) {
-	setup(t)
-
-	user := models.User{
-		ID:           primitive.NewObjectID(),
-		Username:     "testuser",
-		Email:        "test@example.com",
-		PasswordHash: "hashedpassword",
-		CreatedAt:    time.Now(),
-	}
-	err := database.InsertUser(user)
-	require.NoError(t, err)
-
-	foundUser, err := database.FindUserByEmail("test@example.com")
-	require.NoError(t, err)
-	require.NotNil(t, foundUser)
-	require.Equal(t, user.Username, foundUser.Username)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user := models.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "testuser",
+			Email:        "test@example.com",
+			PasswordHash: "hashedpassword",
+			CreatedAt:    time.Now(),
+		}
+		err := store.InsertUser(user)
+		require.NoError(t, err)
+
+		foundUser, err := store.FindUserByEmail("test@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, foundUser)
+		require.Equal(t, user.Username, foundUser.Username)
+	})
 }
 
 func TestFindUserByEmailNotFound(t *testing.T) {
-	setup(t)
-
-	user, err := database.FindUserByEmail("nonexistent@example.com")
-	require.Error(t, err)
-	require.Nil(t, user)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user, err := store.FindUserByEmail("nonexistent@example.com")
+		require.Error(t, err)
+		require.Nil(t, user)
+	})
 }
 
 func TestInsertDuplicateUser(t *testing.T) {
-	setup(t)
-
-	user := models.User{
-		ID:           primitive.NewObjectID(),
-		Username:     "testuser",
-		Email:        "duplicate@example.com",
-		PasswordHash: "hashedpassword",
-		CreatedAt:    time.Now(),
-	}
-
-	err := database.InsertUser(user)
-	require.NoError(t, err)
-
-	// Try to insert the same user again
-	err = database.InsertUser(user)
-	require.Error(t, err)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user := models.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "testuser",
+			Email:        "duplicate@example.com",
+			PasswordHash: "hashedpassword",
+			CreatedAt:    time.Now(),
+		}
+
+		err := store.InsertUser(user)
+		require.NoError(t, err)
+
+		// Try to insert the same user again
+		err = store.InsertUser(user)
+		require.Error(t, err)
+	})
 }
 
 func TestInsertMissionAndGetAllMissions(t *testing.T) {
-	setup(t)
-
-	mission := models.Mission{
-		ID:          primitive.NewObjectID(),
-		Title:       "Test Mission",
-		Description: "Test Description",
-		CreatedAt:   time.Now(),
-	}
-	err := database.InsertMission(mission)
-	require.NoError(t, err)
-
-	missions, err := database.GetAllMissions()
-	require.NoError(t, err)
-	require.NotEmpty(t, missions)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		mission := models.Mission{
+			ID:          primitive.NewObjectID(),
+			Title:       "Test Mission",
+			Description: "Test Description",
+			CreatedAt:   time.Now(),
+		}
+		err := store.InsertMission(mission)
+		require.NoError(t, err)
+
+		missions, err := store.GetAllMissions(false)
+		require.NoError(t, err)
+		require.NotEmpty(t, missions)
+	})
 }
 
 func TestInsertAndUpdateMissionProgress(t *testing.T) {
-	setup(t)
-
-	userID := primitive.NewObjectID()
-	missionID := primitive.NewObjectID()
-
-	progress := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		MissionID: missionID,
-		Status:    "iniciada",
-		StartDate: time.Now(),
-	}
-	err := database.InsertMissionProgress(progress)
-	require.NoError(t, err)
-
-	// Actualiza el progreso a "completada".
-	err = database.UpdateMissionProgress(userID, missionID)
-	require.NoError(t, err)
-
-	// Recupera el progreso y verifica el cambio.
-	progs, err := database.GetMissionProgress(userID)
-	require.NoError(t, err)
-	require.NotEmpty(t, progs)
-	require.Equal(t, "completada", progs[0].Status)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		missionID := primitive.NewObjectID()
+
+		progress := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: missionID,
+			Status:    "iniciada",
+			StartDate: time.Now(),
+		}
+		err := store.InsertMissionProgress(progress)
+		require.NoError(t, err)
+
+		// Actualiza el progreso a "completada".
+		require.NoError(t, progress.Apply(models.ActionComplete, time.Now()))
+		err = store.UpdateMissionProgress(progress, models.ProgressStarted)
+		require.NoError(t, err)
+
+		// Recupera el progreso y verifica el cambio.
+		progs, err := store.GetProgressForMissions(userID, []primitive.ObjectID{missionID})
+		require.NoError(t, err)
+		require.Len(t, progs, 1)
+		require.Equal(t, models.ProgressCompleted, progs[0].Status)
+	})
 }
 
 func TestUpdateMissionProgressInvalidStatus(t *testing.T) {
-	setup(t)
-
-	userID := primitive.NewObjectID()
-	missionID := primitive.NewObjectID()
-
-	// Try to update progress for non-existent mission
-	err := database.UpdateMissionProgress(userID, missionID)
-	require.Error(t, err)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		missionID := primitive.NewObjectID()
+
+		// Try to update progress for non-existent mission
+		progress := models.NewMissionProgress(userID, missionID, time.Now())
+		err := store.UpdateMissionProgress(progress, models.ProgressStarted)
+		require.Error(t, err)
+	})
 }
 
-func TestGetActiveAndCompletedMissions(t *testing.T) {
-	setup(t)
-
-	userID := primitive.NewObjectID()
-
-	// Inserta dos progresos: uno iniciado y otro completado.
-	progressActive := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		MissionID: primitive.NewObjectID(),
-		Status:    "iniciada",
-		StartDate: time.Now(),
-	}
-	progressCompleted := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		MissionID: primitive.NewObjectID(),
-		Status:    "completada",
-		StartDate: time.Now().Add(-time.Hour),
-	}
-	err := database.InsertMissionProgress(progressActive)
-	require.NoError(t, err)
-	err = database.InsertMissionProgress(progressCompleted)
-	require.NoError(t, err)
-
-	active, err := database.GetActiveMissions(userID)
-	require.NoError(t, err)
-	require.Len(t, active, 1)
-	require.Equal(t, "iniciada", active[0].Status)
-
-	completed, err := database.GetCompletedMissions(userID)
-	require.NoError(t, err)
-	require.Len(t, completed, 1)
-	require.Equal(t, "completada", completed[0].Status)
+func TestGetProgressSummary(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+
+		// Inserta dos progresos: uno iniciado y otro completado.
+		progressActive := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: primitive.NewObjectID(),
+			Status:    "iniciada",
+			StartDate: time.Now(),
+		}
+		progressCompleted := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: primitive.NewObjectID(),
+			Status:    "completada",
+			StartDate: time.Now().Add(-time.Hour).Truncate(time.Millisecond),
+		}
+		progressCompleted.EndDate = progressCompleted.StartDate.Add(10 * time.Minute)
+		progressCompleted.ActiveDurationMs = (10 * time.Minute).Milliseconds()
+		err := store.InsertMissionProgress(progressActive)
+		require.NoError(t, err)
+		err = store.InsertMissionProgress(progressCompleted)
+		require.NoError(t, err)
+
+		summary, err := store.GetProgressSummary(userID)
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.Started)
+		require.Equal(t, 1, summary.Active)
+		require.Equal(t, 1, summary.Completed)
+		require.Equal(t, 10*time.Minute, summary.FastestCompletion)
+		require.True(t, progressCompleted.EndDate.Equal(summary.LastCompletedAt))
+
+		empty, err := store.GetProgressSummary(primitive.NewObjectID())
+		require.NoError(t, err)
+		require.Zero(t, empty.Started)
+		require.Zero(t, empty.Completed)
+	})
 }
 
 func TestGetLeaderboard(t *testing.T) {
-	setup(t)
-
-	// Para este test, insertamos al menos un usuario y algún progreso.
-	// Inserta un usuario.
-	user := models.User{
-		ID:           primitive.NewObjectID(),
-		Username:     "leader",
-		Email:        "leader@example.com",
-		PasswordHash: "pass",
-		CreatedAt:    time.Now(),
-	}
-	err := database.InsertUser(user)
-	require.NoError(t, err)
-
-	// Inserta un progreso completado para ese usuario.
-	progress := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    user.ID,
-		MissionID: primitive.NewObjectID(),
-		Status:    "completada",
-		StartDate: time.Now().Add(-time.Hour),
-	}
-	err = database.InsertMissionProgress(progress)
-	require.NoError(t, err)
-
-	leaderboard, err := database.GetLeaderboard()
-	require.NoError(t, err)
-	require.IsType(t, []bson.M{}, leaderboard)
-	// Dependiendo de la agregación, el leaderboard podría contener al menos al usuario "leader".
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		// Para este test, insertamos al menos un usuario y algún progreso.
+		// Inserta un usuario.
+		user := models.User{
+			ID:           primitive.NewObjectID(),
+			Username:     "leader",
+			Email:        "leader@example.com",
+			PasswordHash: "pass",
+			CreatedAt:    time.Now(),
+		}
+		err := store.InsertUser(user)
+		require.NoError(t, err)
+
+		// Inserta un progreso completado para ese usuario.
+		progress := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID,
+			MissionID: primitive.NewObjectID(),
+			Status:    "completada",
+			StartDate: time.Now().Add(-time.Hour),
+		}
+		err = store.InsertMissionProgress(progress)
+		require.NoError(t, err)
+		require.NoError(t, store.RebuildLeaderboard())
+
+		leaderboard, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 1)
+		require.Equal(t, user.ID, leaderboard[0].UserID)
+	})
 }
 
 func TestGetUserStatistics(t *testing.T) {
-	setup(t)
-
-	userID := primitive.NewObjectID()
-	// Inserta un progreso completado para este usuario.
-	progress := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		MissionID: primitive.NewObjectID(),
-		Status:    "completada",
-		StartDate: time.Now().Add(-2 * time.Hour),
-		EndDate:   time.Now(),
-	}
-	err := database.InsertMissionProgress(progress)
-	require.NoError(t, err)
-
-	stats, err := database.GetUserStatistics(userID)
-	require.NoError(t, err)
-	require.NotNil(t, stats)
-	require.Contains(t, stats, "totalCompleted")
-	require.Contains(t, stats, "averageDuration")
-	require.Contains(t, stats, "progressPercentage")
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		// Inserta un progreso completado para este usuario.
+		progress := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: primitive.NewObjectID(),
+			Status:    "completada",
+			StartDate: time.Now().Add(-2 * time.Hour),
+			EndDate:   time.Now(),
+		}
+		err := store.InsertMissionProgress(progress)
+		require.NoError(t, err)
+
+		stats, err := store.GetUserStatistics(userID)
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		require.Contains(t, stats, "totalCompleted")
+		require.Contains(t, stats, "averageDuration")
+		require.Contains(t, stats, "progressPercentage")
+	})
 }
 
 func TestGetMissionByID(t *testing.T) {
-	setup(t)
-
-	mission := models.Mission{
-		ID:          primitive.NewObjectID(),
-		Title:       "Test Mission",
-		Description: "Description",
-		CreatedAt:   time.Now(),
-	}
-	err := database.InsertMission(mission)
-	require.NoError(t, err)
-
-	fetched, err := database.GetMissionByID(mission.ID)
-	require.NoError(t, err)
-	require.NotNil(t, fetched)
-	require.Equal(t, mission.Title, fetched.Title)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		mission := models.Mission{
+			ID:          primitive.NewObjectID(),
+			Title:       "Test Mission",
+			Description: "Description",
+			CreatedAt:   time.Now(),
+		}
+		err := store.InsertMission(mission)
+		require.NoError(t, err)
+
+		fetched, err := store.GetMissionByID(mission.ID)
+		require.NoError(t, err)
+		require.NotNil(t, fetched)
+		require.Equal(t, mission.Title, fetched.Title)
+	})
 }
 
 func TestGetMissionByIDNotFound(t *testing.T) {
-	setup(t)
-
-	nonExistentID := primitive.NewObjectID()
-	mission, err := database.GetMissionByID(nonExistentID)
-	require.Error(t, err)
-	require.Nil(t, mission)
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		nonExistentID := primitive.NewObjectID()
+		mission, err := store.GetMissionByID(nonExistentID)
+		require.Error(t, err)
+		require.Nil(t, mission)
+	})
 }
 
 func TestGetMissionsOverview(t *testing.T) {
-	setup(t)
-
-	// Inserta una misión.
-	mission := models.Mission{
-		ID:          primitive.NewObjectID(),
-		Title:       "Overview Mission",
-		Description: "Test overview",
-		CreatedAt:   time.Now(),
-	}
-	err := database.InsertMission(mission)
-	require.NoError(t, err)
-
-	// Inserta un progreso completado para la misión.
-	userID := primitive.NewObjectID()
-	progress := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userID,
-		MissionID: mission.ID,
-		Status:    "completada",
-		StartDate: time.Now().Add(-time.Hour),
-		EndDate:   time.Now(),
-	}
-	err = database.InsertMissionProgress(progress)
-	require.NoError(t, err)
-
-	overview, err := database.GetMissionsOverview()
-	require.NoError(t, err)
-	require.NotNil(t, overview)
-	// Se puede profundizar en la validación del contenido del overview según la lógica agregada.
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		// Inserta una misión.
+		mission := models.Mission{
+			ID:          primitive.NewObjectID(),
+			Title:       "Overview Mission",
+			Description: "Test overview",
+			CreatedAt:   time.Now(),
+		}
+		err := store.InsertMission(mission)
+		require.NoError(t, err)
+
+		// Inserta un progreso completado para la misión.
+		userID := primitive.NewObjectID()
+		progress := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: mission.ID,
+			Status:    "completada",
+			StartDate: time.Now().Add(-time.Hour),
+			EndDate:   time.Now(),
+		}
+		err = store.InsertMissionProgress(progress)
+		require.NoError(t, err)
+
+		overview, err := store.GetMissionsOverview()
+		require.NoError(t, err)
+		require.NotNil(t, overview)
+		// Se puede profundizar en la validación del contenido del overview según la lógica agregada.
+	})
 }
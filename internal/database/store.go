@@ -0,0 +1,413 @@
+// /internal/database/store.go
+package database
+
+import (
+	"errors"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNotFound se devuelve cuando ningún documento coincide con la consulta o
+// la actualización solicitada.
+var ErrNotFound = errors.New("documento no encontrado")
+
+// ErrDuplicate se devuelve al insertar un documento cuyo ID o clave única ya existe.
+var ErrDuplicate = errors.New("documento duplicado")
+
+// ListQuery selecciona una página de una lista que se recorre en un solo
+// orden, el que indica cada método, y a igual valor por ID. Con After se
+// devuelven los elementos que siguen a ese, del que solo importan el campo de
+// orden y el ID. Limit en 0 no limita.
+type ListQuery[T any] struct {
+	After *T
+	Limit int
+}
+
+// UserUpdate describe los cambios a aplicar sobre las preferencias de un
+// usuario; los campos nil se dejan como están.
+type UserUpdate struct {
+	Timezone            *string
+	BirthDate           *time.Time
+	DisplayName         *string
+	Avatar              *string
+	HideFromLeaderboard *bool
+	Locale              *string
+}
+
+// UserStore agrupa las operaciones sobre la colección de usuarios.
+type UserStore interface {
+	InsertUser(user models.User) error
+	FindUserByEmail(email string) (*models.User, error)
+	FindUserByID(id primitive.ObjectID) (*models.User, error)
+	// UpdateUser aplica los cambios y devuelve el usuario actualizado.
+	UpdateUser(id primitive.ObjectID, update UserUpdate) (*models.User, error)
+	// AddUserXP suma xp a la experiencia del usuario en una sola operación atómica.
+	AddUserXP(id primitive.ObjectID, xp int) error
+	// UpdateUserStreak reemplaza la racha diaria del usuario.
+	UpdateUserStreak(id primitive.ObjectID, streak models.Streak) error
+}
+
+// SessionStore agrupa las operaciones sobre las sesiones de los usuarios.
+type SessionStore interface {
+	InsertSession(session models.Session) error
+	FindSessionByRefreshHash(hash string) (*models.Session, error)
+	RotateSession(id primitive.ObjectID, oldHash, newHash string, usedAt, expiresAt time.Time) error
+	RevokeSession(id primitive.ObjectID, at time.Time) error
+	RevokeUserSessions(userID primitive.ObjectID, at time.Time) error
+	IsSessionActive(id primitive.ObjectID, now time.Time) (bool, error)
+}
+
+// MissionUpdate describe los cambios a aplicar sobre una misión; los campos
+// nil se dejan como están.
+type MissionUpdate struct {
+	Title       *string
+	Description *string
+	Archived    *bool
+	// Steps reemplaza la lista completa de pasos de la misión.
+	Steps *[]models.MissionStep
+	// Quiz reemplaza el cuestionario; RemoveQuiz lo elimina.
+	Quiz       *models.Quiz
+	RemoveQuiz bool
+	// Prerequisites reemplaza la lista completa de prerrequisitos.
+	Prerequisites *[]primitive.ObjectID
+	// La clasificación en cero, o sin etiquetas, se elimina.
+	Category         *string
+	Tags             *[]string
+	Difficulty       *int
+	GradeLevel       *int
+	EstimatedMinutes *int
+	// XPReward y DifficultyMultiplier en 0 vuelven a los valores por defecto.
+	XPReward             *int
+	DifficultyMultiplier *float64
+	// Translations agrega o reemplaza la traducción de cada idioma indicado;
+	// una traducción nil la elimina. Las de otros idiomas se dejan como están.
+	Translations map[string]*models.MissionTranslation
+}
+
+// Campos por los que se puede ordenar el catálogo de misiones.
+const (
+	SortByCreatedAt  = "createdAt"
+	SortByTitle      = "title"
+	SortByDifficulty = "difficulty"
+	SortByGradeLevel = "gradeLevel"
+	SortByDuration   = "estimatedMinutes"
+)
+
+// MissionQuery filtra y ordena el catálogo de misiones no archivadas. Los
+// campos en cero no filtran; Tags exige todas las etiquetas e IDs, si no es
+// nil, limita el resultado a esas misiones. Sort es uno de
+// los campos SortBy, por defecto SortByCreatedAt; a igual valor se ordena por
+// fecha de creación y luego por ID. Con After se devuelven las misiones que
+// siguen a esa, de la que solo importan el campo de orden, CreatedAt y el ID.
+// Limit en 0 no limita.
+type MissionQuery struct {
+	Category       string
+	Tags           []string
+	Difficulty     int
+	GradeLevel     int
+	MaxMinutes     int
+	IDs            []primitive.ObjectID
+	Sort           string
+	SortDescending bool
+	After          *models.Mission
+	Limit          int
+}
+
+// MissionList selecciona una página de las misiones de la organización y las
+// globales, por fecha de creación y luego por ID, sin el total ni las facetas
+// de FindMissions. IncludeArchived incluye las archivadas; OwnOnly omite las
+// globales de otras organizaciones; IDs, si no es nil, limita el resultado a
+// esas misiones. After y Limit funcionan como en ListQuery.
+type MissionList struct {
+	IncludeArchived bool
+	OwnOnly         bool
+	IDs             []primitive.ObjectID
+	After           *models.Mission
+	Limit           int
+}
+
+// MissionPage es una página del catálogo. Total y Facets cuentan todas las
+// misiones que cumplen la consulta, no solo las de la página.
+type MissionPage struct {
+	Missions []models.Mission
+	Total    int
+	Facets   models.MissionFacets
+}
+
+// MissionSearch busca texto en el catálogo de misiones no archivadas. Con
+// After se devuelven los resultados que siguen a ese, por relevancia y luego
+// por ID. Limit en 0 no limita.
+type MissionSearch struct {
+	Text  string
+	After *MissionMatch
+	Limit int
+}
+
+// MissionMatch es una misión encontrada por SearchMissions con su relevancia.
+type MissionMatch struct {
+	Mission models.Mission
+	Score   float64
+}
+
+// searchWeights es cuánto pesa una coincidencia en cada campo de la búsqueda.
+var searchWeights = map[string]int{"title": 10, "tags": 5, "description": 1}
+
+// MissionStore agrupa las operaciones sobre el catálogo de misiones.
+type MissionStore interface {
+	InsertMission(mission models.Mission) error
+	// GetAllMissions omite las misiones archivadas salvo que includeArchived sea true.
+	GetAllMissions(includeArchived bool) ([]models.Mission, error)
+	// ListMissions devuelve una página de las misiones que cumplen list.
+	ListMissions(list MissionList) ([]models.Mission, error)
+	// FindMissions devuelve una página de las misiones que cumplen la consulta.
+	FindMissions(query MissionQuery) (MissionPage, error)
+	// SearchMissions devuelve las misiones cuyo título, descripción o
+	// etiquetas contienen alguna palabra del texto, de la más a la menos
+	// relevante. No distingue mayúsculas ni acentos y reconoce los plurales.
+	SearchMissions(search MissionSearch) ([]MissionMatch, error)
+	GetMissionByID(id primitive.ObjectID) (*models.Mission, error)
+	// UpdateMission aplica los cambios y devuelve la misión actualizada.
+	UpdateMission(id primitive.ObjectID, update MissionUpdate, at time.Time) (*models.Mission, error)
+	DeleteMission(id primitive.ObjectID) error
+}
+
+// Campos por los que se puede ordenar el progreso de misiones.
+const (
+	SortByStartDate = "startDate"
+	SortByEndDate   = "endDate"
+)
+
+// ProgressQuery selecciona una página del progreso de un usuario. Statuses
+// vacío no filtra por estado. Sort es SortByStartDate (por defecto) o
+// SortByEndDate; el progreso sin fecha de fin va primero en orden ascendente.
+// Con After se devuelve el progreso que sigue a ese, del que solo importan la
+// fecha de orden y el ID. Limit en 0 no limita. Con WithMission cada progreso
+// trae su misión; sin él no se consulta.
+type ProgressQuery struct {
+	UserID         primitive.ObjectID
+	Statuses       []models.ProgressStatus
+	Sort           string
+	SortDescending bool
+	After          *models.MissionProgress
+	Limit          int
+	WithMission    bool
+}
+
+// ProgressWithMission es un progreso junto con su misión, incluidas las
+// archivadas. Mission es nil si no se pidió o si la misión ya no existe.
+type ProgressWithMission struct {
+	Progress models.MissionProgress
+	Mission  *models.Mission
+}
+
+// ProgressSummary resume el progreso de un usuario sin cargar sus
+// documentos: cuántas misiones inició (en cualquier estado), cuántas tiene en
+// curso y cuántas completó. FastestCompletion, el menor tiempo activo de las
+// completadas, y LastCompletedAt solo valen si Completed es mayor que 0.
+type ProgressSummary struct {
+	Started           int
+	Active            int
+	Completed         int
+	FastestCompletion time.Duration
+	LastCompletedAt   time.Time
+}
+
+// ProgressStore agrupa las operaciones sobre el progreso de misiones y las
+// estadísticas que se calculan a partir de él.
+type ProgressStore interface {
+	// InsertMissionProgress devuelve ErrDuplicate si el usuario ya tiene progreso en la misión.
+	InsertMissionProgress(progress models.MissionProgress) error
+	FindMissionProgress(userID, missionID primitive.ObjectID) (*models.MissionProgress, error)
+	// UpdateMissionProgress guarda el progreso tras una transición de estado.
+	// Solo tiene efecto si el documento sigue en el estado from; si otro cambio
+	// se adelantó devuelve ErrNotFound.
+	UpdateMissionProgress(progress models.MissionProgress, from models.ProgressStatus) error
+	// CompleteMissionStep registra un paso completado y devuelve el progreso
+	// actualizado. Devuelve ErrNotFound si la misión no está iniciada o el paso
+	// ya estaba completado.
+	CompleteMissionStep(id primitive.ObjectID, step models.StepProgress) (*models.MissionProgress, error)
+	// CreditPendingXP suma al usuario la XP de sus misiones completadas que
+	// sigue pendiente (XPPending) y devuelve cuánta sumó. Es idempotente: si
+	// una llamada anterior se interrumpió, repetirla no acredita dos veces la
+	// misma misión.
+	CreditPendingXP(userID primitive.ObjectID) (int, error)
+	// GetProgressForMissions devuelve el progreso del usuario en las misiones
+	// indicadas, a lo sumo uno por misión.
+	GetProgressForMissions(userID primitive.ObjectID, missionIDs []primitive.ObjectID) ([]models.MissionProgress, error)
+	GetProgressSummary(userID primitive.ObjectID) (ProgressSummary, error)
+	// CountCompletionsByDay cuenta las misiones completadas desde since por
+	// día, con el formato models.DayLayout en la zona horaria loc.
+	CountCompletionsByDay(userID primitive.ObjectID, since time.Time, loc *time.Location) (map[string]int, error)
+	// ListMissionProgress devuelve una página del progreso del usuario; a
+	// igual valor del campo de orden, se ordena por ID.
+	ListMissionProgress(query ProgressQuery) ([]ProgressWithMission, error)
+	// HasMissionProgress consulta todas las organizaciones, porque una misión
+	// global puede tener progreso en cualquiera de ellas.
+	HasMissionProgress(missionID primitive.ObjectID) (bool, error)
+	GetUserStatistics(userID primitive.ObjectID) (bson.M, error)
+	GetMissionsOverview() (bson.M, error)
+}
+
+// QuizAttemptSummary resume los intentos de un usuario en el cuestionario de
+// una misión sin cargarlos: cuántos hizo y si alguno aprobó.
+type QuizAttemptSummary struct {
+	Attempts int
+	Passed   bool
+}
+
+// QuizStore agrupa las operaciones sobre los intentos de los cuestionarios.
+type QuizStore interface {
+	InsertQuizAttempt(attempt models.QuizAttempt) error
+	// GetQuizAttempts devuelve una página de los intentos del usuario en la
+	// misión, del más antiguo al más reciente.
+	GetQuizAttempts(userID, missionID primitive.ObjectID, query ListQuery[models.QuizAttempt]) ([]models.QuizAttempt, error)
+	GetQuizAttemptSummary(userID, missionID primitive.ObjectID) (QuizAttemptSummary, error)
+}
+
+// LeaderboardQuery selecciona una página del ranking Bucket (ver
+// models.LeaderboardBucket). Con After se devuelven las entradas que siguen a
+// esa; con Before, las que la preceden, también en el orden del ranking.
+// Las páginas se recorren con After, sin saltar entradas, para que leer una
+// cueste lo mismo que su tamaño. Limit en 0 no limita.
+type LeaderboardQuery struct {
+	Bucket string
+	Limit  int
+	After  *models.LeaderboardEntry
+	Before *models.LeaderboardEntry
+}
+
+// LeaderboardStore mantiene los rankings materializados: una entrada por
+// usuario y periodo que se actualiza con cada misión completada, de modo que
+// leer una página cuesta lo mismo que su tamaño. Los usuarios que ocultaron
+// su perfil nunca aparecen.
+type LeaderboardStore interface {
+	// RecordLeaderboardCompletion suma una misión completada en at, con xp
+	// puntos, a los rankings semanal, mensual e histórico que la contienen.
+	RecordLeaderboardCompletion(userID primitive.ObjectID, xp int, at time.Time) error
+	// GetLeaderboard devuelve una página del ranking en el orden de
+	// models.LeaderboardEntry.Precedes.
+	GetLeaderboard(query LeaderboardQuery) ([]models.LeaderboardEntry, error)
+	// GetLeaderboardPosition devuelve la entrada del usuario en el ranking y
+	// cuántos usuarios lo preceden. Devuelve ErrNotFound si no completó
+	// misiones en el periodo o si ocultó su perfil.
+	GetLeaderboardPosition(userID primitive.ObjectID, bucket string) (*models.LeaderboardEntry, int, error)
+	// RebuildLeaderboard recalcula los rankings de la organización a partir de
+	// las misiones completadas. Sirve para recuperarse si alguna actualización
+	// se perdió.
+	RebuildLeaderboard() error
+}
+
+// AchievementUpdate describe los cambios a aplicar sobre la definición de un
+// logro; los campos nil se dejan como están.
+type AchievementUpdate struct {
+	Name        *string
+	Description *string
+	Rule        *models.AchievementRule
+	Active      *bool
+}
+
+// AchievementStore agrupa las operaciones sobre las definiciones de logros y
+// los logros otorgados a los usuarios.
+type AchievementStore interface {
+	// InsertAchievement devuelve ErrDuplicate si ya existe un logro con el mismo código.
+	InsertAchievement(achievement models.Achievement) error
+	// GetAchievements devuelve una página de las definiciones por fecha de
+	// creación; omite los logros inactivos salvo que includeInactive sea true.
+	GetAchievements(includeInactive bool, query ListQuery[models.Achievement]) ([]models.Achievement, error)
+	UpdateAchievement(id primitive.ObjectID, update AchievementUpdate, at time.Time) (*models.Achievement, error)
+	DeleteAchievement(id primitive.ObjectID) error
+	// InsertUserAchievement devuelve ErrDuplicate si el usuario ya tiene el logro.
+	InsertUserAchievement(award models.UserAchievement) error
+	// GetUserAchievements devuelve una página de los logros del usuario en el
+	// orden en que los obtuvo.
+	GetUserAchievements(userID primitive.ObjectID, query ListQuery[models.UserAchievement]) ([]models.UserAchievement, error)
+}
+
+// ClassroomStore agrupa las operaciones sobre las clases y sus inscripciones.
+type ClassroomStore interface {
+	// InsertClassroom devuelve ErrDuplicate si el código de la clase ya existe.
+	InsertClassroom(classroom models.Classroom) error
+	GetClassroomByID(id primitive.ObjectID) (*models.Classroom, error)
+	// FindClassroomByJoinCode devuelve ErrNotFound si ninguna clase tiene el código.
+	FindClassroomByJoinCode(code string) (*models.Classroom, error)
+	// GetTeacherClassrooms devuelve una página de las clases del docente por
+	// fecha de creación.
+	GetTeacherClassrooms(teacherID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error)
+	// GetStudentClassrooms devuelve una página de las clases en que está
+	// inscrito el estudiante, por fecha de creación.
+	GetStudentClassrooms(studentID primitive.ObjectID, query ListQuery[models.Classroom]) ([]models.Classroom, error)
+	// AddClassroomStudent inscribe al estudiante; si ya lo estaba no hace nada.
+	AddClassroomStudent(classroomID, studentID primitive.ObjectID) error
+	// RemoveClassroomStudent da de baja al estudiante; si no estaba inscrito no hace nada.
+	RemoveClassroomStudent(classroomID, studentID primitive.ObjectID) error
+}
+
+// AssignmentStore agrupa las operaciones sobre las misiones asignadas por los
+// docentes. Las listas se ordenan por fecha de entrega.
+type AssignmentStore interface {
+	InsertAssignment(assignment models.Assignment) error
+	GetAssignmentByID(id primitive.ObjectID) (*models.Assignment, error)
+	GetTeacherAssignments(teacherID primitive.ObjectID, query ListQuery[models.Assignment]) ([]models.Assignment, error)
+	// GetStudentAssignments devuelve una página de las asignaciones dirigidas
+	// al estudiante o a alguna de las clases classroomIDs que ya están
+	// abiertas en openAt; openAt en cero no filtra por apertura.
+	GetStudentAssignments(studentID primitive.ObjectID, classroomIDs []primitive.ObjectID, openAt time.Time, query ListQuery[models.Assignment]) ([]models.Assignment, error)
+}
+
+// PathStore agrupa las operaciones sobre las rutas de aprendizaje.
+type PathStore interface {
+	InsertPath(path models.LearningPath) error
+	// GetPaths devuelve una página de las rutas de la organización por fecha
+	// de creación.
+	GetPaths(query ListQuery[models.LearningPath]) ([]models.LearningPath, error)
+	GetPathByID(id primitive.ObjectID) (*models.LearningPath, error)
+	// ReplacePath reemplaza el título, la descripción y los capítulos de la
+	// ruta con el ID de path.
+	ReplacePath(path models.LearningPath) error
+	DeletePath(id primitive.ObjectID) error
+}
+
+// TenantStore agrupa las operaciones sobre el registro de organizaciones, que
+// es común a todas ellas.
+type TenantStore interface {
+	// InsertTenant devuelve ErrDuplicate si ya existe una organización con el mismo ID.
+	InsertTenant(tenant models.Tenant) error
+	// GetTenant devuelve ErrNotFound si la organización no está registrada.
+	// DefaultTenantID existe siempre, aunque no tenga documento.
+	GetTenant(id string) (*models.Tenant, error)
+	// GetTenants devuelve una página de las organizaciones registradas por ID.
+	GetTenants(query ListQuery[models.Tenant]) ([]models.Tenant, error)
+}
+
+// Store reúne todos los repositorios de la aplicación. Lo implementan
+// MongoStore y MemoryStore. Las operaciones que registran fechas las reciben
+// como parámetro para que el reloj lo controle quien las invoca.
+//
+// Cada Store trabaja sobre una sola organización: guarda los documentos con
+// su ID y solo lee los de ella, además de las misiones globales. Los
+// constructores devuelven el de DefaultTenantID y ForTenant, el de otra.
+type Store interface {
+	UserStore
+	SessionStore
+	MissionStore
+	ProgressStore
+	QuizStore
+	AchievementStore
+	LeaderboardStore
+	ClassroomStore
+	AssignmentStore
+	PathStore
+	TenantStore
+	// ForTenant devuelve un Store sobre los mismos datos limitado a la
+	// organización tenantID.
+	ForTenant(tenantID string) Store
+}
+
+var (
+	_ Store = (*MongoStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
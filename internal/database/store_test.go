@@ -0,0 +1,1243 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestErrNotFoundSemantics(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		_, err := store.FindUserByID(primitive.NewObjectID())
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		_, err = store.GetMissionByID(primitive.NewObjectID())
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		progress := models.NewMissionProgress(primitive.NewObjectID(), primitive.NewObjectID(), time.Now())
+		err = store.UpdateMissionProgress(progress, models.ProgressStarted)
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestDuplicateMissionProgress(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		progress := models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    primitive.NewObjectID(),
+			MissionID: primitive.NewObjectID(),
+			Status:    "iniciada",
+			StartDate: time.Now(),
+		}
+		require.NoError(t, store.InsertMissionProgress(progress))
+
+		progress.ID = primitive.NewObjectID()
+		require.ErrorIs(t, store.InsertMissionProgress(progress), database.ErrDuplicate)
+
+		found, err := store.FindMissionProgress(progress.UserID, progress.MissionID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProgressStarted, found.Status)
+	})
+}
+
+func TestLeaderboardOrderAndCounts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		leader := models.User{ID: primitive.NewObjectID(), Username: "leader", Email: "leader@example.com"}
+		idle := models.User{ID: primitive.NewObjectID(), Username: "idle", Email: "idle@example.com"}
+		require.NoError(t, store.InsertUser(idle))
+		require.NoError(t, store.InsertUser(leader))
+
+		for _, status := range []models.ProgressStatus{models.ProgressCompleted, models.ProgressCompleted, models.ProgressStarted} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    leader.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    status,
+				StartDate: time.Now(),
+				EndDate:   time.Now(),
+			}))
+		}
+
+		require.NoError(t, store.RebuildLeaderboard())
+
+		// Solo aparecen quienes completaron misiones
+		leaderboard, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 1)
+		require.Equal(t, leader.ID, leaderboard[0].UserID)
+		require.Equal(t, 2, leaderboard[0].CompletedCount)
+
+		_, _, err = store.GetLeaderboardPosition(idle.ID, models.PeriodAllTime)
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestLeaderboardSortedByXP(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		veteran := models.User{ID: primitive.NewObjectID(), Username: "veteran", Email: "veteran@example.com"}
+		expert := models.User{ID: primitive.NewObjectID(), Username: "expert", Email: "expert@example.com"}
+		require.NoError(t, store.InsertUser(veteran))
+		require.NoError(t, store.InsertUser(expert))
+
+		complete := func(user models.User, xp int) {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: time.Now(),
+				EndDate:   time.Now(),
+				XPAwarded: xp,
+			}))
+			require.NoError(t, store.AddUserXP(user.ID, xp))
+			require.NoError(t, store.RecordLeaderboardCompletion(user.ID, xp, time.Now()))
+		}
+		// veteran completa más misiones, pero expert acumula más XP
+		complete(veteran, 100)
+		complete(veteran, 100)
+		complete(expert, 300)
+		require.ErrorIs(t, store.AddUserXP(primitive.NewObjectID(), 10), database.ErrNotFound)
+		require.ErrorIs(t, store.RecordLeaderboardCompletion(primitive.NewObjectID(), 10, time.Now()), database.ErrNotFound)
+
+		found, err := store.FindUserByID(expert.ID)
+		require.NoError(t, err)
+		require.Equal(t, 300, found.XP)
+
+		leaderboard, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 2)
+		require.Equal(t, expert.ID, leaderboard[0].UserID)
+		require.Equal(t, 300, leaderboard[0].XP)
+		require.Equal(t, 300, leaderboard[0].User.XP)
+		require.Equal(t, 1, leaderboard[0].CompletedCount)
+		require.Equal(t, veteran.ID, leaderboard[1].UserID)
+		require.Equal(t, 2, leaderboard[1].CompletedCount)
+	})
+}
+
+func TestLeaderboardBucketsAndKeyset(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		users := make([]models.User, 5)
+		for i := range users {
+			users[i] = models.User{ID: primitive.NewObjectID(), Username: string(rune('a' + i))}
+			require.NoError(t, store.InsertUser(users[i]))
+		}
+		complete := func(user models.User, xp int, at time.Time) {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: at.Add(-time.Minute),
+				EndDate:   at,
+				XPAwarded: xp,
+			}))
+		}
+		// a, b y c empatan en la semana; c llegó primero y luego b
+		complete(users[0], 100, base.Add(3*time.Hour))
+		complete(users[1], 100, base.Add(2*time.Hour))
+		complete(users[2], 100, base.Add(time.Hour))
+		complete(users[3], 50, base)
+		// e suma más, pero la semana anterior
+		complete(users[4], 500, base.AddDate(0, 0, -7))
+
+		require.NoError(t, store.RebuildLeaderboard())
+		week, _ := models.LeaderboardBucket(models.PeriodWeekly, base)
+		usernames := map[primitive.ObjectID]string{}
+		for _, u := range users {
+			usernames[u.ID] = u.Username
+		}
+		names := func(entries []models.LeaderboardEntry) []string {
+			var out []string
+			for _, e := range entries {
+				out = append(out, usernames[e.UserID])
+			}
+			return out
+		}
+
+		first, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: week, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []string{"c", "b"}, names(first))
+		require.True(t, first[0].ReachedAt.Equal(base.Add(time.Hour)))
+
+		second, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: week, Limit: 2, After: &first[1]})
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "d"}, names(second))
+
+		before, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: week, Limit: 2, Before: &second[1]})
+		require.NoError(t, err)
+		require.Equal(t, []string{"b", "a"}, names(before))
+
+		entry, ahead, err := store.GetLeaderboardPosition(users[0].ID, week)
+		require.NoError(t, err)
+		require.Equal(t, 2, ahead)
+		require.Equal(t, 100, entry.XP)
+
+		all, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Equal(t, []string{"e", "c", "b", "a", "d"}, names(all))
+	})
+}
+
+func TestUserStatisticsValues(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		var missionIDs []primitive.ObjectID
+		for i := 0; i < 4; i++ {
+			mission := models.Mission{ID: primitive.NewObjectID(), Title: "Misión"}
+			require.NoError(t, store.InsertMission(mission))
+			missionIDs = append(missionIDs, mission.ID)
+		}
+
+		start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		for i, minutes := range []int{10, 30} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    userID,
+				MissionID: missionIDs[i],
+				Status:    "completada",
+				StartDate: start,
+				EndDate:   start.Add(time.Duration(minutes) * time.Minute),
+			}))
+		}
+
+		stats, err := store.GetUserStatistics(userID)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, stats["totalCompleted"])
+		require.InDelta(t, float64(20*time.Minute/time.Millisecond), stats["averageDuration"], 0.5)
+		require.InDelta(t, 50.0, stats["progressPercentage"], 0.001)
+	})
+}
+
+func TestUpdateMissionProgressRequiresExpectedStatus(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		start := time.Now().Truncate(time.Millisecond)
+		progress := models.NewMissionProgress(primitive.NewObjectID(), primitive.NewObjectID(), start)
+		require.NoError(t, store.InsertMissionProgress(progress))
+
+		require.NoError(t, progress.Apply(models.ActionPause, start.Add(time.Minute)))
+		require.NoError(t, store.UpdateMissionProgress(progress, models.ProgressStarted))
+
+		// Una segunda pausa basada en el estado anterior ya no coincide
+		require.ErrorIs(t, store.UpdateMissionProgress(progress, models.ProgressStarted), database.ErrNotFound)
+
+		active, err := store.GetProgressForMissions(progress.UserID, []primitive.ObjectID{progress.MissionID})
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		require.Equal(t, models.ProgressPaused, active[0].Status)
+		require.EqualValues(t, time.Minute.Milliseconds(), active[0].ActiveDurationMs)
+	})
+}
+
+func TestCreditPendingXPIsIdempotent(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user := models.User{ID: primitive.NewObjectID(), Username: "alumno", Email: "alumno@example.com"}
+		require.NoError(t, store.InsertUser(user))
+		for _, xp := range []int{100, 150} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: time.Now(),
+				EndDate:   time.Now(),
+				XPAwarded: xp,
+				XPPending: true,
+			}))
+		}
+
+		credited, err := store.CreditPendingXP(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 250, credited)
+
+		// Sin XP pendiente, repetir no vuelve a sumar
+		credited, err = store.CreditPendingXP(user.ID)
+		require.NoError(t, err)
+		require.Zero(t, credited)
+
+		found, err := store.FindUserByID(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, 250, found.XP)
+	})
+}
+
+func TestCompleteMissionStep(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		mission := models.Mission{ID: primitive.NewObjectID(), Title: "Misión"}
+		require.NoError(t, store.InsertMission(mission))
+		steps := []models.MissionStep{
+			{ID: primitive.NewObjectID(), Title: "Paso 1"},
+			{ID: primitive.NewObjectID(), Title: "Paso 2", Optional: true},
+		}
+		updated, err := store.UpdateMission(mission.ID, database.MissionUpdate{Steps: &steps}, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, steps, updated.Steps)
+
+		now := time.Now().Truncate(time.Millisecond)
+		progress := models.NewMissionProgress(primitive.NewObjectID(), mission.ID, now)
+		require.NoError(t, store.InsertMissionProgress(progress))
+
+		step := models.StepProgress{StepID: steps[0].ID, CompletedAt: now}
+		result, err := store.CompleteMissionStep(progress.ID, step)
+		require.NoError(t, err)
+		require.Len(t, result.Steps, 1)
+		require.True(t, result.HasCompletedStep(steps[0].ID))
+
+		// El mismo paso no se registra dos veces
+		_, err = store.CompleteMissionStep(progress.ID, step)
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// Una misión pausada no admite pasos
+		paused := *result
+		require.NoError(t, paused.Apply(models.ActionPause, now))
+		require.NoError(t, store.UpdateMissionProgress(paused, models.ProgressStarted))
+		_, err = store.CompleteMissionStep(progress.ID, models.StepProgress{StepID: steps[1].ID, CompletedAt: now})
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		found, err := store.FindMissionProgress(progress.UserID, mission.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Steps, 1)
+	})
+}
+
+func TestQuizAttemptsAndMissionQuiz(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		correct := 1
+		mission := models.Mission{ID: primitive.NewObjectID(), Title: "Cuestionario"}
+		require.NoError(t, store.InsertMission(mission))
+
+		quiz := &models.Quiz{PassingScore: 50, Questions: []models.QuizQuestion{{
+			ID:            primitive.NewObjectID(),
+			Type:          models.QuestionMultipleChoice,
+			Prompt:        "¿Cuál es el planeta rojo?",
+			Options:       []string{"Venus", "Marte"},
+			CorrectOption: &correct,
+		}}}
+		updated, err := store.UpdateMission(mission.ID, database.MissionUpdate{Quiz: quiz}, time.Now())
+		require.NoError(t, err)
+		require.NotNil(t, updated.Quiz)
+		require.Equal(t, 1, *updated.Quiz.Questions[0].CorrectOption)
+
+		userID := primitive.NewObjectID()
+		now := time.Now().Truncate(time.Millisecond)
+		for i, score := range []float64{0, 100} {
+			require.NoError(t, store.InsertQuizAttempt(models.QuizAttempt{
+				ID:          primitive.NewObjectID(),
+				UserID:      userID,
+				MissionID:   mission.ID,
+				Number:      i + 1,
+				Score:       score,
+				Passed:      score >= 50,
+				SubmittedAt: now.Add(time.Duration(1-i) * time.Minute),
+			}))
+		}
+
+		// Dos envíos simultáneos no pueden ocupar el mismo número de intento
+		err = store.InsertQuizAttempt(models.QuizAttempt{
+			ID:          primitive.NewObjectID(),
+			UserID:      userID,
+			MissionID:   mission.ID,
+			Number:      2,
+			SubmittedAt: now,
+		})
+		require.ErrorIs(t, err, database.ErrDuplicate)
+
+		attempts, err := store.GetQuizAttempts(userID, mission.ID, database.ListQuery[models.QuizAttempt]{})
+		require.NoError(t, err)
+		require.Len(t, attempts, 2)
+		require.True(t, attempts[0].Passed)
+		require.False(t, attempts[1].Passed)
+		rest, err := store.GetQuizAttempts(userID, mission.ID, database.ListQuery[models.QuizAttempt]{After: &attempts[0], Limit: 5})
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		require.Equal(t, attempts[1].ID, rest[0].ID)
+
+		summary, err := store.GetQuizAttemptSummary(userID, mission.ID)
+		require.NoError(t, err)
+		require.Equal(t, database.QuizAttemptSummary{Attempts: 2, Passed: true}, summary)
+		summary, err = store.GetQuizAttemptSummary(primitive.NewObjectID(), mission.ID)
+		require.NoError(t, err)
+		require.Zero(t, summary)
+
+		updated, err = store.UpdateMission(mission.ID, database.MissionUpdate{RemoveQuiz: true}, time.Now())
+		require.NoError(t, err)
+		require.Nil(t, updated.Quiz)
+	})
+}
+
+func TestUserStatisticsUseActiveDuration(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		mission := models.Mission{ID: primitive.NewObjectID(), Title: "Misión"}
+		require.NoError(t, store.InsertMission(mission))
+
+		// Una hora entre inicio y fin, pero solo diez minutos activos
+		start := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+			ID:               primitive.NewObjectID(),
+			UserID:           userID,
+			MissionID:        mission.ID,
+			Status:           models.ProgressCompleted,
+			StartDate:        start,
+			EndDate:          start.Add(time.Hour),
+			ActiveDurationMs: (10 * time.Minute).Milliseconds(),
+		}))
+
+		stats, err := store.GetUserStatistics(userID)
+		require.NoError(t, err)
+		require.InDelta(t, float64((10 * time.Minute).Milliseconds()), stats["averageDuration"], 0.5)
+
+		overview, err := store.GetMissionsOverview()
+		require.NoError(t, err)
+		times, ok := overview["avgCompletionTimes"].([]bson.M)
+		require.True(t, ok)
+		require.Len(t, times, 1)
+		require.InDelta(t, float64((10 * time.Minute).Milliseconds()), times[0]["averageDuration"], 0.5)
+	})
+}
+
+func TestArchivedMissionsExcludedFromCatalogAndProgress(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		kept := models.Mission{ID: primitive.NewObjectID(), Title: "Vigente"}
+		retired := models.Mission{ID: primitive.NewObjectID(), Title: "Retirada"}
+		require.NoError(t, store.InsertMission(kept))
+		require.NoError(t, store.InsertMission(retired))
+		require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+			ID:        primitive.NewObjectID(),
+			UserID:    userID,
+			MissionID: retired.ID,
+			Status:    "completada",
+			StartDate: time.Now().Add(-time.Minute),
+			EndDate:   time.Now(),
+		}))
+
+		archived := true
+		updated, err := store.UpdateMission(retired.ID, database.MissionUpdate{Archived: &archived}, time.Now())
+		require.NoError(t, err)
+		require.True(t, updated.Archived)
+		require.NotNil(t, updated.ArchivedAt)
+
+		missions, err := store.GetAllMissions(false)
+		require.NoError(t, err)
+		require.Len(t, missions, 1)
+		require.Equal(t, kept.ID, missions[0].ID)
+
+		all, err := store.GetAllMissions(true)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		listed, err := store.ListMissions(database.MissionList{})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		listed, err = store.ListMissions(database.MissionList{IncludeArchived: true, IDs: []primitive.ObjectID{retired.ID}})
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		require.Equal(t, retired.ID, listed[0].ID)
+
+		stats, err := store.GetUserStatistics(userID)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, stats["totalCompleted"])
+		require.InDelta(t, 0.0, stats["progressPercentage"], 0.001)
+
+		inUse, err := store.HasMissionProgress(retired.ID)
+		require.NoError(t, err)
+		require.True(t, inUse)
+
+		require.NoError(t, store.DeleteMission(kept.ID))
+		require.ErrorIs(t, store.DeleteMission(kept.ID), database.ErrNotFound)
+	})
+}
+
+func TestMissionsOverviewMostPopular(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		popular := models.Mission{ID: primitive.NewObjectID(), Title: "Popular", CreatedAt: time.Now()}
+		other := models.Mission{ID: primitive.NewObjectID(), Title: "Otra", CreatedAt: time.Now()}
+		require.NoError(t, store.InsertMission(popular))
+		require.NoError(t, store.InsertMission(other))
+
+		start := time.Now().Add(-time.Hour)
+		for _, missionID := range []primitive.ObjectID{popular.ID, popular.ID, other.ID} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    primitive.NewObjectID(),
+				MissionID: missionID,
+				Status:    "completada",
+				StartDate: start,
+				EndDate:   start.Add(time.Minute),
+			}))
+		}
+
+		overview, err := store.GetMissionsOverview()
+		require.NoError(t, err)
+
+		mostPopular, ok := overview["mostPopularMission"].(bson.M)
+		require.True(t, ok)
+		require.Equal(t, popular.ID, mostPopular["missionId"])
+		require.EqualValues(t, 2, mostPopular["count"])
+		require.Equal(t, bson.M{"_id": popular.ID, "title": popular.Title}, mostPopular["mission"])
+	})
+}
+
+func TestSessionRotationAndRevocation(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		now := time.Now()
+		session := models.Session{
+			ID:               primitive.NewObjectID(),
+			UserID:           primitive.NewObjectID(),
+			RefreshTokenHash: "hash-1",
+			CreatedAt:        now,
+			LastUsedAt:       now,
+			ExpiresAt:        now.Add(time.Hour),
+		}
+		require.NoError(t, store.InsertSession(session))
+
+		active, err := store.IsSessionActive(session.ID, now)
+		require.NoError(t, err)
+		require.True(t, active)
+
+		require.NoError(t, store.RotateSession(session.ID, "hash-1", "hash-2", now, now.Add(2*time.Hour)))
+		// El hash anterior ya no permite rotar
+		require.ErrorIs(t, store.RotateSession(session.ID, "hash-1", "hash-3", now, now.Add(2*time.Hour)), database.ErrNotFound)
+
+		found, err := store.FindSessionByRefreshHash("hash-1")
+		require.NoError(t, err)
+		require.Equal(t, "hash-2", found.RefreshTokenHash)
+
+		require.NoError(t, store.RevokeUserSessions(session.UserID, now))
+		active, err = store.IsSessionActive(session.ID, now)
+		require.NoError(t, err)
+		require.False(t, active)
+	})
+}
+
+func TestAchievementDefinitionsAndAwards(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		now := time.Now().Truncate(time.Millisecond)
+		rule := models.Achievement{
+			ID:        primitive.NewObjectID(),
+			Code:      "cinco-misiones",
+			Name:      "Explorador constante",
+			Rule:      models.AchievementRule{Type: models.RuleMissionsCompleted, Threshold: 5},
+			Active:    true,
+			CreatedAt: now,
+		}
+		require.NoError(t, store.InsertAchievement(rule))
+
+		duplicate := rule
+		duplicate.ID = primitive.NewObjectID()
+		require.ErrorIs(t, store.InsertAchievement(duplicate), database.ErrDuplicate)
+
+		inactive := false
+		updated, err := store.UpdateAchievement(rule.ID, database.AchievementUpdate{Active: &inactive}, now)
+		require.NoError(t, err)
+		require.False(t, updated.Active)
+
+		active, err := store.GetAchievements(false, database.ListQuery[models.Achievement]{})
+		require.NoError(t, err)
+		require.Empty(t, active)
+		all, err := store.GetAchievements(true, database.ListQuery[models.Achievement]{})
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+
+		award := models.UserAchievement{
+			ID:            primitive.NewObjectID(),
+			UserID:        primitive.NewObjectID(),
+			AchievementID: rule.ID,
+			Code:          rule.Code,
+			AwardedAt:     now,
+		}
+		require.NoError(t, store.InsertUserAchievement(award))
+		again := award
+		again.ID = primitive.NewObjectID()
+		require.ErrorIs(t, store.InsertUserAchievement(again), database.ErrDuplicate)
+
+		// Eliminar la definición no retira el logro otorgado
+		require.NoError(t, store.DeleteAchievement(rule.ID))
+		require.ErrorIs(t, store.DeleteAchievement(rule.ID), database.ErrNotFound)
+		awards, err := store.GetUserAchievements(award.UserID, database.ListQuery[models.UserAchievement]{})
+		require.NoError(t, err)
+		require.Len(t, awards, 1)
+		require.Equal(t, "cinco-misiones", awards[0].Code)
+	})
+}
+
+func TestUserPreferencesAndStreak(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user := models.User{ID: primitive.NewObjectID(), Username: "racha", Email: "racha@example.com"}
+		require.NoError(t, store.InsertUser(user))
+
+		tz := "America/Guatemala"
+		updated, err := store.UpdateUser(user.ID, database.UserUpdate{Timezone: &tz})
+		require.NoError(t, err)
+		require.Equal(t, tz, updated.Timezone)
+
+		streak := models.Streak{Current: 3, Longest: 5, LastActiveDay: "2025-03-09", Freezes: 1, FrozenDays: []string{"2025-03-07"}}
+		require.NoError(t, store.UpdateUserStreak(user.ID, streak))
+		found, err := store.FindUserByID(user.ID)
+		require.NoError(t, err)
+		require.Equal(t, streak, found.Streak)
+		require.Equal(t, tz, found.Timezone)
+
+		_, err = store.UpdateUser(primitive.NewObjectID(), database.UserUpdate{Timezone: &tz})
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, store.UpdateUserStreak(primitive.NewObjectID(), streak), database.ErrNotFound)
+	})
+}
+
+func TestLeaderboardOmitsHiddenUsersAndPrivateFields(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		visible := models.User{ID: primitive.NewObjectID(), Username: "visible", Email: "visible@example.com", PasswordHash: "hash", DisplayName: "Visible", XP: 50}
+		hidden := models.User{ID: primitive.NewObjectID(), Username: "hidden", Email: "hidden@example.com", PasswordHash: "hash", HideFromLeaderboard: true}
+		require.NoError(t, store.InsertUser(visible))
+		require.NoError(t, store.InsertUser(hidden))
+		for i, user := range []models.User{visible, hidden} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: time.Now(),
+				EndDate:   time.Now(),
+				XPAwarded: 100 * (i + 1),
+			}))
+		}
+
+		require.NoError(t, store.RebuildLeaderboard())
+
+		leaderboard, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 1)
+		entry := leaderboard[0]
+		require.Equal(t, visible.ID, entry.UserID)
+		require.Equal(t, "Visible", entry.User.DisplayName)
+		require.Equal(t, 50, entry.User.XP)
+		require.Empty(t, entry.User.Email)
+		require.Empty(t, entry.User.Username)
+		require.Empty(t, entry.User.PasswordHash)
+
+		_, _, err = store.GetLeaderboardPosition(hidden.ID, models.PeriodAllTime)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		_, ahead, err := store.GetLeaderboardPosition(visible.ID, models.PeriodAllTime)
+		require.NoError(t, err)
+		require.Zero(t, ahead)
+
+		after, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime, After: &entry})
+		require.NoError(t, err)
+		require.Empty(t, after)
+	})
+}
+
+func TestLeaderboardPositionSkipsDeletedUsers(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		user := models.User{ID: primitive.NewObjectID(), Username: "sigue", Email: "sigue@example.com"}
+		require.NoError(t, store.InsertUser(user))
+		// El progreso de un usuario borrado sigue en la base y entra al
+		// ranking al reconstruirlo, por delante del que sigue
+		deleted := primitive.NewObjectID()
+		for i, userID := range []primitive.ObjectID{user.ID, deleted} {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    userID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: time.Now(),
+				EndDate:   time.Now(),
+				XPAwarded: 100 * (i + 1),
+			}))
+		}
+		require.NoError(t, store.RebuildLeaderboard())
+
+		leaderboard, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, leaderboard, 1)
+		require.Equal(t, user.ID, leaderboard[0].UserID)
+		_, ahead, err := store.GetLeaderboardPosition(user.ID, models.PeriodAllTime)
+		require.NoError(t, err)
+		require.Zero(t, ahead)
+		_, _, err = store.GetLeaderboardPosition(deleted, models.PeriodAllTime)
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestLeaderboardIncrementalUpdatesMatchRebuild(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		users := make([]models.User, 3)
+		for i := range users {
+			users[i] = models.User{ID: primitive.NewObjectID(), Username: string(rune('a' + i))}
+			require.NoError(t, store.InsertUser(users[i]))
+		}
+		complete := func(user models.User, xp int, at time.Time) {
+			require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: at.Add(-time.Minute),
+				EndDate:   at,
+				XPAwarded: xp,
+			}))
+			require.NoError(t, store.RecordLeaderboardCompletion(user.ID, xp, at))
+		}
+		complete(users[0], 100, base)
+		complete(users[0], 0, base.Add(time.Hour))
+		complete(users[1], 150, base.AddDate(0, 0, 1))
+		complete(users[1], 40, base.AddDate(0, 0, -7))
+		complete(users[2], 80, base.AddDate(0, -1, 0))
+
+		buckets := []string{models.PeriodAllTime}
+		for _, at := range []time.Time{base, base.AddDate(0, 0, -7), base.AddDate(0, -1, 0)} {
+			for _, period := range []string{models.PeriodWeekly, models.PeriodMonthly} {
+				bucket, _ := models.LeaderboardBucket(period, at)
+				buckets = append(buckets, bucket)
+			}
+		}
+		snapshot := func() map[string][]models.LeaderboardEntry {
+			result := map[string][]models.LeaderboardEntry{}
+			for _, bucket := range buckets {
+				entries, err := store.GetLeaderboard(database.LeaderboardQuery{Bucket: bucket})
+				require.NoError(t, err)
+				for i := range entries {
+					entries[i].ReachedAt = entries[i].ReachedAt.UTC()
+				}
+				result[bucket] = entries
+			}
+			return result
+		}
+
+		incremental := snapshot()
+		week, _ := models.LeaderboardBucket(models.PeriodWeekly, base)
+		require.Len(t, incremental[week], 2)
+		require.Equal(t, users[1].ID, incremental[week][0].UserID)
+		require.Equal(t, 2, incremental[week][1].CompletedCount)
+		require.True(t, incremental[week][1].ReachedAt.Equal(base.Add(time.Hour)))
+		require.Len(t, incremental[models.PeriodAllTime], 3)
+		require.Equal(t, 190, incremental[models.PeriodAllTime][0].XP)
+
+		require.NoError(t, store.RebuildLeaderboard())
+		require.Equal(t, incremental, snapshot())
+
+		// Ocultar el perfil lo saca de todos los rankings que ya tenía
+		hide := true
+		_, err := store.UpdateUser(users[1].ID, database.UserUpdate{HideFromLeaderboard: &hide})
+		require.NoError(t, err)
+		for _, bucket := range buckets {
+			_, _, err := store.GetLeaderboardPosition(users[1].ID, bucket)
+			require.ErrorIs(t, err, database.ErrNotFound)
+		}
+		complete(users[1], 10, base)
+		_, _, err = store.GetLeaderboardPosition(users[1].ID, week)
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestClassroomsAndEnrollment(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		teacherID := primitive.NewObjectID()
+		studentID := primitive.NewObjectID()
+		classroom := models.Classroom{ID: primitive.NewObjectID(), Name: "Ciencias", TeacherID: teacherID, JoinCode: "ABCD2345", CreatedAt: time.Now()}
+		require.NoError(t, store.InsertClassroom(classroom))
+		other := models.Classroom{ID: primitive.NewObjectID(), Name: "Otra", TeacherID: teacherID, JoinCode: "ABCD2345", CreatedAt: time.Now()}
+		require.ErrorIs(t, store.InsertClassroom(other), database.ErrDuplicate)
+
+		found, err := store.FindClassroomByJoinCode("ABCD2345")
+		require.NoError(t, err)
+		require.Equal(t, classroom.ID, found.ID)
+		_, err = store.FindClassroomByJoinCode("ZZZZ9999")
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// Inscribirse dos veces no duplica al estudiante
+		require.NoError(t, store.AddClassroomStudent(classroom.ID, studentID))
+		require.NoError(t, store.AddClassroomStudent(classroom.ID, studentID))
+		require.ErrorIs(t, store.AddClassroomStudent(primitive.NewObjectID(), studentID), database.ErrNotFound)
+		found, err = store.GetClassroomByID(classroom.ID)
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{studentID}, found.StudentIDs)
+
+		enrolled, err := store.GetStudentClassrooms(studentID, database.ListQuery[models.Classroom]{})
+		require.NoError(t, err)
+		require.Len(t, enrolled, 1)
+		owned, err := store.GetTeacherClassrooms(teacherID, database.ListQuery[models.Classroom]{})
+		require.NoError(t, err)
+		require.Len(t, owned, 1)
+		owned, err = store.GetTeacherClassrooms(teacherID, database.ListQuery[models.Classroom]{After: &classroom})
+		require.NoError(t, err)
+		require.Empty(t, owned)
+
+		require.NoError(t, store.RemoveClassroomStudent(classroom.ID, studentID))
+		enrolled, err = store.GetStudentClassrooms(studentID, database.ListQuery[models.Classroom]{})
+		require.NoError(t, err)
+		require.Empty(t, enrolled)
+		found, err = store.GetClassroomByID(classroom.ID)
+		require.NoError(t, err)
+		require.Empty(t, found.StudentIDs)
+	})
+}
+
+func TestStudentAssignmentsIncludeClassrooms(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		teacherID := primitive.NewObjectID()
+		studentID := primitive.NewObjectID()
+		classroomID := primitive.NewObjectID()
+		assign := func(classroom, student primitive.ObjectID, due time.Time) models.Assignment {
+			a := models.Assignment{
+				ID:          primitive.NewObjectID(),
+				MissionID:   primitive.NewObjectID(),
+				TeacherID:   teacherID,
+				ClassroomID: classroom,
+				StudentID:   student,
+				OpensAt:     base,
+				DueAt:       due,
+				CreatedAt:   base,
+			}
+			require.NoError(t, store.InsertAssignment(a))
+			return a
+		}
+		toClass := assign(classroomID, primitive.NilObjectID, base.Add(48*time.Hour))
+		toStudent := assign(primitive.NilObjectID, studentID, base.Add(24*time.Hour))
+		upcoming := toStudent
+		upcoming.ID = primitive.NewObjectID()
+		upcoming.OpensAt = base.Add(time.Hour)
+		require.NoError(t, store.InsertAssignment(upcoming))
+		assign(primitive.NewObjectID(), primitive.NilObjectID, base.Add(time.Hour))
+		assign(primitive.NilObjectID, primitive.NewObjectID(), base.Add(time.Hour))
+
+		found, err := store.GetAssignmentByID(toClass.ID)
+		require.NoError(t, err)
+		require.Equal(t, classroomID, found.ClassroomID)
+		require.True(t, found.StudentID.IsZero())
+		_, err = store.GetAssignmentByID(primitive.NewObjectID())
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// Ordenadas por fecha de entrega
+		all := database.ListQuery[models.Assignment]{}
+		mine, err := store.GetStudentAssignments(studentID, []primitive.ObjectID{classroomID}, time.Time{}, all)
+		require.NoError(t, err)
+		require.Len(t, mine, 3)
+		require.Equal(t, toStudent.ID, mine[0].ID)
+		require.Equal(t, upcoming.ID, mine[1].ID)
+		require.Equal(t, toClass.ID, mine[2].ID)
+
+		// Las que todavía no abren se omiten; la página sigue a la anterior
+		mine, err = store.GetStudentAssignments(studentID, []primitive.ObjectID{classroomID}, base, database.ListQuery[models.Assignment]{Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{toStudent.ID}, []primitive.ObjectID{mine[0].ID})
+		mine, err = store.GetStudentAssignments(studentID, []primitive.ObjectID{classroomID}, base, database.ListQuery[models.Assignment]{After: &mine[0]})
+		require.NoError(t, err)
+		require.Len(t, mine, 1)
+		require.Equal(t, toClass.ID, mine[0].ID)
+
+		mine, err = store.GetStudentAssignments(studentID, nil, time.Time{}, all)
+		require.NoError(t, err)
+		require.Len(t, mine, 2)
+
+		owned, err := store.GetTeacherAssignments(teacherID, all)
+		require.NoError(t, err)
+		require.Len(t, owned, 5)
+	})
+}
+
+func TestTenantScopedStores(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		north := store.ForTenant("colegio-norte")
+		require.NoError(t, store.InsertTenant(models.Tenant{ID: "colegio-norte", Name: "Colegio Norte"}))
+		require.ErrorIs(t, store.InsertTenant(models.Tenant{ID: "colegio-norte"}), database.ErrDuplicate)
+		_, err := north.GetTenant(models.DefaultTenantID)
+		require.NoError(t, err)
+		_, err = store.GetTenant("colegio-sur")
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// El mismo email puede existir en dos organizaciones
+		emma := models.User{ID: primitive.NewObjectID(), Username: "emma", Email: "alumno@example.com"}
+		nora := models.User{ID: primitive.NewObjectID(), Username: "nora", Email: "alumno@example.com"}
+		require.NoError(t, store.InsertUser(emma))
+		require.NoError(t, north.InsertUser(nora))
+		found, err := north.FindUserByEmail("alumno@example.com")
+		require.NoError(t, err)
+		require.Equal(t, nora.ID, found.ID)
+		_, err = north.FindUserByID(emma.ID)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, north.AddUserXP(emma.ID, 10), database.ErrNotFound)
+
+		global := models.Mission{ID: primitive.NewObjectID(), Title: "Global", Global: true}
+		local := models.Mission{ID: primitive.NewObjectID(), Title: "Local"}
+		require.NoError(t, store.InsertMission(global))
+		require.NoError(t, store.InsertMission(local))
+		missions, err := north.GetAllMissions(false)
+		require.NoError(t, err)
+		require.Len(t, missions, 1)
+		require.Equal(t, global.ID, missions[0].ID)
+		missions, err = north.ListMissions(database.MissionList{OwnOnly: true})
+		require.NoError(t, err)
+		require.Empty(t, missions)
+		_, err = north.GetMissionByID(local.ID)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		_, err = north.UpdateMission(global.ID, database.MissionUpdate{Title: &local.Title}, time.Now())
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, north.DeleteMission(global.ID), database.ErrNotFound)
+
+		now := time.Now().Truncate(time.Millisecond)
+		complete := func(s database.Store, userID, missionID primitive.ObjectID) {
+			p := models.NewMissionProgress(userID, missionID, now)
+			p.Status = models.ProgressCompleted
+			p.EndDate = now
+			p.XPAwarded = 100
+			require.NoError(t, s.InsertMissionProgress(p))
+			require.NoError(t, s.RecordLeaderboardCompletion(userID, 100, now))
+		}
+		complete(store, emma.ID, global.ID)
+		complete(store, emma.ID, local.ID)
+		complete(north, nora.ID, global.ID)
+
+		// Ningún ranking ni resumen mezcla organizaciones
+		board, err := north.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, board, 1)
+		require.Equal(t, nora.ID, board[0].UserID)
+		_, _, err = north.GetLeaderboardPosition(emma.ID, models.PeriodAllTime)
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		require.NoError(t, north.RebuildLeaderboard())
+		board, err = store.GetLeaderboard(database.LeaderboardQuery{Bucket: models.PeriodAllTime})
+		require.NoError(t, err)
+		require.Len(t, board, 1)
+		require.Equal(t, emma.ID, board[0].UserID)
+		require.Equal(t, 2, board[0].CompletedCount)
+
+		overview, err := north.GetMissionsOverview()
+		require.NoError(t, err)
+		avg := overview["avgCompletionTimes"].([]bson.M)
+		require.Len(t, avg, 1)
+		require.EqualValues(t, 1, avg[0]["count"])
+
+		// El avance de nora se calcula sobre las misiones que ve: solo la global
+		stats, err := north.GetUserStatistics(nora.ID)
+		require.NoError(t, err)
+		require.EqualValues(t, 100, stats["progressPercentage"])
+		_, err = north.FindMissionProgress(emma.ID, global.ID)
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		// Borrar una misión global considera el progreso de todas las organizaciones
+		has, err := store.HasMissionProgress(global.ID)
+		require.NoError(t, err)
+		require.True(t, has)
+	})
+}
+
+func TestLearningPathsAndPrerequisites(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		first := models.Mission{ID: primitive.NewObjectID(), Title: "Sol", CreatedAt: base}
+		second := models.Mission{ID: primitive.NewObjectID(), Title: "Mercurio", Prerequisites: []primitive.ObjectID{first.ID}, CreatedAt: base}
+		require.NoError(t, store.InsertMission(first))
+		require.NoError(t, store.InsertMission(second))
+
+		found, err := store.GetMissionByID(second.ID)
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{first.ID}, found.Prerequisites)
+
+		// Una lista vacía elimina los prerrequisitos
+		empty := []primitive.ObjectID{}
+		updated, err := store.UpdateMission(second.ID, database.MissionUpdate{Prerequisites: &empty}, base)
+		require.NoError(t, err)
+		require.Empty(t, updated.Prerequisites)
+
+		path := models.LearningPath{
+			ID:    primitive.NewObjectID(),
+			Title: "Sistema solar",
+			Chapters: []models.PathChapter{
+				{Title: "Inicio", MissionIDs: []primitive.ObjectID{first.ID}},
+				{Title: "Planetas", MissionIDs: []primitive.ObjectID{second.ID}},
+			},
+			CreatedAt: base,
+		}
+		require.NoError(t, store.InsertPath(path))
+		later := path
+		later.ID = primitive.NewObjectID()
+		later.CreatedAt = base.Add(time.Hour)
+		require.NoError(t, store.InsertPath(later))
+
+		paths, err := store.GetPaths(database.ListQuery[models.LearningPath]{})
+		require.NoError(t, err)
+		require.Len(t, paths, 2)
+		require.Equal(t, path.ID, paths[0].ID)
+		require.Equal(t, []primitive.ObjectID{first.ID, second.ID}, paths[0].MissionIDs())
+		paths, err = store.GetPaths(database.ListQuery[models.LearningPath]{After: &path, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		require.Equal(t, later.ID, paths[0].ID)
+
+		path.Title = "El sistema solar"
+		path.Chapters = path.Chapters[:1]
+		path.UpdatedAt = base.Add(time.Hour)
+		require.NoError(t, store.ReplacePath(path))
+		replaced, err := store.GetPathByID(path.ID)
+		require.NoError(t, err)
+		require.Equal(t, "El sistema solar", replaced.Title)
+		require.Len(t, replaced.Chapters, 1)
+
+		// Las rutas no se ven desde otras organizaciones
+		_, err = store.ForTenant("colegio-norte").GetPathByID(path.ID)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, store.ForTenant("colegio-norte").DeletePath(path.ID), database.ErrNotFound)
+
+		require.NoError(t, store.DeletePath(path.ID))
+		_, err = store.GetPathByID(path.ID)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, store.ReplacePath(path), database.ErrNotFound)
+	})
+}
+
+func TestFindMissionsFiltersSortsAndFacets(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		insert := func(m models.Mission) models.Mission {
+			m.ID = primitive.NewObjectID()
+			m.CreatedAt = base.Add(time.Duration(m.Difficulty) * time.Minute)
+			require.NoError(t, store.InsertMission(m))
+			return m
+		}
+		mars := insert(models.Mission{Title: "Marte", Category: "astronomia", Tags: []string{"planetas", "marte"}, Difficulty: 3, GradeLevel: 5, EstimatedMinutes: 30})
+		moon := insert(models.Mission{Title: "Luna", Category: "astronomia", Tags: []string{"satelites"}, Difficulty: 1, GradeLevel: 3, EstimatedMinutes: 10})
+		cells := insert(models.Mission{Title: "Células", Category: "biologia", Tags: []string{"planetas"}, Difficulty: 2, GradeLevel: 5})
+		insert(models.Mission{Title: "Archivada", Category: "astronomia", Difficulty: 4, Archived: true})
+
+		ids := func(missions []models.Mission) []primitive.ObjectID {
+			out := []primitive.ObjectID{}
+			for _, m := range missions {
+				out = append(out, m.ID)
+			}
+			return out
+		}
+
+		page, err := store.FindMissions(database.MissionQuery{})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{moon.ID, cells.ID, mars.ID}, ids(page.Missions))
+		require.Equal(t, 3, page.Total)
+		require.Equal(t, map[string]int{"astronomia": 2, "biologia": 1}, page.Facets.Categories)
+		require.Equal(t, map[string]int{"planetas": 2, "marte": 1, "satelites": 1}, page.Facets.Tags)
+		require.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, page.Facets.Difficulties)
+		require.Equal(t, map[int]int{3: 1, 5: 2}, page.Facets.GradeLevels)
+
+		page, err = store.FindMissions(database.MissionQuery{Tags: []string{"planetas"}, GradeLevel: 5, Sort: database.SortByTitle})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{cells.ID, mars.ID}, ids(page.Missions))
+		require.Equal(t, map[string]int{"astronomia": 1, "biologia": 1}, page.Facets.Categories)
+
+		page, err = store.FindMissions(database.MissionQuery{MaxMinutes: 30, Sort: database.SortByDuration, SortDescending: true})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{mars.ID, moon.ID}, ids(page.Missions))
+
+		page, err = store.FindMissions(database.MissionQuery{Category: "astronomia", IDs: []primitive.ObjectID{mars.ID, cells.ID}})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{mars.ID}, ids(page.Missions))
+
+		page, err = store.FindMissions(database.MissionQuery{IDs: []primitive.ObjectID{}})
+		require.NoError(t, err)
+		require.Empty(t, page.Missions)
+		require.Zero(t, page.Total)
+
+		// El total y las facetas cuentan todas las misiones, no solo la página
+		page, err = store.FindMissions(database.MissionQuery{After: &moon, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{cells.ID}, ids(page.Missions))
+		require.Equal(t, 3, page.Total)
+		require.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, page.Facets.Difficulties)
+
+		page, err = store.FindMissions(database.MissionQuery{After: &mars, Limit: 1})
+		require.NoError(t, err)
+		require.Empty(t, page.Missions)
+		require.Equal(t, 3, page.Total)
+
+		// Sin duración estimada se ordena primero en orden ascendente y al
+		// final en descendente, también al seguir desde After
+		page, err = store.FindMissions(database.MissionQuery{Sort: database.SortByDuration, After: &cells})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{moon.ID, mars.ID}, ids(page.Missions))
+		page, err = store.FindMissions(database.MissionQuery{Sort: database.SortByDuration, SortDescending: true, After: &moon})
+		require.NoError(t, err)
+		require.Equal(t, []primitive.ObjectID{cells.ID}, ids(page.Missions))
+		page, err = store.FindMissions(database.MissionQuery{Sort: database.SortByDuration, SortDescending: true, After: &cells})
+		require.NoError(t, err)
+		require.Empty(t, page.Missions)
+	})
+}
+
+func TestListMissionProgressSortsAndPaginates(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		userID := primitive.NewObjectID()
+		start := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		insert := func(status models.ProgressStatus, startOffset, endOffset time.Duration) models.MissionProgress {
+			progress := models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    userID,
+				MissionID: primitive.NewObjectID(),
+				Status:    status,
+				StartDate: start.Add(startOffset),
+			}
+			if endOffset > 0 {
+				progress.EndDate = start.Add(endOffset)
+			}
+			require.NoError(t, store.InsertMissionProgress(progress))
+			return progress
+		}
+		first := insert(models.ProgressCompleted, 0, 3*time.Hour)
+		second := insert(models.ProgressStarted, time.Hour, 0)
+		third := insert(models.ProgressCompleted, 2*time.Hour, 2*time.Hour+time.Minute)
+		fourth := insert(models.ProgressPaused, 2*time.Hour, 0)
+		require.NoError(t, store.InsertMissionProgress(models.MissionProgress{
+			ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), MissionID: primitive.NewObjectID(),
+			Status: models.ProgressStarted, StartDate: start,
+		}))
+
+		list := func(query database.ProgressQuery) []primitive.ObjectID {
+			query.UserID = userID
+			progress, err := store.ListMissionProgress(query)
+			require.NoError(t, err)
+			ids := []primitive.ObjectID{}
+			for _, p := range progress {
+				ids = append(ids, p.Progress.ID)
+			}
+			return ids
+		}
+
+		// A igual fecha de inicio se desempata por ID en ambos sentidos
+		require.Equal(t, []primitive.ObjectID{first.ID, second.ID, third.ID, fourth.ID}, list(database.ProgressQuery{}))
+		require.Equal(t, []primitive.ObjectID{third.ID, fourth.ID, second.ID, first.ID}, list(database.ProgressQuery{SortDescending: true}))
+		require.Equal(t, []primitive.ObjectID{second.ID, third.ID}, list(database.ProgressQuery{After: &first, Limit: 2}))
+		require.Equal(t, []primitive.ObjectID{second.ID, first.ID}, list(database.ProgressQuery{SortDescending: true, After: &fourth}))
+		require.Empty(t, list(database.ProgressQuery{After: &fourth}))
+
+		// Sin fecha de fin se ordena primero en orden ascendente y al final
+		// en descendente, también al seguir desde After
+		byEnd := database.ProgressQuery{Sort: database.SortByEndDate}
+		require.Equal(t, []primitive.ObjectID{second.ID, fourth.ID, third.ID, first.ID}, list(byEnd))
+		byEnd.After = &fourth
+		require.Equal(t, []primitive.ObjectID{third.ID, first.ID}, list(byEnd))
+		byEnd.SortDescending, byEnd.After = true, &third
+		require.Equal(t, []primitive.ObjectID{second.ID, fourth.ID}, list(byEnd))
+
+		completed := []models.ProgressStatus{models.ProgressCompleted}
+		require.Equal(t, []primitive.ObjectID{first.ID, third.ID}, list(database.ProgressQuery{Statuses: completed, Sort: database.SortByEndDate, SortDescending: true}))
+		active := []models.ProgressStatus{models.ProgressStarted, models.ProgressPaused}
+		require.Equal(t, []primitive.ObjectID{fourth.ID}, list(database.ProgressQuery{Statuses: active, SortDescending: true, Limit: 1}))
+
+		// Con WithMission cada progreso trae su misión, aunque esté
+		// archivada; si ya no existe, Mission es nil
+		mission := models.Mission{ID: first.MissionID, Title: "Marte", Archived: true, Steps: []models.MissionStep{{ID: primitive.NewObjectID(), Title: "Paso"}}}
+		require.NoError(t, store.InsertMission(mission))
+		progress, err := store.ListMissionProgress(database.ProgressQuery{UserID: userID, Limit: 2})
+		require.NoError(t, err)
+		require.Nil(t, progress[0].Mission)
+		progress, err = store.ListMissionProgress(database.ProgressQuery{UserID: userID, Limit: 2, WithMission: true})
+		require.NoError(t, err)
+		require.Len(t, progress, 2)
+		require.Equal(t, first.ID, progress[0].Progress.ID)
+		require.Equal(t, first.EndDate, progress[0].Progress.EndDate)
+		require.NotNil(t, progress[0].Mission)
+		require.Equal(t, "Marte", progress[0].Mission.Title)
+		require.Len(t, progress[0].Mission.Steps, 1)
+		require.Nil(t, progress[1].Mission)
+	})
+}
+
+func TestSearchMissionsIgnoresAccentsAndRanksByField(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		insert := func(m models.Mission) models.Mission {
+			m.ID = primitive.NewObjectID()
+			require.NoError(t, store.InsertMission(m))
+			return m
+		}
+		inTitle := insert(models.Mission{Title: "Órbita lunar", Description: "Gira alrededor de la Luna"})
+		inTags := insert(models.Mission{Title: "Satélites", Description: "Lanza un satélite", Tags: []string{"orbitas"}})
+		inDescription := insert(models.Mission{Title: "Cohetes", Description: "Pon el cohete en órbita"})
+		insert(models.Mission{Title: "Órbita archivada", Archived: true})
+		insert(models.Mission{Title: "Volcanes", Description: "Erupciones"})
+
+		matches, err := store.SearchMissions(database.MissionSearch{Text: "orbita"})
+		require.NoError(t, err)
+		require.Len(t, matches, 3)
+		require.Equal(t, inTitle.ID, matches[0].Mission.ID)
+		require.Equal(t, inTags.ID, matches[1].Mission.ID)
+		require.Equal(t, inDescription.ID, matches[2].Mission.ID)
+
+		page, err := store.SearchMissions(database.MissionSearch{Text: "orbita", After: &matches[0], Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		require.Equal(t, inTags.ID, page[0].Mission.ID)
+
+		matches, err = store.SearchMissions(database.MissionSearch{Text: "de la"})
+		require.NoError(t, err)
+		require.Empty(t, matches)
+	})
+}
+
+func TestMissionTranslationsAndUserLocale(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		base := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+		mission := models.Mission{ID: primitive.NewObjectID(), Title: "Explorar Marte", CreatedAt: base}
+		require.NoError(t, store.InsertMission(mission))
+
+		english := &models.MissionTranslation{Title: "Explore Mars"}
+		portuguese := &models.MissionTranslation{Title: "Explorar Marte", Description: "Percorra Marte"}
+		updated, err := store.UpdateMission(mission.ID, database.MissionUpdate{
+			Translations: map[string]*models.MissionTranslation{"en": english, "pt": portuguese},
+		}, base)
+		require.NoError(t, err)
+		require.Len(t, updated.Translations, 2)
+
+		// Cada idioma se reemplaza o elimina sin tocar los demás
+		english = &models.MissionTranslation{Title: "Explore Mars", Description: "Travel across Mars"}
+		_, err = store.UpdateMission(mission.ID, database.MissionUpdate{
+			Translations: map[string]*models.MissionTranslation{"en": english},
+		}, base)
+		require.NoError(t, err)
+		_, err = store.UpdateMission(mission.ID, database.MissionUpdate{
+			Translations: map[string]*models.MissionTranslation{"pt": nil},
+		}, base)
+		require.NoError(t, err)
+
+		found, err := store.GetMissionByID(mission.ID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]models.MissionTranslation{"en": *english}, found.Translations)
+		require.Empty(t, updated.Translations["en"].Description, "las copias devueltas no cambian")
+
+		user := models.User{ID: primitive.NewObjectID(), Username: "ana", Email: "ana@example.com", CreatedAt: base}
+		require.NoError(t, store.InsertUser(user))
+		locale := "en"
+		updatedUser, err := store.UpdateUser(user.ID, database.UserUpdate{Locale: &locale})
+		require.NoError(t, err)
+		require.Equal(t, "en", updatedUser.Locale)
+	})
+}
+
+func TestCountCompletionsByDayUsesTimeZone(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store database.Store) {
+		loc, err := time.LoadLocation("America/Guatemala")
+		require.NoError(t, err)
+		userID := primitive.NewObjectID()
+		// 02:00 UTC del 10 de marzo es todavía el 9 de marzo en Guatemala
+		for _, end := range []time.Time{
+			time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC),
+		} {
+			progress := models.NewMissionProgress(userID, primitive.NewObjectID(), end.Add(-time.Hour))
+			require.NoError(t, progress.Apply(models.ActionComplete, end))
+			require.NoError(t, store.InsertMissionProgress(progress))
+		}
+		require.NoError(t, store.InsertMissionProgress(models.NewMissionProgress(userID, primitive.NewObjectID(), time.Now())))
+
+		days, err := store.CountCompletionsByDay(userID, time.Date(2026, 3, 5, 0, 0, 0, 0, loc), loc)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"2026-03-09": 1, "2026-03-10": 1}, days)
+	})
+}
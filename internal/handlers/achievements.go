@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateAchievementRequest define un logro nuevo.
+// @Description Estructura para crear un logro
+type CreateAchievementRequest struct {
+	Code        string                 `json:"code" binding:"required" example:"cinco-misiones"`
+	Name        string                 `json:"name" binding:"required" example:"Explorador constante"`
+	Description string                 `json:"description" example:"Completa 5 misiones"`
+	Rule        models.AchievementRule `json:"rule"`
+	// Active es true si no se indica
+	Active *bool `json:"active" example:"true"`
+}
+
+// PatchAchievementRequest actualiza parcialmente un logro. El código no se
+// puede cambiar porque identifica al logro en los clientes.
+// @Description Estructura para actualizar parcialmente un logro
+type PatchAchievementRequest struct {
+	Name        *string                 `json:"name" example:"Explorador constante"`
+	Description *string                 `json:"description" example:"Completa 5 misiones"`
+	Rule        *models.AchievementRule `json:"rule"`
+	Active      *bool                   `json:"active" example:"false"`
+}
+
+// checkAchievements evalúa las reglas activas contra el historial del usuario
+// y le otorga los logros que acaba de conseguir. Se llama tras iniciar o
+// completar una misión. Los errores se registran sin afectar la respuesta:
+// los logros pendientes se otorgan en la siguiente evaluación.
+func (s *Server) checkAchievements(userID primitive.ObjectID) []models.UserAchievement {
+	awarded := []models.UserAchievement{}
+	rules, err := s.achievements.GetAchievements(false, database.ListQuery[models.Achievement]{})
+	if err != nil || len(rules) == 0 {
+		s.logAchievementError(userID, err)
+		return awarded
+	}
+
+	earned, err := s.achievements.GetUserAchievements(userID, database.ListQuery[models.UserAchievement]{})
+	if err != nil {
+		s.logAchievementError(userID, err)
+		return awarded
+	}
+	has := make(map[primitive.ObjectID]bool, len(earned))
+	for _, e := range earned {
+		has[e.AchievementID] = true
+	}
+
+	summary, err := s.progress.GetProgressSummary(userID)
+	if err != nil {
+		s.logAchievementError(userID, err)
+		return awarded
+	}
+	user, err := s.users.FindUserByID(userID)
+	if err != nil {
+		s.logAchievementError(userID, err)
+		return awarded
+	}
+
+	now := s.now()
+	loc := user.Location()
+	completionsByDay, err := s.progress.CountCompletionsByDay(userID, time.Time{}, loc)
+	if err != nil {
+		s.logAchievementError(userID, err)
+		return awarded
+	}
+
+	facts := models.AchievementFacts{
+		MissionsStarted:   summary.Started,
+		MissionsCompleted: summary.Completed,
+		FastestCompletion: summary.FastestCompletion,
+		StreakDays:        models.StreakDays(completionsByDay, now, loc),
+		XP:                user.XP,
+	}
+	// La racha guardada incluye los días cubiertos por protecciones; la
+	// calculada del historial cubre a quienes completaron misiones antes de
+	// que existiera
+	if current := user.Streak.CurrentAt(now, loc); current > facts.StreakDays {
+		facts.StreakDays = current
+	}
+	for _, rule := range rules {
+		if has[rule.ID] || !rule.Rule.Satisfied(facts) {
+			continue
+		}
+		award := models.UserAchievement{
+			ID:            primitive.NewObjectID(),
+			UserID:        userID,
+			AchievementID: rule.ID,
+			Code:          rule.Code,
+			Name:          rule.Name,
+			Description:   rule.Description,
+			AwardedAt:     now,
+		}
+		// Otra petición simultánea pudo otorgarlo primero
+		if err := s.achievements.InsertUserAchievement(award); err != nil {
+			if !errors.Is(err, database.ErrDuplicate) {
+				s.logAchievementError(userID, err)
+			}
+			continue
+		}
+		awarded = append(awarded, award)
+	}
+	return awarded
+}
+
+func (s *Server) logAchievementError(userID primitive.ObjectID, err error) {
+	if err != nil {
+		s.logger.Printf("No se pudieron evaluar los logros del usuario %s: %v", userID.Hex(), err)
+	}
+}
+
+// awardOrder es el orden de los logros de un usuario: por fecha de obtención
+// y luego por ID.
+var awardOrder = listOrder[models.UserAchievement, dateKey]{
+	key:  func(a models.UserAchievement) dateKey { return dateKey{Date: a.AwardedAt, ID: a.ID} },
+	item: func(k dateKey) models.UserAchievement { return models.UserAchievement{ID: k.ID, AwardedAt: k.Date} },
+}
+
+// achievementOrder es el orden de las definiciones de logros: por fecha de
+// creación y luego por ID.
+var achievementOrder = listOrder[models.Achievement, dateKey]{
+	key:  func(a models.Achievement) dateKey { return dateKey{Date: a.CreatedAt, ID: a.ID} },
+	item: func(k dateKey) models.Achievement { return models.Achievement{ID: k.ID, CreatedAt: k.Date} },
+}
+
+// GetMyAchievements godoc
+// @Summary Lista los logros del usuario
+// @Description Devuelve una página de los logros obtenidos por el usuario autenticado, en el orden en que los consiguió. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Achievements
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.UserAchievement]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener los logros"
+// @Router /me/achievements [get]
+func (s *Server) GetMyAchievements(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(models.UserAchievement{}))
+	if !ok {
+		return
+	}
+	query, ok := awardOrder.query(c, page)
+	if !ok {
+		return
+	}
+
+	awards, err := s.achievements.GetUserAchievements(userObjID, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener los logros", err)
+		return
+	}
+	awards, next := awardOrder.next(c, page, awards)
+	respondPage(s, c, page, awards, next)
+}
+
+// ListAchievements godoc
+// @Summary Lista las definiciones de logros
+// @Description Retorna una página de las reglas de logros, incluidas las inactivas, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.Achievement]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener los logros"
+// @Router /admin/achievements [get]
+func (s *Server) ListAchievements(c *gin.Context) {
+	page, ok := parsePage(c, listSpec(models.Achievement{}))
+	if !ok {
+		return
+	}
+	query, ok := achievementOrder.query(c, page)
+	if !ok {
+		return
+	}
+	achievements, err := s.achievements.GetAchievements(true, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener los logros", err)
+		return
+	}
+	achievements, next := achievementOrder.next(c, page, achievements)
+	respondPage(s, c, page, achievements, next)
+}
+
+// CreateAchievement godoc
+// @Summary Define un logro nuevo
+// @Description Crea una regla de logro que se evalúa al iniciar o completar misiones. Tipos de regla: missions_completed, missions_started, fast_completion (minutos), streak_days y xp_total.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param achievement body CreateAchievementRequest true "Definición del logro"
+// @Success 201 {object} models.Achievement
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 409 {object} apierror.Response "Ya existe un logro con ese código"
+// @Failure 500 {object} apierror.Response "No se pudo crear el logro"
+// @Router /admin/achievements [post]
+func (s *Server) CreateAchievement(c *gin.Context) {
+	var input CreateAchievementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	code := strings.TrimSpace(input.Code)
+	name := strings.TrimSpace(input.Name)
+	if code == "" {
+		fail(c, apierror.Invalid(apierror.Field("code", apierror.Required)))
+		return
+	}
+	if name == "" {
+		fail(c, apierror.Invalid(apierror.Field("name", apierror.Required)))
+		return
+	}
+	if !input.Rule.Valid() {
+		fail(c, apierror.Invalid(apierror.Field("rule", apierror.InvalidRule)))
+		return
+	}
+
+	achievement := models.Achievement{
+		ID:          primitive.NewObjectID(),
+		Code:        code,
+		Name:        name,
+		Description: strings.TrimSpace(input.Description),
+		Rule:        input.Rule,
+		Active:      input.Active == nil || *input.Active,
+		CreatedAt:   s.now(),
+	}
+	if err := s.achievements.InsertAchievement(achievement); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			fail(c, apierror.New(apierror.AchievementCodeTaken))
+		} else {
+			s.internalError(c, "No se pudo crear el logro", err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, achievement)
+}
+
+// PatchAchievement godoc
+// @Summary Actualiza parcialmente un logro
+// @Description Modifica solo los campos enviados. Con "active": false el logro deja de otorgarse; quienes ya lo tienen lo conservan.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID del logro"
+// @Param achievement body PatchAchievementRequest true "Campos a modificar"
+// @Success 200 {object} models.Achievement
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Logro no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudo actualizar el logro"
+// @Router /admin/achievements/{id} [patch]
+func (s *Server) PatchAchievement(c *gin.Context) {
+	achievementID, ok := achievementIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input PatchAchievementRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if input.Name == nil && input.Description == nil && input.Rule == nil && input.Active == nil {
+		fail(c, apierror.Invalid(apierror.Field("", apierror.NoChanges)))
+		return
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		fail(c, apierror.Invalid(apierror.Field("name", apierror.Required)))
+		return
+	}
+	if input.Rule != nil && !input.Rule.Valid() {
+		fail(c, apierror.Invalid(apierror.Field("rule", apierror.InvalidRule)))
+		return
+	}
+
+	achievement, err := s.achievements.UpdateAchievement(achievementID, database.AchievementUpdate{
+		Name:        input.Name,
+		Description: input.Description,
+		Rule:        input.Rule,
+		Active:      input.Active,
+	}, s.now())
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.AchievementNotFound))
+		} else {
+			s.internalError(c, "No se pudo actualizar el logro", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, achievement)
+}
+
+// DeleteAchievement godoc
+// @Summary Elimina un logro
+// @Description Elimina la definición de un logro. Los usuarios que ya lo obtuvieron lo conservan.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID del logro"
+// @Success 200 {object} GenericResponse "Logro eliminado"
+// @Failure 400 {object} apierror.Response "ID de logro inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Logro no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudo eliminar el logro"
+// @Router /admin/achievements/{id} [delete]
+func (s *Server) DeleteAchievement(c *gin.Context) {
+	achievementID, ok := achievementIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := s.achievements.DeleteAchievement(achievementID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.AchievementNotFound))
+		} else {
+			s.internalError(c, "No se pudo eliminar el logro", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Logro eliminado"})
+}
+
+// achievementIDParam convierte el parámetro :id de la ruta a ObjectID. Si no
+// es válido responde 400 y devuelve false.
+func achievementIDParam(c *gin.Context) (primitive.ObjectID, bool) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return primitive.NilObjectID, false
+	}
+	return objID, true
+}
@@ -0,0 +1,541 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MissionStepInput describe un paso al crear o editar una misión. Los pasos
+// que ya existían se envían con su id para conservar el avance de los usuarios;
+// los nuevos reciben un id al guardarse.
+// @Description Paso de una misión
+type MissionStepInput struct {
+	ID          string `json:"id,omitempty" example:"60a7b97f5e41c42e7c2e30b7"`
+	Title       string `json:"title" example:"Aterriza en el cráter"`
+	Description string `json:"description" example:"Elige un punto de aterrizaje seguro"`
+	Optional    bool   `json:"optional" example:"false"`
+}
+
+// UpdateMissionRequest representa el reemplazo completo de los datos editables de una misión.
+// @Description Estructura para reemplazar una misión
+type UpdateMissionRequest struct {
+	Title       string             `json:"title" binding:"required" example:"Explorar Marte"`
+	Description string             `json:"description" binding:"required" example:"Recorre la superficie marciana"`
+	Steps       []MissionStepInput `json:"steps"`
+	Quiz        *QuizInput         `json:"quiz"`
+	// Prerequisites son los IDs de las misiones que hay que completar antes
+	Prerequisites []string `json:"prerequisites" example:"60a7b97f5e41c42e7c2e30b5"`
+	// Category, Tags, Difficulty (1 a 5), GradeLevel (1 a 12) y
+	// EstimatedMinutes clasifican la misión; en cero no se indican
+	Category         string   `json:"category" example:"astronomia"`
+	Tags             []string `json:"tags" example:"planetas,marte"`
+	Difficulty       int      `json:"difficulty" example:"2"`
+	GradeLevel       int      `json:"grade_level" example:"5"`
+	EstimatedMinutes int      `json:"estimated_minutes" example:"20"`
+	// XPReward y DifficultyMultiplier en 0 usan los valores por defecto
+	XPReward             int     `json:"xp_reward" example:"150"`
+	DifficultyMultiplier float64 `json:"difficulty_multiplier" example:"1.5"`
+}
+
+// PatchMissionRequest representa una actualización parcial de una misión.
+// Enviar "archived": true retira la misión del catálogo sin borrar el historial.
+// @Description Estructura para actualizar parcialmente una misión
+type PatchMissionRequest struct {
+	Title                *string             `json:"title" example:"Explorar Marte"`
+	Description          *string             `json:"description" example:"Recorre la superficie marciana"`
+	Archived             *bool               `json:"archived" example:"true"`
+	Steps                *[]MissionStepInput `json:"steps"`
+	Quiz                 *QuizInput          `json:"quiz"`
+	Prerequisites        *[]string           `json:"prerequisites"`
+	Category             *string             `json:"category" example:"astronomia"`
+	Tags                 *[]string           `json:"tags"`
+	Difficulty           *int                `json:"difficulty" example:"2"`
+	GradeLevel           *int                `json:"grade_level" example:"5"`
+	EstimatedMinutes     *int                `json:"estimated_minutes" example:"20"`
+	XPReward             *int                `json:"xp_reward" example:"150"`
+	DifficultyMultiplier *float64            `json:"difficulty_multiplier" example:"1.5"`
+}
+
+// missionOrder es el orden de database.MissionList: por fecha de creación y
+// luego por ID.
+var missionOrder = listOrder[models.Mission, dateKey]{
+	key:  func(m models.Mission) dateKey { return dateKey{Date: m.CreatedAt, ID: m.ID} },
+	item: func(k dateKey) models.Mission { return models.Mission{ID: k.ID, CreatedAt: k.Date} },
+}
+
+// ListMissionsAdmin godoc
+// @Summary Lista todas las misiones para administración
+// @Description Retorna una página de las misiones, incluidas las archivadas, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.Mission]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las misiones"
+// @Router /admin/missions [get]
+func (s *Server) ListMissionsAdmin(c *gin.Context) {
+	page, ok := parsePage(c, listSpec(models.Mission{}))
+	if !ok {
+		return
+	}
+	query, ok := missionOrder.query(c, page)
+	if !ok {
+		return
+	}
+	missions, err := s.missions.ListMissions(database.MissionList{
+		IncludeArchived: true,
+		After:           query.After,
+		Limit:           query.Limit,
+	})
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las misiones", err)
+		return
+	}
+	missions, next := missionOrder.next(c, page, missions)
+	respondPage(s, c, page, missions, next)
+}
+
+// UpdateMission godoc
+// @Summary Reemplaza una misión
+// @Description Reemplaza el título, la descripción, los pasos, el cuestionario y los prerrequisitos de una misión existente. Si no se envían pasos, cuestionario o prerrequisitos, la misión queda sin ellos. Los prerrequisitos no pueden formar un ciclo.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param mission body UpdateMissionRequest true "Nuevos datos de la misión"
+// @Success 200 {object} models.Mission
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo actualizar la misión"
+// @Router /admin/missions/{id} [put]
+func (s *Server) UpdateMission(c *gin.Context) {
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input UpdateMissionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	steps, err := buildMissionSteps(input.Steps)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if err := validateXP(input.XPReward, input.DifficultyMultiplier); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if err := validateClassification(input.Difficulty, input.GradeLevel, input.EstimatedMinutes); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	category := normalizeCategory(input.Category)
+	prerequisites, err := parsePrerequisites(input.Prerequisites)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if !s.checkPrerequisites(c, models.Mission{ID: missionObjID, Prerequisites: prerequisites}, "No se pudo actualizar la misión") {
+		return
+	}
+
+	update := database.MissionUpdate{
+		Title:                &input.Title,
+		Description:          &input.Description,
+		Steps:                &steps,
+		Prerequisites:        &prerequisites,
+		Category:             &category,
+		Tags:                 &tags,
+		Difficulty:           &input.Difficulty,
+		GradeLevel:           &input.GradeLevel,
+		EstimatedMinutes:     &input.EstimatedMinutes,
+		RemoveQuiz:           input.Quiz == nil,
+		XPReward:             &input.XPReward,
+		DifficultyMultiplier: &input.DifficultyMultiplier,
+	}
+	if input.Quiz != nil {
+		if update.Quiz, err = buildQuiz(*input.Quiz); err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+	}
+
+	s.applyMissionUpdate(c, missionObjID, update)
+}
+
+// PatchMission godoc
+// @Summary Actualiza parcialmente una misión
+// @Description Modifica solo los campos enviados. Con "archived" se archiva o restaura la misión; "steps", "quiz" y "prerequisites" reemplazan la lista completa de pasos, el cuestionario y los prerrequisitos, que no pueden formar un ciclo.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param mission body PatchMissionRequest true "Campos a modificar"
+// @Success 200 {object} models.Mission
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo actualizar la misión"
+// @Router /admin/missions/{id} [patch]
+func (s *Server) PatchMission(c *gin.Context) {
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input PatchMissionRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if input.Title == nil && input.Description == nil && input.Archived == nil && input.Steps == nil && input.Quiz == nil &&
+		input.Prerequisites == nil && input.XPReward == nil && input.DifficultyMultiplier == nil &&
+		input.Category == nil && input.Tags == nil && input.Difficulty == nil && input.GradeLevel == nil && input.EstimatedMinutes == nil {
+		fail(c, apierror.Invalid(apierror.Field("", apierror.NoChanges)))
+		return
+	}
+	if input.Title != nil && strings.TrimSpace(*input.Title) == "" {
+		fail(c, apierror.Invalid(apierror.Field("title", apierror.Required)))
+		return
+	}
+	if input.Description != nil && strings.TrimSpace(*input.Description) == "" {
+		fail(c, apierror.Invalid(apierror.Field("description", apierror.Required)))
+		return
+	}
+
+	xpReward, multiplier := 0, 0.0
+	if input.XPReward != nil {
+		xpReward = *input.XPReward
+	}
+	if input.DifficultyMultiplier != nil {
+		multiplier = *input.DifficultyMultiplier
+	}
+	if err := validateXP(xpReward, multiplier); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	difficulty, gradeLevel, minutes := 0, 0, 0
+	if input.Difficulty != nil {
+		difficulty = *input.Difficulty
+	}
+	if input.GradeLevel != nil {
+		gradeLevel = *input.GradeLevel
+	}
+	if input.EstimatedMinutes != nil {
+		minutes = *input.EstimatedMinutes
+	}
+	if err := validateClassification(difficulty, gradeLevel, minutes); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	update := database.MissionUpdate{
+		Title:                input.Title,
+		Description:          input.Description,
+		Archived:             input.Archived,
+		XPReward:             input.XPReward,
+		DifficultyMultiplier: input.DifficultyMultiplier,
+		Difficulty:           input.Difficulty,
+		GradeLevel:           input.GradeLevel,
+		EstimatedMinutes:     input.EstimatedMinutes,
+	}
+	if input.Category != nil {
+		category := normalizeCategory(*input.Category)
+		update.Category = &category
+	}
+	if input.Tags != nil {
+		tags, err := normalizeTags(*input.Tags)
+		if err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+		update.Tags = &tags
+	}
+	if input.Steps != nil {
+		steps, err := buildMissionSteps(*input.Steps)
+		if err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+		update.Steps = &steps
+	}
+	if input.Quiz != nil {
+		quiz, err := buildQuiz(*input.Quiz)
+		if err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+		update.Quiz = quiz
+	}
+	if input.Prerequisites != nil {
+		prerequisites, err := parsePrerequisites(*input.Prerequisites)
+		if err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+		if !s.checkPrerequisites(c, models.Mission{ID: missionObjID, Prerequisites: prerequisites}, "No se pudo actualizar la misión") {
+			return
+		}
+		update.Prerequisites = &prerequisites
+	}
+
+	s.applyMissionUpdate(c, missionObjID, update)
+}
+
+func (s *Server) applyMissionUpdate(c *gin.Context, missionObjID primitive.ObjectID, update database.MissionUpdate) {
+	mission, err := s.missions.UpdateMission(missionObjID, update, s.now())
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "No se pudo actualizar la misión", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, mission)
+}
+
+// DeleteMission godoc
+// @Summary Elimina una misión
+// @Description Elimina definitivamente una misión que ningún usuario ha iniciado. Si ya tiene progreso registrado se debe archivar en su lugar.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Success 200 {object} GenericResponse "Misión eliminada"
+// @Failure 400 {object} apierror.Response "ID de misión inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión tiene progreso registrado"
+// @Failure 500 {object} apierror.Response "No se pudo eliminar la misión"
+// @Router /admin/missions/{id} [delete]
+func (s *Server) DeleteMission(c *gin.Context) {
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return
+	}
+
+	inUse, err := s.progress.HasMissionProgress(missionObjID)
+	if err != nil {
+		s.internalError(c, "No se pudo eliminar la misión", err)
+		return
+	}
+	if inUse {
+		fail(c, apierror.New(apierror.MissionInUse))
+		return
+	}
+
+	if err := s.missions.DeleteMission(missionObjID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "No se pudo eliminar la misión", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Misión eliminada"})
+}
+
+// missionIDParam convierte el parámetro :id de la ruta a ObjectID. Si no es
+// válido responde 400 y devuelve false.
+func missionIDParam(c *gin.Context) (primitive.ObjectID, bool) {
+	objID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return primitive.NilObjectID, false
+	}
+	return objID, true
+}
+
+// buildMissionSteps valida los pasos recibidos y los convierte en pasos de la
+// misión, conservando el orden en que se enviaron.
+func buildMissionSteps(inputs []MissionStepInput) ([]models.MissionStep, error) {
+	steps := make([]models.MissionStep, 0, len(inputs))
+	seen := make(map[primitive.ObjectID]bool, len(inputs))
+	for i, input := range inputs {
+		title := strings.TrimSpace(input.Title)
+		if title == "" {
+			return nil, apierror.Field(fmt.Sprintf("steps[%d].title", i), apierror.Required)
+		}
+
+		stepID := primitive.NewObjectID()
+		if input.ID != "" {
+			id, err := primitive.ObjectIDFromHex(input.ID)
+			if err != nil {
+				return nil, apierror.Field(fmt.Sprintf("steps[%d].id", i), apierror.InvalidObjectID)
+			}
+			stepID = id
+		}
+		if seen[stepID] {
+			return nil, apierror.Field(fmt.Sprintf("steps[%d].id", i), apierror.Duplicate)
+		}
+		seen[stepID] = true
+
+		steps = append(steps, models.MissionStep{
+			ID:          stepID,
+			Title:       title,
+			Description: strings.TrimSpace(input.Description),
+			Optional:    input.Optional,
+		})
+	}
+	return steps, nil
+}
+
+// validateClassification comprueba los límites de la dificultad, el grado y la
+// duración estimada; en cero no se indicaron.
+func validateClassification(difficulty, gradeLevel, minutes int) error {
+	if difficulty < 0 || difficulty > models.MaxDifficulty {
+		return apierror.Field("difficulty", apierror.OutOfRange).With("min", 1).With("max", models.MaxDifficulty)
+	}
+	if gradeLevel < 0 || gradeLevel > models.MaxGradeLevel {
+		return apierror.Field("grade_level", apierror.OutOfRange).With("min", 1).With("max", models.MaxGradeLevel)
+	}
+	if minutes < 0 {
+		return apierror.Field("estimated_minutes", apierror.TooSmall).With("min", 0)
+	}
+	return nil
+}
+
+// normalizeCategory guarda las categorías en minúsculas, para que el filtro
+// no distinga cómo se escribieron.
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// normalizeTags pasa las etiquetas a minúsculas, sin espacios sobrantes ni
+// repetidas, conservando su orden.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for i, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, apierror.Field(fmt.Sprintf("tags[%d]", i), apierror.Required)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > models.MaxTags {
+		return nil, apierror.Field("tags", apierror.TooMany).With("max", models.MaxTags)
+	}
+	return normalized, nil
+}
+
+// parsePrerequisites convierte los IDs de los prerrequisitos recibidos,
+// conservando su orden y rechazando los repetidos.
+func parsePrerequisites(ids []string) ([]primitive.ObjectID, error) {
+	prerequisites := make([]primitive.ObjectID, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for i, hex := range ids {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, apierror.Field(fmt.Sprintf("prerequisites[%d]", i), apierror.InvalidObjectID)
+		}
+		if seen[id] {
+			return nil, apierror.Field(fmt.Sprintf("prerequisites[%d]", i), apierror.Duplicate)
+		}
+		seen[id] = true
+		prerequisites = append(prerequisites, id)
+	}
+	return prerequisites, nil
+}
+
+// checkPrerequisites comprueba que los prerrequisitos de mission existan en el
+// catálogo de la organización y que, con ellos, el grafo de prerrequisitos no
+// tenga ciclos. Si mission ya está en el catálogo se toma de ahí, con los
+// prerrequisitos nuevos. Una misión global solo puede depender de misiones
+// globales, porque las demás organizaciones no verían sus prerrequisitos.
+// Si algo falla responde y devuelve false.
+func (s *Server) checkPrerequisites(c *gin.Context, mission models.Mission, message string) bool {
+	// Quitar prerrequisitos no puede cerrar un ciclo
+	if len(mission.Prerequisites) == 0 {
+		return true
+	}
+	catalog, err := s.missions.GetAllMissions(true)
+	if err != nil {
+		s.internalError(c, message, err)
+		return false
+	}
+
+	found := false
+	for i := range catalog {
+		if catalog[i].ID == mission.ID {
+			catalog[i].Prerequisites = mission.Prerequisites
+			mission, found = catalog[i], true
+		}
+	}
+	if !found {
+		catalog = append(catalog, mission)
+	}
+	byID := make(map[primitive.ObjectID]models.Mission, len(catalog))
+	for _, m := range catalog {
+		byID[m.ID] = m
+	}
+
+	for i, id := range mission.Prerequisites {
+		field := fmt.Sprintf("prerequisites[%d]", i)
+		prerequisite, ok := byID[id]
+		if !ok {
+			fail(c, apierror.Invalid(apierror.Field(field, apierror.NotFound)))
+			return false
+		}
+		if mission.Global && !prerequisite.Global {
+			fail(c, apierror.Invalid(apierror.Field(field, apierror.GlobalPrerequisite)))
+			return false
+		}
+	}
+
+	if cycle := models.PrerequisiteCycle(catalog); cycle != nil {
+		titles := make([]string, len(cycle))
+		for i, id := range cycle {
+			titles[i] = byID[id].Title
+		}
+		fail(c, apierror.Invalid(apierror.Field("prerequisites", apierror.PrerequisiteCycle).
+			With("cycle", strings.Join(titles, " → ")).
+			With("missionIds", cycle)))
+		return false
+	}
+	return true
+}
+
+// validateXP comprueba que la recompensa y el multiplicador no sean negativos.
+func validateXP(xpReward int, multiplier float64) error {
+	if xpReward < 0 {
+		return apierror.Field("xp_reward", apierror.TooSmall).With("min", 0)
+	}
+	if multiplier < 0 {
+		return apierror.Field("difficulty_multiplier", apierror.TooSmall).With("min", 0)
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateAssignmentRequest es el cuerpo de la solicitud para asignar una misión.
+// Se indica exactamente uno de classroom_id o student_id.
+// @Description Misión asignada a una clase o a un estudiante
+type CreateAssignmentRequest struct {
+	MissionID   string `json:"mission_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b6"`
+	ClassroomID string `json:"classroom_id" example:"60a7b97f5e41c42e7c2e30b8"`
+	StudentID   string `json:"student_id" example:""`
+	// OpensAt es opcional; por defecto la asignación se abre de inmediato
+	OpensAt *time.Time `json:"opens_at" example:"2025-03-10T08:00:00Z"`
+	DueAt   time.Time  `json:"due_at" binding:"required" example:"2025-03-17T23:59:00Z"`
+}
+
+// StudentAssignment es una misión asignada vista por el estudiante, con su avance.
+type StudentAssignment struct {
+	models.Assignment
+	MissionTitle string `json:"missionTitle" example:"Explorar Marte"`
+	models.AssignmentState
+}
+
+// AssignmentStudentStatus es el avance de un estudiante en una asignación.
+type AssignmentStudentStatus struct {
+	StudentID   primitive.ObjectID `json:"studentId"`
+	Username    string             `json:"username" example:"ana"`
+	DisplayName string             `json:"displayName,omitempty" example:"Ana"`
+	models.AssignmentState
+}
+
+// AssignmentSummary cuenta a los estudiantes de la asignación según su avance.
+type AssignmentSummary struct {
+	Students   int `json:"students" example:"24"`
+	NotStarted int `json:"notStarted" example:"5"`
+	InProgress int `json:"inProgress" example:"3"`
+	Completed  int `json:"completed" example:"16"`
+	OnTime     int `json:"onTime" example:"14"`
+	// Late cuenta las completadas fuera de plazo y las vencidas sin completar
+	Late int `json:"late" example:"4"`
+}
+
+// AssignmentReport es el reporte del docente sobre una asignación.
+type AssignmentReport struct {
+	Assignment models.Assignment         `json:"assignment"`
+	Summary    AssignmentSummary         `json:"summary"`
+	Students   []AssignmentStudentStatus `json:"students"`
+}
+
+// openAssignments devuelve una página de las asignaciones ya abiertas del
+// usuario: las dirigidas a él y las de sus clases.
+func (s *Server) openAssignments(userID primitive.ObjectID, query database.ListQuery[models.Assignment]) ([]models.Assignment, error) {
+	classrooms, err := s.classrooms.GetStudentClassrooms(userID, database.ListQuery[models.Classroom]{})
+	if err != nil {
+		return nil, err
+	}
+	classroomIDs := make([]primitive.ObjectID, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		classroomIDs = append(classroomIDs, classroom.ID)
+	}
+	return s.assignments.GetStudentAssignments(userID, classroomIDs, s.now(), query)
+}
+
+// catalogAssignments decide si el catálogo se limita a las misiones con una
+// asignación abierta para el usuario y, en ese caso, devuelve sus IDs.
+// assigned=true o assigned=false lo deciden; sin el parámetro se limita para
+// los estudiantes que tienen alguna. Si algo falla responde y devuelve false
+// en ok.
+func (s *Server) catalogAssignments(c *gin.Context) (ids []primitive.ObjectID, filter, ok bool) {
+	raw, explicit := c.GetQuery("assigned")
+	if explicit {
+		var err error
+		if filter, err = strconv.ParseBool(raw); err != nil {
+			fail(c, apierror.InvalidQuery(apierror.Field("assigned", apierror.InvalidType).With("expected", "boolean")))
+			return nil, false, false
+		}
+		if !filter {
+			return nil, false, true
+		}
+	} else if currentRole(c) != models.RoleStudent {
+		return nil, false, true
+	}
+
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return nil, false, false
+	}
+	assignments, err := s.openAssignments(userObjID, database.ListQuery[models.Assignment]{})
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las misiones", err)
+		return nil, false, false
+	}
+	if !explicit && len(assignments) == 0 {
+		return nil, false, true
+	}
+	ids = make([]primitive.ObjectID, 0, len(assignments))
+	for _, a := range assignments {
+		ids = append(ids, a.MissionID)
+	}
+	return ids, true, true
+}
+
+// assignmentStudents devuelve los IDs de los estudiantes a quienes va dirigida
+// la asignación; para una clase, los inscritos actualmente.
+func (s *Server) assignmentStudents(assignment models.Assignment) ([]primitive.ObjectID, error) {
+	if !assignment.StudentID.IsZero() {
+		return []primitive.ObjectID{assignment.StudentID}, nil
+	}
+	classroom, err := s.classrooms.GetClassroomByID(assignment.ClassroomID)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return classroom.StudentIDs, nil
+}
+
+// teachesStudent indica si el estudiante está inscrito en alguna clase del docente.
+func (s *Server) teachesStudent(teacherID, studentID primitive.ObjectID) (bool, error) {
+	classrooms, err := s.classrooms.GetTeacherClassrooms(teacherID, database.ListQuery[models.Classroom]{})
+	if err != nil {
+		return false, err
+	}
+	for _, classroom := range classrooms {
+		if classroom.HasStudent(studentID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreateAssignment godoc
+// @Summary Asigna una misión
+// @Description Asigna una misión a una clase del docente o a un estudiante inscrito en alguna de sus clases, con fecha de apertura y de entrega. Los administradores pueden asignar a cualquier clase o estudiante.
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param assignment body CreateAssignmentRequest true "Datos de la asignación"
+// @Success 201 {object} models.Assignment
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 404 {object} apierror.Response "Misión, clase o estudiante no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudo crear la asignación"
+// @Router /assignments [post]
+func (s *Server) CreateAssignment(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var input CreateAssignmentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if input.ClassroomID == "" && input.StudentID == "" {
+		fail(c, apierror.Invalid(apierror.Field("classroom_id", apierror.Required)))
+		return
+	}
+	if input.ClassroomID != "" && input.StudentID != "" {
+		fail(c, apierror.Invalid(apierror.Field("classroom_id", apierror.MutuallyExclusive).With("other", "student_id")))
+		return
+	}
+	missionID, err := primitive.ObjectIDFromHex(input.MissionID)
+	if err != nil {
+		fail(c, apierror.Invalid(apierror.Field("mission_id", apierror.InvalidObjectID)))
+		return
+	}
+
+	assignment := models.Assignment{
+		ID:        primitive.NewObjectID(),
+		MissionID: missionID,
+		TeacherID: userObjID,
+		OpensAt:   s.now(),
+		DueAt:     input.DueAt,
+		CreatedAt: s.now(),
+	}
+	if input.OpensAt != nil {
+		assignment.OpensAt = *input.OpensAt
+	}
+	if !assignment.DueAt.After(assignment.OpensAt) {
+		fail(c, apierror.Invalid(apierror.Field("due_at", apierror.MustBeAfter).With("other", "opens_at")))
+		return
+	}
+
+	mission, err := s.missions.GetMissionByID(missionID)
+	if err == nil && mission.Archived {
+		err = database.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "No se pudo crear la asignación", err)
+		}
+		return
+	}
+
+	if input.ClassroomID != "" {
+		classroomID, err := primitive.ObjectIDFromHex(input.ClassroomID)
+		if err != nil {
+			fail(c, apierror.Invalid(apierror.Field("classroom_id", apierror.InvalidObjectID)))
+			return
+		}
+		if _, ok := s.ownedClassroom(c, classroomID); !ok {
+			return
+		}
+		assignment.ClassroomID = classroomID
+	} else {
+		studentID, err := primitive.ObjectIDFromHex(input.StudentID)
+		if err != nil {
+			fail(c, apierror.Invalid(apierror.Field("student_id", apierror.InvalidObjectID)))
+			return
+		}
+		var allowed bool
+		if currentRole(c) == models.RoleAdmin {
+			_, err = s.users.FindUserByID(studentID)
+			allowed = err == nil
+			if errors.Is(err, database.ErrNotFound) {
+				err = nil
+			}
+		} else {
+			allowed, err = s.teachesStudent(userObjID, studentID)
+		}
+		if err != nil {
+			s.internalError(c, "No se pudo crear la asignación", err)
+			return
+		}
+		if !allowed {
+			fail(c, apierror.New(apierror.StudentNotEnrolled))
+			return
+		}
+		assignment.StudentID = studentID
+	}
+
+	if err := s.assignments.InsertAssignment(assignment); err != nil {
+		s.internalError(c, "No se pudo crear la asignación", err)
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// assignmentOrder es el orden de las listas de asignaciones: por fecha de
+// entrega y luego por ID.
+var assignmentOrder = listOrder[models.Assignment, dateKey]{
+	key:  func(a models.Assignment) dateKey { return dateKey{Date: a.DueAt, ID: a.ID} },
+	item: func(k dateKey) models.Assignment { return models.Assignment{ID: k.ID, DueAt: k.Date} },
+}
+
+// ListAssignments godoc
+// @Summary Lista las asignaciones del docente
+// @Description Devuelve una página de las misiones asignadas por el docente autenticado, ordenadas por fecha de entrega. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Assignments
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.Assignment]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las asignaciones"
+// @Router /assignments [get]
+func (s *Server) ListAssignments(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(models.Assignment{}))
+	if !ok {
+		return
+	}
+	query, ok := assignmentOrder.query(c, page)
+	if !ok {
+		return
+	}
+	assignments, err := s.assignments.GetTeacherAssignments(userObjID, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las asignaciones", err)
+		return
+	}
+	assignments, next := assignmentOrder.next(c, page, assignments)
+	respondPage(s, c, page, assignments, next)
+}
+
+// GetAssignmentReport godoc
+// @Summary Obtiene el reporte de una asignación
+// @Description Devuelve el avance de cada estudiante en la misión asignada: no iniciada, en curso o completada, y si la completó a tiempo según la fecha en que terminó la misión. Con status=not_started lista solo a quienes no la han empezado. Solo lo ve el docente que la creó o un administrador.
+// @Tags Assignments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la asignación"
+// @Param status query string false "Filtra a los estudiantes: not_started, in_progress o completed"
+// @Success 200 {object} AssignmentReport
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 404 {object} apierror.Response "Asignación no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo obtener el reporte"
+// @Router /assignments/{id}/report [get]
+func (s *Server) GetAssignmentReport(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	assignmentID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return
+	}
+	status := models.AssignmentStatus(c.Query("status"))
+	switch status {
+	case "", models.AssignmentNotStarted, models.AssignmentInProgress, models.AssignmentCompleted:
+	default:
+		fail(c, apierror.InvalidQuery(apierror.Field("status", apierror.UnknownValue).
+			With("allowed", strings.Join([]string{string(models.AssignmentNotStarted), string(models.AssignmentInProgress), string(models.AssignmentCompleted)}, ", "))))
+		return
+	}
+
+	assignment, err := s.assignments.GetAssignmentByID(assignmentID)
+	if err == nil && assignment.TeacherID != userObjID && currentRole(c) != models.RoleAdmin {
+		err = database.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.AssignmentNotFound))
+		} else {
+			s.internalError(c, "No se pudo obtener el reporte", err)
+		}
+		return
+	}
+
+	studentIDs, err := s.assignmentStudents(*assignment)
+	if err != nil {
+		s.internalError(c, "No se pudo obtener el reporte", err)
+		return
+	}
+	now := s.now()
+	report := AssignmentReport{Assignment: *assignment, Students: []AssignmentStudentStatus{}}
+	for _, studentID := range studentIDs {
+		student, err := s.users.FindUserByID(studentID)
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.internalError(c, "No se pudo obtener el reporte", err)
+			return
+		}
+		progress, err := s.progress.FindMissionProgress(studentID, assignment.MissionID)
+		if errors.Is(err, database.ErrNotFound) {
+			progress, err = nil, nil
+		}
+		if err != nil {
+			s.internalError(c, "No se pudo obtener el reporte", err)
+			return
+		}
+
+		state := assignment.State(progress, now)
+		report.Summary.Students++
+		switch state.Status {
+		case models.AssignmentNotStarted:
+			report.Summary.NotStarted++
+		case models.AssignmentInProgress:
+			report.Summary.InProgress++
+		case models.AssignmentCompleted:
+			report.Summary.Completed++
+		}
+		if state.OnTime {
+			report.Summary.OnTime++
+		}
+		if state.Late {
+			report.Summary.Late++
+		}
+		if status == "" || state.Status == status {
+			report.Students = append(report.Students, AssignmentStudentStatus{
+				StudentID:       student.ID,
+				Username:        student.Username,
+				DisplayName:     student.DisplayName,
+				AssignmentState: state,
+			})
+		}
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GetMyAssignments godoc
+// @Summary Lista las misiones asignadas al usuario
+// @Description Devuelve una página de las asignaciones abiertas del usuario autenticado, propias o de sus clases, ordenadas por fecha de entrega, con su avance y si la completó a tiempo. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Assignments
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[StudentAssignment]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las asignaciones"
+// @Router /me/assignments [get]
+func (s *Server) GetMyAssignments(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(StudentAssignment{}))
+	if !ok {
+		return
+	}
+	query, ok := assignmentOrder.query(c, page)
+	if !ok {
+		return
+	}
+	assignments, err := s.openAssignments(userObjID, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las asignaciones", err)
+		return
+	}
+	assignments, next := assignmentOrder.next(c, page, assignments)
+
+	missionIDs := make([]primitive.ObjectID, 0, len(assignments))
+	for _, a := range assignments {
+		missionIDs = append(missionIDs, a.MissionID)
+	}
+	missions, err := s.missions.ListMissions(database.MissionList{IncludeArchived: true, IDs: missionIDs})
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las asignaciones", err)
+		return
+	}
+	locale := s.locale(c)
+	titles := make(map[primitive.ObjectID]string, len(missions))
+	for _, m := range missions {
+		titles[m.ID] = m.Localize(locale).Title
+	}
+	progress, err := s.progress.GetProgressForMissions(userObjID, missionIDs)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las asignaciones", err)
+		return
+	}
+	byMission := make(map[primitive.ObjectID]*models.MissionProgress, len(progress))
+	for i := range progress {
+		byMission[progress[i].MissionID] = &progress[i]
+	}
+
+	now := s.now()
+	result := make([]StudentAssignment, 0, len(assignments))
+	for _, a := range assignments {
+		result = append(result, StudentAssignment{
+			Assignment:      a,
+			MissionTitle:    titles[a.MissionID],
+			AssignmentState: a.State(byMission[a.MissionID], now),
+		})
+	}
+	respondPage(s, c, page, result, next)
+}
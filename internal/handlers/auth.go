@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 
+	"explorax-backend/internal/apierror"
 	"explorax-backend/internal/database"
+	"explorax-backend/internal/middleware"
 	"explorax-backend/internal/models"
 	"explorax-backend/internal/utils"
 )
@@ -18,6 +22,11 @@ type RegisterRequest struct {
 	Username string `json:"username" binding:"required" example:"usuario123"`
 	Email    string `json:"email" binding:"required,email" example:"usuario@email.com"`
 	Password string `json:"password" binding:"required" example:"123456"`
+	// Timezone es una zona horaria IANA opcional; por defecto UTC
+	Timezone string `json:"timezone" example:"America/Guatemala"`
+	// BirthDate (AAAA-MM-DD) es opcional; sin ella el usuario se trata como
+	// menor y aparece con un seudónimo en los rankings
+	BirthDate string `json:"birth_date" example:"2012-05-01"`
 }
 
 // LoginRequest representa los datos esperados en el login de usuario.
@@ -27,28 +36,62 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"123456"`
 }
 
+// RefreshRequest representa los datos esperados para renovar el token de acceso.
+// @Description Estructura para renovar tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"b3BhcXVlLXJlZnJlc2gtdG9rZW4"`
+}
+
+// LogoutRequest representa los datos opcionales del cierre de sesión.
+// @Description Estructura para cerrar sesión
+type LogoutRequest struct {
+	All bool `json:"all" example:"false"`
+}
+
+// TokenResponse contiene los tokens emitidos al iniciar sesión o renovarla.
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
 // Register godoc
 // @Summary Registro de usuario
-// @Description Permite registrar un nuevo usuario en la plataforma.
+// @Description Permite registrar un nuevo usuario en la organización indicada en X-Tenant-ID, o en la organización por defecto si no se envía.
 // @Tags Auth
 // @Accept json
 // @Produce json
+// @Param X-Tenant-ID header string false "Organización del usuario"
 // @Param user body RegisterRequest true "Datos del usuario"
 // @Success 201 {object} map[string]string "Usuario creado exitosamente"
-// @Failure 400 {object} map[string]string "Datos inválidos"
-// @Failure 500 {object} map[string]string "Error interno del servidor"
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 404 {object} apierror.Response "La organización no existe"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
 // @Router /auth/register [post]
-func Register(c *gin.Context) {
+func (s *Server) Register(c *gin.Context) {
 	var input RegisterRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if !validTimezone(input.Timezone) {
+		fail(c, apierror.Invalid(apierror.Field("timezone", apierror.InvalidTimezone)))
 		return
 	}
+	var birthDate *time.Time
+	if input.BirthDate != "" {
+		parsed, err := parseBirthDate(input.BirthDate, s.now())
+		if err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+		birthDate = &parsed
+	}
 
 	// Encriptar la contraseña
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al encriptar la contraseña"})
+		s.internalError(c, "Error al encriptar la contraseña", err)
 		return
 	}
 
@@ -56,12 +99,15 @@ func Register(c *gin.Context) {
 		Username:     input.Username,
 		Email:        input.Email,
 		PasswordHash: string(hashedPassword),
-		CreatedAt:    time.Now(),
+		Role:         models.RoleStudent,
+		Timezone:     input.Timezone,
+		BirthDate:    birthDate,
+		CreatedAt:    s.now(),
 	}
 
 	// Insertar el usuario en MongoDB
-	if err := database.InsertUser(user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al registrar el usuario"})
+	if err := s.users.InsertUser(user); err != nil {
+		s.internalError(c, "Error al registrar el usuario", err)
 		return
 	}
 
@@ -70,42 +116,198 @@ func Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Inicia sesión de usuario
-// @Description Autentica a un usuario y devuelve un token JWT.
+// @Description Autentica a un usuario de la organización indicada en X-Tenant-ID, abre una sesión y devuelve un token de acceso JWT de corta duración, que incluye la organización, junto con un refresh token.
 // @Tags Auth
 // @Accept  json
 // @Produce  json
+// @Param X-Tenant-ID header string false "Organización del usuario"
 // @Param credentials body LoginRequest true "Credenciales de usuario (email y password)"
-// @Success 200 {object} map[string]string "Token JWT generado exitosamente"
-// @Failure 400 {object} map[string]string "Datos inválidos"
-// @Failure 401 {object} map[string]string "Credenciales incorrectas"
-// @Failure 500 {object} map[string]string "Error interno"
+// @Success 200 {object} TokenResponse "Tokens generados exitosamente"
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 401 {object} apierror.Response "Credenciales incorrectas"
+// @Failure 500 {object} apierror.Response "Error interno"
 // @Router /auth/login [post]
-func Login(c *gin.Context) {
+func (s *Server) Login(c *gin.Context) {
 	var input LoginRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		fail(c, apierror.Invalid(err))
 		return
 	}
 
 	// Buscar usuario por email
-	user, err := database.FindUserByEmail(input.Email)
+	user, err := s.users.FindUserByEmail(input.Email)
 	if err != nil || user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no encontrado"})
+		fail(c, apierror.New(apierror.InvalidCredentials))
 		return
 	}
 
 	// Comparar contraseña
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(input.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Contraseña incorrecta"})
+		fail(c, apierror.New(apierror.InvalidCredentials))
+		return
+	}
+
+	// Crear la sesión y generar los tokens
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		s.internalError(c, "Error al generar token", err)
+		return
+	}
+	now := s.now()
+	session := models.Session{
+		ID:               primitive.NewObjectID(),
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashRefreshToken(refreshToken),
+		UserAgent:        c.Request.UserAgent(),
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL),
+	}
+	if err := s.sessions.InsertSession(session); err != nil {
+		s.internalError(c, "Error al crear la sesión", err)
+		return
+	}
+
+	token, err := s.tokens.SignAccessToken(utils.AccessClaims{
+		UserID:    user.ID.Hex(),
+		Role:      string(user.EffectiveRole()),
+		SessionID: session.ID.Hex(),
+		TenantID:  middleware.CurrentTenant(c),
+	})
+	if err != nil {
+		s.internalError(c, "Error al generar token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	})
+}
+
+// Refresh godoc
+// @Summary Renueva el token de acceso
+// @Description Intercambia un refresh token vigente por un nuevo token de acceso y un nuevo refresh token. El refresh token usado deja de ser válido; reutilizarlo revoca la sesión completa.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param X-Tenant-ID header string false "Organización del usuario; la misma que al iniciar sesión"
+// @Param body body RefreshRequest true "Refresh token"
+// @Success 200 {object} TokenResponse "Tokens renovados"
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 401 {object} apierror.Response "Refresh token inválido, expirado o revocado"
+// @Failure 500 {object} apierror.Response "Error interno"
+// @Router /auth/refresh [post]
+func (s *Server) Refresh(c *gin.Context) {
+	var input RefreshRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	hash := utils.HashRefreshToken(input.RefreshToken)
+	session, err := s.sessions.FindSessionByRefreshHash(hash)
+	if err != nil {
+		fail(c, apierror.New(apierror.RefreshTokenInvalid))
+		return
+	}
+
+	// Un token ya rotado que vuelve a presentarse indica que fue robado:
+	// se revoca la sesión para cerrar tanto al atacante como al dueño.
+	if session.RefreshTokenHash != hash {
+		if err := s.sessions.RevokeSession(session.ID, s.now()); err != nil {
+			s.internalError(c, "Error al revocar la sesión", err)
+			return
+		}
+		fail(c, apierror.New(apierror.RefreshTokenReused))
+		return
+	}
+
+	now := s.now()
+	if !session.Active(now) {
+		fail(c, apierror.New(apierror.SessionExpired))
+		return
+	}
+
+	user, err := s.users.FindUserByID(session.UserID)
+	if err != nil {
+		fail(c, apierror.New(apierror.RefreshTokenInvalid))
+		return
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		s.internalError(c, "Error al generar token", err)
+		return
+	}
+	newHash := utils.HashRefreshToken(refreshToken)
+	if err := s.sessions.RotateSession(session.ID, hash, newHash, now, now.Add(utils.RefreshTokenTTL)); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.RefreshTokenInvalid))
+		} else {
+			s.internalError(c, "Error al renovar la sesión", err)
+		}
+		return
+	}
+
+	token, err := s.tokens.SignAccessToken(utils.AccessClaims{
+		UserID:    user.ID.Hex(),
+		Role:      string(user.EffectiveRole()),
+		SessionID: session.ID.Hex(),
+		TenantID:  middleware.CurrentTenant(c),
+	})
+	if err != nil {
+		s.internalError(c, "Error al generar token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	})
+}
+
+// Logout godoc
+// @Summary Cierra la sesión
+// @Description Revoca la sesión del token usado. Con "all" en true revoca todas las sesiones del usuario, por ejemplo ante la pérdida de un dispositivo.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body LogoutRequest false "Alcance del cierre de sesión"
+// @Success 200 {object} map[string]string "Sesión cerrada"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "Error interno"
+// @Router /auth/logout [post]
+func (s *Server) Logout(c *gin.Context) {
+	var input LogoutRequest
+	// El cuerpo es opcional; sin él se cierra solo la sesión actual
+	_ = c.ShouldBindJSON(&input)
+
+	if input.All {
+		userObjID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+		if err := s.sessions.RevokeUserSessions(userObjID, s.now()); err != nil {
+			s.internalError(c, "Error al cerrar las sesiones", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Todas las sesiones fueron cerradas"})
 		return
 	}
 
-	// Generar token JWT
-	token, err := utils.GenerateJWT(user.ID.Hex())
+	sessionID, err := primitive.ObjectIDFromHex(c.GetString("session_id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al generar token"})
+		fail(c, apierror.New(apierror.Unauthenticated))
+		return
+	}
+	if err := s.sessions.RevokeSession(sessionID, s.now()); err != nil {
+		s.internalError(c, "Error al cerrar la sesión", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+	c.JSON(http.StatusOK, gin.H{"message": "Sesión cerrada"})
 }
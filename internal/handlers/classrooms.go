@@ -0,0 +1,563 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxClassroomNameLength = 100
+	// joinCodeAttempts es cuántas veces se genera un código nuevo si el
+	// anterior ya estaba en uso.
+	joinCodeAttempts = 5
+)
+
+// CreateClassroomRequest es el cuerpo de la solicitud para crear una clase.
+// @Description Datos de una clase nueva
+type CreateClassroomRequest struct {
+	Name string `json:"name" binding:"required" example:"Ciencias 5.º B"`
+}
+
+// JoinClassroomRequest es el cuerpo de la solicitud para inscribirse en una clase.
+// @Description Código de la clase
+type JoinClassroomRequest struct {
+	JoinCode string `json:"join_code" binding:"required" example:"K7QX-M2PA"`
+}
+
+// StudentClassroom es una clase vista por un estudiante inscrito: no incluye
+// el código ni la lista de compañeros.
+type StudentClassroom struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name" example:"Ciencias 5.º B"`
+}
+
+// StudentProgressSummary resume el avance de un estudiante de la clase.
+type StudentProgressSummary struct {
+	StudentID   primitive.ObjectID `json:"studentId"`
+	Username    string             `json:"username" example:"ana"`
+	DisplayName string             `json:"displayName,omitempty" example:"Ana"`
+	UserStatistics
+	// ActiveCount cuenta las misiones iniciadas o en pausa
+	ActiveCount     int        `json:"activeCount" example:"1"`
+	LastCompletedAt *time.Time `json:"lastCompletedAt,omitempty"`
+}
+
+// ClassroomSummary agrega el avance de todos los estudiantes de la clase.
+type ClassroomSummary struct {
+	Students       int `json:"students" example:"24"`
+	TotalCompleted int `json:"totalCompleted" example:"96"`
+	ActiveCount    int `json:"activeCount" example:"7"`
+	// Los promedios son por estudiante, salvo AverageDuration, que es el
+	// promedio de todas las misiones completadas en la clase
+	AverageCompleted float64 `json:"averageCompleted" example:"4"`
+	AverageDuration  float64 `json:"averageDuration" example:"540000"`
+	AverageProgress  float64 `json:"averageProgress" example:"38.5"`
+	AverageXP        float64 `json:"averageXp" example:"420"`
+}
+
+// ClassroomDashboard es el tablero del docente: la clase, el resumen y el
+// avance de cada estudiante.
+type ClassroomDashboard struct {
+	Classroom models.Classroom         `json:"classroom"`
+	Summary   ClassroomSummary         `json:"summary"`
+	Students  []StudentProgressSummary `json:"students"`
+}
+
+// reportMissionsLimit es cuántas misiones activas y cuántas completadas trae
+// el reporte de un estudiante, las más recientes primero.
+const reportMissionsLimit = 20
+
+// StudentReport es el detalle del avance de un estudiante de la clase: sus
+// estadísticas y sus reportMissionsLimit misiones activas y completadas más
+// recientes.
+type StudentReport struct {
+	Student           StudentProgressSummary   `json:"student"`
+	ActiveMissions    []models.MissionProgress `json:"activeMissions"`
+	CompletedMissions []models.MissionProgress `json:"completedMissions"`
+}
+
+// studentSummary reúne las estadísticas del estudiante con la misma lógica que
+// /missions/statistics, sin cargar su progreso.
+func (s *Server) studentSummary(student models.User) (StudentProgressSummary, error) {
+	stats, err := s.userStatistics(student)
+	if err != nil {
+		return StudentProgressSummary{}, err
+	}
+	progress, err := s.progress.GetProgressSummary(student.ID)
+	if err != nil {
+		return StudentProgressSummary{}, err
+	}
+
+	summary := StudentProgressSummary{
+		StudentID:      student.ID,
+		Username:       student.Username,
+		DisplayName:    student.DisplayName,
+		UserStatistics: stats,
+		ActiveCount:    progress.Active,
+	}
+	if progress.Completed > 0 {
+		lastCompletedAt := progress.LastCompletedAt
+		summary.LastCompletedAt = &lastCompletedAt
+	}
+	return summary, nil
+}
+
+// studentReport suma al resumen del estudiante sus misiones activas y
+// completadas más recientes, en el orden por defecto de /missions/active y
+// /missions/completed.
+func (s *Server) studentReport(student models.User) (StudentReport, error) {
+	summary, err := s.studentSummary(student)
+	if err != nil {
+		return StudentReport{}, err
+	}
+	active, err := s.recentProgress(student.ID, database.SortByStartDate, models.ProgressStarted, models.ProgressPaused)
+	if err != nil {
+		return StudentReport{}, err
+	}
+	completed, err := s.recentProgress(student.ID, database.SortByEndDate, models.ProgressCompleted)
+	if err != nil {
+		return StudentReport{}, err
+	}
+	return StudentReport{Student: summary, ActiveMissions: active, CompletedMissions: completed}, nil
+}
+
+// recentProgress devuelve hasta reportMissionsLimit progresos del usuario en
+// los estados indicados, del más reciente al más antiguo según sort.
+func (s *Server) recentProgress(userID primitive.ObjectID, sort string, statuses ...models.ProgressStatus) ([]models.MissionProgress, error) {
+	items, err := s.progress.ListMissionProgress(database.ProgressQuery{
+		UserID:         userID,
+		Statuses:       statuses,
+		Sort:           sort,
+		SortDescending: true,
+		Limit:          reportMissionsLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	progress := make([]models.MissionProgress, len(items))
+	for i, item := range items {
+		progress[i] = item.Progress
+	}
+	return progress, nil
+}
+
+// summarizeClassroom calcula los totales y promedios de la clase.
+func summarizeClassroom(students []StudentProgressSummary) ClassroomSummary {
+	summary := ClassroomSummary{Students: len(students)}
+	if len(students) == 0 {
+		return summary
+	}
+	var durationSum, progressSum float64
+	var xpSum int
+	for _, st := range students {
+		summary.TotalCompleted += st.TotalCompleted
+		summary.ActiveCount += st.ActiveCount
+		durationSum += st.AverageDuration * float64(st.TotalCompleted)
+		progressSum += st.ProgressPercentage
+		xpSum += st.XP
+	}
+	n := float64(len(students))
+	summary.AverageCompleted = float64(summary.TotalCompleted) / n
+	summary.AverageProgress = progressSum / n
+	summary.AverageXP = float64(xpSum) / n
+	if summary.TotalCompleted > 0 {
+		summary.AverageDuration = durationSum / float64(summary.TotalCompleted)
+	}
+	return summary
+}
+
+// teacherClassroom carga la clase del parámetro id y comprueba que la dicte
+// el usuario autenticado; ver ownedClassroom.
+func (s *Server) teacherClassroom(c *gin.Context) (*models.Classroom, bool) {
+	classroomID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return nil, false
+	}
+	return s.ownedClassroom(c, classroomID)
+}
+
+// ownedClassroom carga la clase y comprueba que la dicte el usuario
+// autenticado; los administradores pueden ver todas. Las clases ajenas
+// responden 404 para no revelar que existen.
+func (s *Server) ownedClassroom(c *gin.Context, classroomID primitive.ObjectID) (*models.Classroom, bool) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	classroom, err := s.classrooms.GetClassroomByID(classroomID)
+	if err == nil && classroom.TeacherID != userObjID && currentRole(c) != models.RoleAdmin {
+		err = database.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.ClassroomNotFound))
+		} else {
+			s.internalError(c, "Error al obtener la clase", err)
+		}
+		return nil, false
+	}
+	return classroom, true
+}
+
+// classroomStudentParam lee el parámetro studentId y comprueba que el
+// estudiante esté inscrito en la clase.
+func classroomStudentParam(c *gin.Context, classroom *models.Classroom) (primitive.ObjectID, bool) {
+	studentID, err := primitive.ObjectIDFromHex(c.Param("studentId"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "studentId"))
+		return primitive.NilObjectID, false
+	}
+	if !classroom.HasStudent(studentID) {
+		fail(c, apierror.New(apierror.StudentNotEnrolled))
+		return primitive.NilObjectID, false
+	}
+	return studentID, true
+}
+
+// CreateClassroom godoc
+// @Summary Crea una clase
+// @Description Crea una clase a cargo del docente autenticado con un código aleatorio de 8 caracteres, que los estudiantes usan para inscribirse.
+// @Tags Classrooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param classroom body CreateClassroomRequest true "Datos de la clase"
+// @Success 201 {object} models.Classroom
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 500 {object} apierror.Response "No se pudo crear la clase"
+// @Router /classrooms [post]
+func (s *Server) CreateClassroom(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var input CreateClassroomRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxClassroomNameLength {
+		fail(c, apierror.Invalid(apierror.Field("name", apierror.InvalidLength).With("min", 1).With("max", maxClassroomNameLength)))
+		return
+	}
+
+	classroom := models.Classroom{
+		ID:         primitive.NewObjectID(),
+		Name:       name,
+		TeacherID:  userObjID,
+		StudentIDs: []primitive.ObjectID{},
+		CreatedAt:  s.now(),
+	}
+	var err error
+	for attempt := 0; attempt < joinCodeAttempts; attempt++ {
+		if classroom.JoinCode, err = models.NewJoinCode(); err != nil {
+			break
+		}
+		// Si el código ya existe se vuelve a intentar con otro
+		if err = s.classrooms.InsertClassroom(classroom); !errors.Is(err, database.ErrDuplicate) {
+			break
+		}
+	}
+	if err != nil {
+		s.internalError(c, "No se pudo crear la clase", err)
+		return
+	}
+	c.JSON(http.StatusCreated, classroom)
+}
+
+// classroomOrder es el orden de las listas de clases: por fecha de creación y
+// luego por ID.
+var classroomOrder = listOrder[models.Classroom, dateKey]{
+	key:  func(c models.Classroom) dateKey { return dateKey{Date: c.CreatedAt, ID: c.ID} },
+	item: func(k dateKey) models.Classroom { return models.Classroom{ID: k.ID, CreatedAt: k.Date} },
+}
+
+// ListClassrooms godoc
+// @Summary Lista las clases del docente
+// @Description Devuelve una página de las clases a cargo del docente autenticado, con su código y sus estudiantes, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.Classroom]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las clases"
+// @Router /classrooms [get]
+func (s *Server) ListClassrooms(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(models.Classroom{}))
+	if !ok {
+		return
+	}
+	query, ok := classroomOrder.query(c, page)
+	if !ok {
+		return
+	}
+	classrooms, err := s.classrooms.GetTeacherClassrooms(userObjID, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las clases", err)
+		return
+	}
+	classrooms, next := classroomOrder.next(c, page, classrooms)
+	respondPage(s, c, page, classrooms, next)
+}
+
+// GetClassroomDashboard godoc
+// @Summary Obtiene el tablero de una clase
+// @Description Devuelve el avance de cada estudiante de la clase (misiones completadas, duración promedio, porcentaje de avance, XP, nivel y misiones activas) y el resumen de la clase. Solo lo ve el docente de la clase o un administrador.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la clase"
+// @Success 200 {object} ClassroomDashboard
+// @Failure 400 {object} apierror.Response "ID inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 404 {object} apierror.Response "Clase no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo obtener el tablero"
+// @Router /classrooms/{id}/dashboard [get]
+func (s *Server) GetClassroomDashboard(c *gin.Context) {
+	classroom, ok := s.teacherClassroom(c)
+	if !ok {
+		return
+	}
+
+	students := make([]StudentProgressSummary, 0, len(classroom.StudentIDs))
+	for _, studentID := range classroom.StudentIDs {
+		student, err := s.users.FindUserByID(studentID)
+		if errors.Is(err, database.ErrNotFound) {
+			// La cuenta se eliminó después de inscribirse
+			continue
+		}
+		if err != nil {
+			s.internalError(c, "No se pudo obtener el tablero", err)
+			return
+		}
+		summary, err := s.studentSummary(*student)
+		if err != nil {
+			s.internalError(c, "No se pudo obtener el tablero", err)
+			return
+		}
+		students = append(students, summary)
+	}
+
+	c.JSON(http.StatusOK, ClassroomDashboard{
+		Classroom: *classroom,
+		Summary:   summarizeClassroom(students),
+		Students:  students,
+	})
+}
+
+// GetClassroomStudentReport godoc
+// @Summary Obtiene el avance de un estudiante de la clase
+// @Description Devuelve las estadísticas del estudiante junto con sus 20 misiones activas y sus 20 completadas más recientes. Solo lo ve el docente de la clase o un administrador.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la clase"
+// @Param studentId path string true "ID del estudiante"
+// @Success 200 {object} StudentReport
+// @Failure 400 {object} apierror.Response "ID inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 404 {object} apierror.Response "Clase o estudiante no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudo obtener el avance del estudiante"
+// @Router /classrooms/{id}/students/{studentId} [get]
+func (s *Server) GetClassroomStudentReport(c *gin.Context) {
+	classroom, ok := s.teacherClassroom(c)
+	if !ok {
+		return
+	}
+	studentID, ok := classroomStudentParam(c, classroom)
+	if !ok {
+		return
+	}
+
+	student, err := s.users.FindUserByID(studentID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.StudentNotFound))
+		} else {
+			s.internalError(c, "No se pudo obtener el avance del estudiante", err)
+		}
+		return
+	}
+	report, err := s.studentReport(*student)
+	if err != nil {
+		s.internalError(c, "No se pudo obtener el avance del estudiante", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// RemoveClassroomStudent godoc
+// @Summary Da de baja a un estudiante de la clase
+// @Description Quita al estudiante de la clase. Su progreso se conserva.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la clase"
+// @Param studentId path string true "ID del estudiante"
+// @Success 200 {object} GenericResponse
+// @Failure 400 {object} apierror.Response "ID inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de docente"
+// @Failure 404 {object} apierror.Response "Clase o estudiante no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudo dar de baja al estudiante"
+// @Router /classrooms/{id}/students/{studentId} [delete]
+func (s *Server) RemoveClassroomStudent(c *gin.Context) {
+	classroom, ok := s.teacherClassroom(c)
+	if !ok {
+		return
+	}
+	studentID, ok := classroomStudentParam(c, classroom)
+	if !ok {
+		return
+	}
+	if err := s.classrooms.RemoveClassroomStudent(classroom.ID, studentID); err != nil {
+		s.internalError(c, "No se pudo dar de baja al estudiante", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Estudiante dado de baja"})
+}
+
+// JoinClassroom godoc
+// @Summary Inscribe al estudiante en una clase
+// @Description Inscribe al estudiante autenticado en la clase del código indicado. El código no distingue mayúsculas y admite espacios o guiones. Inscribirse de nuevo en la misma clase no tiene efecto.
+// @Tags Classrooms
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param join body JoinClassroomRequest true "Código de la clase"
+// @Success 200 {object} StudentClassroom
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Solo los estudiantes pueden inscribirse"
+// @Failure 404 {object} apierror.Response "Código de clase inválido"
+// @Failure 500 {object} apierror.Response "No se pudo completar la inscripción"
+// @Router /classrooms/join [post]
+func (s *Server) JoinClassroom(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var input JoinClassroomRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	classroom, err := s.classrooms.FindClassroomByJoinCode(models.NormalizeJoinCode(input.JoinCode))
+	if err == nil {
+		err = s.classrooms.AddClassroomStudent(classroom.ID, userObjID)
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.InvalidJoinCode))
+		} else {
+			s.internalError(c, "No se pudo completar la inscripción", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, StudentClassroom{ID: classroom.ID, Name: classroom.Name})
+}
+
+// GetMyClassrooms godoc
+// @Summary Lista las clases del estudiante
+// @Description Devuelve una página de las clases en que está inscrito el usuario autenticado, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[StudentClassroom]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las clases"
+// @Router /me/classrooms [get]
+func (s *Server) GetMyClassrooms(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(StudentClassroom{}))
+	if !ok {
+		return
+	}
+	query, ok := classroomOrder.query(c, page)
+	if !ok {
+		return
+	}
+	classrooms, err := s.classrooms.GetStudentClassrooms(userObjID, query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las clases", err)
+		return
+	}
+	classrooms, next := classroomOrder.next(c, page, classrooms)
+	result := make([]StudentClassroom, 0, len(classrooms))
+	for _, classroom := range classrooms {
+		result = append(result, StudentClassroom{ID: classroom.ID, Name: classroom.Name})
+	}
+	respondPage(s, c, page, result, next)
+}
+
+// LeaveClassroom godoc
+// @Summary Abandona una clase
+// @Description Da de baja al usuario autenticado de la clase. Su progreso se conserva.
+// @Tags Classrooms
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la clase"
+// @Success 200 {object} GenericResponse
+// @Failure 400 {object} apierror.Response "ID inválido"
+// @Failure 404 {object} apierror.Response "No estás inscrito en la clase"
+// @Failure 500 {object} apierror.Response "No se pudo abandonar la clase"
+// @Router /me/classrooms/{id} [delete]
+func (s *Server) LeaveClassroom(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	classroomID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return
+	}
+
+	classroom, err := s.classrooms.GetClassroomByID(classroomID)
+	if err == nil && !classroom.HasStudent(userObjID) {
+		err = database.ErrNotFound
+	}
+	if err == nil {
+		err = s.classrooms.RemoveClassroomStudent(classroomID, userObjID)
+	}
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.NotEnrolled))
+		} else {
+			s.internalError(c, "No se pudo abandonar la clase", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Abandonaste la clase"})
+}
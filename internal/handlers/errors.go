@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"explorax-backend/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serverKey guarda en el contexto el Server de la organización de la petición.
+const serverKey = "server"
+
+// fail responde con el error en el idioma de la petición: el preferido por el
+// usuario autenticado o el negociado con Accept-Language.
+func fail(c *gin.Context, err *apierror.Error) {
+	if value, exists := c.Get(serverKey); exists {
+		value.(*Server).locale(c)
+	}
+	apierror.Respond(c, err)
+}
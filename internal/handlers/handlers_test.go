@@ -0,0 +1,1988 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/handlers"
+	"explorax-backend/internal/middleware"
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/testutils"
+	"explorax-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testSecret = "test-secret-key"
+
+// testAPI bundles a router backed by an in-memory store
+type testAPI struct {
+	t      *testing.T
+	router *gin.Engine
+	store  *database.MemoryStore
+	clock  *testutils.FakeClock
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	return newTestAPIWith(t, nil)
+}
+
+// newTestAPIWith builds a testAPI whose handlers see the store of each tenant
+// through wrap, e.g. to make some operations fail; wrap may be nil
+func newTestAPIWith(t *testing.T, wrap func(database.Store) database.Store) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	store := database.NewMemoryStore()
+	clock := testutils.NewFakeClock(time.Now().Truncate(time.Millisecond))
+	deps := handlers.StoreDeps(store, utils.NewHMACSigner(testSecret, clock.Now))
+	if wrap != nil {
+		deps = handlers.StoreDeps(wrap(store), utils.NewHMACSigner(testSecret, clock.Now))
+		deps.Scope = func(tenantID string) database.Store { return wrap(store.ForTenant(tenantID)) }
+	}
+	deps.Clock = clock.Now
+	deps.Logger = log.New(io.Discard, "", 0)
+	return &testAPI{t: t, router: handlers.NewRouter(deps), store: store, clock: clock}
+}
+
+// do sends a JSON request and decodes the JSON response into out when given
+func (a *testAPI) do(method, path, token string, body interface{}, out interface{}) int {
+	a.t.Helper()
+	return a.doIn("", method, path, token, body, out)
+}
+
+// doIn sends the request with tenantID in the X-Tenant-ID header, unless empty
+func (a *testAPI) doIn(tenantID, method, path, token string, body interface{}, out interface{}) int {
+	a.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(a.t, err)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if tenantID != "" {
+		req.Header.Set(middleware.TenantHeader, tenantID)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+	if out != nil {
+		require.NoError(a.t, json.Unmarshal(w.Body.Bytes(), out), w.Body.String())
+	}
+	return w.Code
+}
+
+// createUser stores a user with the given role directly in the store
+func (a *testAPI) createUser(username string, role models.Role) models.User {
+	a.t.Helper()
+	return a.createUserIn(models.DefaultTenantID, username, role)
+}
+
+// createUserIn stores a user of the given tenant directly in the store
+func (a *testAPI) createUserIn(tenantID, username string, role models.Role) models.User {
+	a.t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(a.t, err)
+	user := models.User{
+		ID:           primitive.NewObjectID(),
+		Username:     username,
+		Email:        username + "@example.com",
+		PasswordHash: string(hash),
+		Role:         role,
+		CreatedAt:    a.clock.Now(),
+	}
+	require.NoError(a.t, a.store.ForTenant(tenantID).InsertUser(user))
+	return user
+}
+
+// login authenticates a user created with createUser
+func (a *testAPI) login(user models.User) handlers.TokenResponse {
+	a.t.Helper()
+	return a.loginIn("", user)
+}
+
+// loginIn authenticates a user created with createUserIn
+func (a *testAPI) loginIn(tenantID string, user models.User) handlers.TokenResponse {
+	a.t.Helper()
+	var tokens handlers.TokenResponse
+	code := a.doIn(tenantID, "POST", "/auth/login", "", gin.H{"email": user.Email, "password": "secret123"}, &tokens)
+	require.Equal(a.t, http.StatusOK, code)
+	return tokens
+}
+
+// createMission inserts a mission directly in the store
+func (a *testAPI) createMission(title string) models.Mission {
+	a.t.Helper()
+	mission := models.Mission{ID: primitive.NewObjectID(), Title: title, Description: title, CreatedAt: a.clock.Now()}
+	require.NoError(a.t, a.store.InsertMission(mission))
+	return mission
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	api := newTestAPI(t)
+
+	code := api.do("POST", "/auth/register", "", gin.H{
+		"username": "nuevo", "email": "nuevo@example.com", "password": "secret123",
+	}, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	user, err := api.store.FindUserByEmail("nuevo@example.com")
+	require.NoError(t, err)
+	require.Equal(t, models.RoleStudent, user.Role)
+
+	var tokens handlers.TokenResponse
+	code = api.do("POST", "/auth/login", "", gin.H{"email": "nuevo@example.com", "password": "secret123"}, &tokens)
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, tokens.Token)
+	require.NotEmpty(t, tokens.RefreshToken)
+
+	code = api.do("POST", "/auth/login", "", gin.H{"email": "nuevo@example.com", "password": "incorrecta"}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestMissionLifecycleUsesClock(t *testing.T) {
+	api := newTestAPI(t)
+	user := api.createUser("alumno", models.RoleStudent)
+	mission := api.createMission("Explorar Marte")
+	tokens := api.login(user)
+
+	code := api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": mission.ID.Hex()}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	api.clock.Advance(5 * time.Minute)
+	code = api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": mission.ID.Hex()}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var completed handlers.Page[models.MissionProgress]
+	code = api.do("GET", "/missions/completed", tokens.Token, nil, &completed)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, completed.Items, 1)
+	require.Equal(t, 5*time.Minute, completed.Items[0].EndDate.Sub(completed.Items[0].StartDate))
+
+	code = api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": mission.ID.Hex()}, nil)
+	require.Equal(t, http.StatusConflict, code)
+}
+
+func TestStartMissionValidation(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+	mission := api.createMission("Explorar Marte")
+	start := gin.H{"mission_id": mission.ID.Hex()}
+
+	code := api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": primitive.NewObjectID().Hex()}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	code = api.do("POST", "/missions/complete", tokens.Token, start, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code = api.do("POST", "/missions/start", tokens.Token, start, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("POST", "/missions/start", tokens.Token, start, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	code = api.do("POST", "/missions/complete", tokens.Token, start, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("POST", "/missions/start", tokens.Token, start, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	var progress handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/progress", tokens.Token, nil, &progress)
+	require.Len(t, progress.Items, 1)
+
+	archived := true
+	retired := api.createMission("Retirada")
+	_, err := api.store.UpdateMission(retired.ID, database.MissionUpdate{Archived: &archived}, api.clock.Now())
+	require.NoError(t, err)
+	code = api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": retired.ID.Hex()}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	api := newTestAPI(t)
+	user := api.createUser("alumno", models.RoleStudent)
+	first := api.login(user)
+
+	var second handlers.TokenResponse
+	code := api.do("POST", "/auth/refresh", "", gin.H{"refresh_token": first.RefreshToken}, &second)
+	require.Equal(t, http.StatusOK, code)
+	require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// Reusing the rotated token revokes the whole session
+	code = api.do("POST", "/auth/refresh", "", gin.H{"refresh_token": first.RefreshToken}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	code = api.do("GET", "/missions/progress", second.Token, nil, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	api := newTestAPI(t)
+	user := api.createUser("alumno", models.RoleStudent)
+	tablet := api.login(user)
+	laptop := api.login(user)
+
+	code := api.do("POST", "/auth/logout", laptop.Token, gin.H{"all": true}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code = api.do("GET", "/missions/progress", tablet.Token, nil, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code = api.do("POST", "/auth/refresh", "", gin.H{"refresh_token": tablet.RefreshToken}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestAdminRoutesRequireAdminRole(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	body := gin.H{"title": "Nueva", "description": "Descripción"}
+
+	code := api.do("POST", "/admin/missions/create", student.Token, body, nil)
+	require.Equal(t, http.StatusForbidden, code)
+
+	code = api.do("POST", "/admin/missions/create", admin.Token, body, nil)
+	require.Equal(t, http.StatusCreated, code)
+}
+
+func TestAdminMissionCRUD(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+	mission := api.createMission("Explorar Mrate")
+	unused := api.createMission("Borrador")
+	path := "/admin/missions/" + mission.ID.Hex()
+
+	var updated models.Mission
+	code := api.do("PUT", path, admin.Token, gin.H{"title": "Explorar Marte", "description": "Recorre Marte"}, &updated)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "Explorar Marte", updated.Title)
+
+	code = api.do("PATCH", path, admin.Token, gin.H{}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code = api.do("POST", "/missions/start", student.Token, gin.H{"mission_id": mission.ID.Hex()}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	// A mission with progress can be archived but not deleted
+	code = api.do("DELETE", path, admin.Token, nil, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	code = api.do("PATCH", path, admin.Token, gin.H{"archived": true}, &updated)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, updated.Archived)
+
+	var catalog handlers.MissionCatalog
+	code = api.do("GET", "/missions/all", student.Token, nil, &catalog)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, catalog.Items, 1)
+	require.Equal(t, unused.ID, catalog.Items[0].ID)
+
+	code = api.do("DELETE", "/admin/missions/"+unused.ID.Hex(), admin.Token, nil, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("DELETE", "/admin/missions/"+unused.ID.Hex(), admin.Token, nil, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	code = api.do("DELETE", path, student.Token, nil, nil)
+	require.Equal(t, http.StatusForbidden, code)
+}
+
+func TestPauseResumeExcludesPausedTime(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+	mission := api.createMission("Explorar Marte")
+	body := gin.H{"mission_id": mission.ID.Hex()}
+
+	code := api.do("POST", "/missions/pause", tokens.Token, body, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+	code = api.do("POST", "/missions/resume", tokens.Token, body, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	api.clock.Advance(5 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/pause", tokens.Token, body, nil))
+
+	// Una misión pausada sigue activa pero no puede completarse
+	var active handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/active", tokens.Token, nil, &active)
+	require.Len(t, active.Items, 1)
+	require.Equal(t, models.ProgressPaused, active.Items[0].Status)
+	code = api.do("POST", "/missions/complete", tokens.Token, body, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	api.clock.Advance(time.Hour)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/resume", tokens.Token, body, nil))
+	api.clock.Advance(3 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, nil))
+
+	var completed handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/completed", tokens.Token, nil, &completed)
+	require.Len(t, completed.Items, 1)
+	require.Equal(t, (8 * time.Minute).Milliseconds(), completed.Items[0].ActiveDurationMs)
+
+	var stats map[string]interface{}
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/statistics", tokens.Token, nil, &stats))
+	require.InDelta(t, float64((8 * time.Minute).Milliseconds()), stats["averageDuration"], 0.5)
+}
+
+func TestAbandonAndRestartMission(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+	mission := api.createMission("Explorar Marte")
+	body := gin.H{"mission_id": mission.ID.Hex()}
+
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+	api.clock.Advance(10 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/abandon", tokens.Token, body, nil))
+
+	code := api.do("POST", "/missions/complete", tokens.Token, body, nil)
+	require.Equal(t, http.StatusConflict, code)
+	code = api.do("POST", "/missions/abandon", tokens.Token, body, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	var active handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/active", tokens.Token, nil, &active)
+	require.Empty(t, active.Items)
+
+	// Reiniciar descarta el tiempo del intento abandonado
+	api.clock.Advance(time.Hour)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+	api.clock.Advance(2 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, nil))
+
+	var progress handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/progress", tokens.Token, nil, &progress)
+	require.Len(t, progress.Items, 1)
+	require.Equal(t, models.ProgressCompleted, progress.Items[0].Status)
+	require.Nil(t, progress.Items[0].AbandonedAt)
+	require.Equal(t, (2 * time.Minute).Milliseconds(), progress.Items[0].ActiveDurationMs)
+}
+
+func TestMultiStepMissionCompletesAutomatically(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	var created struct {
+		Mission models.Mission `json:"mission"`
+	}
+	code := api.do("POST", "/admin/missions/create", admin.Token, gin.H{
+		"title":       "Explorar Marte",
+		"description": "Recorre la superficie marciana",
+		"steps": []gin.H{
+			{"title": "Aterrizar"},
+			{"title": "Tomar muestras"},
+			{"title": "Fotografiar el cráter", "optional": true},
+		},
+	}, &created)
+	require.Equal(t, http.StatusCreated, code)
+	steps := created.Mission.Steps
+	require.Len(t, steps, 3)
+
+	missionID := created.Mission.ID.Hex()
+	step := func(i int) gin.H { return gin.H{"mission_id": missionID, "step_id": steps[i].ID.Hex()} }
+
+	code = api.do("POST", "/missions/steps/complete", tokens.Token, step(0), nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": missionID}, nil))
+
+	code = api.do("POST", "/missions/steps/complete", tokens.Token, gin.H{"mission_id": missionID, "step_id": primitive.NewObjectID().Hex()}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/steps/complete", tokens.Token, step(0), nil))
+	code = api.do("POST", "/missions/steps/complete", tokens.Token, step(0), nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	// Los pasos obligatorios pendientes impiden completar la misión a mano
+	code = api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": missionID}, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	var progress handlers.Page[handlers.MissionProgressView]
+	api.do("GET", "/missions/progress?expand=mission", tokens.Token, nil, &progress)
+	require.Len(t, progress.Items, 1)
+	require.Equal(t, 1, *progress.Items[0].StepsCompleted)
+	require.Equal(t, 2, *progress.Items[0].StepsRequired)
+	require.InDelta(t, 50.0, *progress.Items[0].CompletionPercentage, 0.001)
+
+	var result struct {
+		MissionCompleted bool                         `json:"missionCompleted"`
+		Progress         handlers.MissionProgressView `json:"progress"`
+	}
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/steps/complete", tokens.Token, step(1), &result))
+	require.True(t, result.MissionCompleted)
+	require.Equal(t, models.ProgressCompleted, result.Progress.Status)
+	require.InDelta(t, 100.0, *result.Progress.CompletionPercentage, 0.001)
+
+	// El paso opcional ya no se puede completar en una misión terminada
+	code = api.do("POST", "/missions/steps/complete", tokens.Token, step(2), nil)
+	require.Equal(t, http.StatusConflict, code)
+}
+
+func TestQuizMissionGradedOnServer(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	var created struct {
+		Mission models.Mission `json:"mission"`
+	}
+	code := api.do("POST", "/admin/missions/create", admin.Token, gin.H{
+		"title":       "Sistema solar",
+		"description": "Responde el cuestionario",
+		"quiz": gin.H{
+			"passing_score": 60,
+			"questions": []gin.H{
+				{"type": "multiple_choice", "prompt": "¿Cuál es el planeta rojo?", "options": []string{"Venus", "Marte"}, "correct_option": 1},
+				{"type": "numeric", "prompt": "¿Cuántos planetas hay?", "numeric_answer": 8},
+				{"type": "short_text", "prompt": "¿Cómo se llama nuestra galaxia?", "accepted_answers": []string{"Vía Láctea"}},
+			},
+		},
+	}, &created)
+	require.Equal(t, http.StatusCreated, code)
+	missionID := created.Mission.ID.Hex()
+	questions := created.Mission.Quiz.Questions
+
+	// Las respuestas no se exponen a los estudiantes
+	var raw map[string]interface{}
+	require.Equal(t, http.StatusOK, api.do("GET", "/mission/"+missionID, tokens.Token, nil, &raw))
+	quiz := raw["quiz"].(map[string]interface{})
+	for _, q := range quiz["questions"].([]interface{}) {
+		question := q.(map[string]interface{})
+		require.NotContains(t, question, "correctOption")
+		require.NotContains(t, question, "numericAnswer")
+		require.NotContains(t, question, "acceptedAnswers")
+	}
+
+	submit := "/missions/" + missionID + "/submit"
+	answers := func(option int, number float64, text string) gin.H {
+		return gin.H{"answers": []gin.H{
+			{"question_id": questions[0].ID.Hex(), "option": option},
+			{"question_id": questions[1].ID.Hex(), "number": number},
+			{"question_id": questions[2].ID.Hex(), "text": text},
+		}}
+	}
+
+	code = api.do("POST", submit, tokens.Token, answers(1, 8, "via lactea"), nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": missionID}, nil))
+
+	code = api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": missionID}, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	type submitResponse struct {
+		Attempt          models.QuizAttempt `json:"attempt"`
+		MissionCompleted bool               `json:"missionCompleted"`
+	}
+	var failed submitResponse
+	require.Equal(t, http.StatusOK, api.do("POST", submit, tokens.Token, answers(0, 9, "Andrómeda"), &failed))
+	require.False(t, failed.Attempt.Passed)
+	require.False(t, failed.MissionCompleted)
+	require.Equal(t, 0, failed.Attempt.Correct)
+	// Con intentos disponibles no se revela qué respuestas eran correctas
+	require.Empty(t, failed.Attempt.Answers)
+
+	api.clock.Advance(time.Minute)
+	var passed submitResponse
+	require.Equal(t, http.StatusOK, api.do("POST", submit, tokens.Token, answers(1, 8, "  via LÁCTEA "), &passed))
+	require.True(t, passed.Attempt.Passed)
+	require.True(t, passed.MissionCompleted)
+	require.InDelta(t, 100.0, passed.Attempt.Score, 0.001)
+	require.Len(t, passed.Attempt.Answers, 3)
+
+	var attempts handlers.Page[models.QuizAttempt]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/"+missionID+"/attempts", tokens.Token, nil, &attempts))
+	require.Len(t, attempts.Items, 2)
+	require.Len(t, attempts.Items[0].Answers, 3)
+
+	var completed handlers.Page[models.MissionProgress]
+	api.do("GET", "/missions/completed", tokens.Token, nil, &completed)
+	require.Len(t, completed.Items, 1)
+
+	code = api.do("POST", submit, tokens.Token, answers(1, 8, "via lactea"), nil)
+	require.Equal(t, http.StatusConflict, code)
+}
+
+func TestQuizAttemptsAreCapped(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	var created struct {
+		Mission models.Mission `json:"mission"`
+	}
+	code := api.do("POST", "/admin/missions/create", admin.Token, gin.H{
+		"title":       "Constelaciones",
+		"description": "Responde el cuestionario",
+		"quiz": gin.H{
+			"max_attempts": 2,
+			"questions": []gin.H{
+				{"type": "multiple_choice", "prompt": "¿Qué constelación tiene tres estrellas alineadas?", "options": []string{"Orión", "Lira", "Casiopea"}, "correct_option": 0},
+			},
+		},
+	}, &created)
+	require.Equal(t, http.StatusCreated, code)
+	missionID := created.Mission.ID.Hex()
+	questionID := created.Mission.Quiz.Questions[0].ID.Hex()
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": missionID}, nil))
+
+	submit := "/missions/" + missionID + "/submit"
+	answer := func(option int) gin.H {
+		return gin.H{"answers": []gin.H{{"question_id": questionID, "option": option}}}
+	}
+	type submitResponse struct {
+		Attempt           models.QuizAttempt `json:"attempt"`
+		AttemptsRemaining int                `json:"attemptsRemaining"`
+	}
+
+	var first submitResponse
+	require.Equal(t, http.StatusOK, api.do("POST", submit, tokens.Token, answer(1), &first))
+	require.Equal(t, 1, first.AttemptsRemaining)
+	require.Empty(t, first.Attempt.Answers)
+
+	var attempts handlers.Page[models.QuizAttempt]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/"+missionID+"/attempts", tokens.Token, nil, &attempts))
+	require.Len(t, attempts.Items, 1)
+	require.Empty(t, attempts.Items[0].Answers)
+
+	// El último intento disponible sí muestra las respuestas calificadas
+	api.clock.Advance(time.Minute)
+	var last submitResponse
+	require.Equal(t, http.StatusOK, api.do("POST", submit, tokens.Token, answer(2), &last))
+	require.Equal(t, 0, last.AttemptsRemaining)
+	require.Equal(t, 2, last.Attempt.Number)
+	require.Len(t, last.Attempt.Answers, 1)
+	require.False(t, last.Attempt.Answers[0].Correct)
+
+	// Al agotar los intentos se revelan también los de páginas anteriores
+	var firstPage, secondPage handlers.Page[models.QuizAttempt]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/"+missionID+"/attempts?limit=1", tokens.Token, nil, &firstPage))
+	require.Len(t, firstPage.Items, 1)
+	require.Len(t, firstPage.Items[0].Answers, 1)
+	require.NotEmpty(t, firstPage.NextCursor)
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/"+missionID+"/attempts?limit=1&cursor="+firstPage.NextCursor, tokens.Token, nil, &secondPage))
+	require.Len(t, secondPage.Items, 1)
+	require.Equal(t, 2, secondPage.Items[0].Number)
+	require.Empty(t, secondPage.NextCursor)
+
+	var exhausted apierror.Response
+	require.Equal(t, http.StatusConflict, api.do("POST", submit, tokens.Token, answer(0), &exhausted))
+	require.Equal(t, apierror.QuizAttemptsExhausted, exhausted.Error.Code)
+}
+
+func TestMissionsOverviewHidesQuizAnswers(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.createUser("alumno", models.RoleStudent)
+
+	option := 2
+	number := 42.0
+	mission := models.Mission{
+		ID: primitive.NewObjectID(), Title: "Cuestionario", Description: "Con respuestas", CreatedAt: api.clock.Now(),
+		Quiz: &models.Quiz{PassingScore: 50, Questions: []models.QuizQuestion{
+			{ID: primitive.NewObjectID(), Type: models.QuestionMultipleChoice, Prompt: "¿Cuál?", Options: []string{"a", "b", "c"}, CorrectOption: &option},
+			{ID: primitive.NewObjectID(), Type: models.QuestionNumeric, Prompt: "¿Cuánto?", NumericAnswer: &number},
+			{ID: primitive.NewObjectID(), Type: models.QuestionShortText, Prompt: "¿Quién?", AcceptedAnswers: []string{"RESPUESTASECRETA"}},
+		}},
+	}
+	require.NoError(t, api.store.InsertMission(mission))
+	progress := models.NewMissionProgress(student.ID, mission.ID, api.clock.Now())
+	require.NoError(t, progress.Apply(models.ActionComplete, api.clock.Now().Add(time.Minute)))
+	require.NoError(t, api.store.InsertMissionProgress(progress))
+
+	// El overview es público: de cada misión solo muestra el ID y el título
+	req := httptest.NewRequest("GET", "/missions/overview", nil)
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, secret := range []string{"correctOption", "numericAnswer", "acceptedAnswers", "RESPUESTASECRETA", "quiz", "tenantId"} {
+		require.NotContains(t, w.Body.String(), secret)
+	}
+	require.Equal(t, map[string]int{"Cuestionario": 1}, api.overviewCounts(""))
+}
+
+func TestCompletingMissionsAwardsXPAndLevels(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.createUser("alumno", models.RoleStudent)
+	rival := api.createUser("rival", models.RoleStudent)
+	tokens := api.login(student)
+	rivalTokens := api.login(rival)
+
+	basic := api.createMission("Básica")
+	hard := models.Mission{ID: primitive.NewObjectID(), Title: "Difícil", XPReward: 150, DifficultyMultiplier: 1.5, CreatedAt: api.clock.Now()}
+	require.NoError(t, api.store.InsertMission(hard))
+
+	complete := func(token string, mission models.Mission) int {
+		body := gin.H{"mission_id": mission.ID.Hex()}
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", token, body, nil))
+		var result struct {
+			XPAwarded int `json:"xpAwarded"`
+		}
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", token, body, &result))
+		return result.XPAwarded
+	}
+	require.Equal(t, models.DefaultXPReward, complete(tokens.Token, basic))
+	require.Equal(t, 225, complete(tokens.Token, hard))
+	require.Equal(t, 225, complete(rivalTokens.Token, hard))
+
+	// Completar de nuevo no vuelve a sumar XP
+	code := api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": hard.ID.Hex()}, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	// Con la curva por defecto: nivel 2 a los 100 XP y nivel 3 a los 250
+	var stats map[string]interface{}
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/statistics", tokens.Token, nil, &stats))
+	require.EqualValues(t, 325, stats["xp"])
+	require.EqualValues(t, 3, stats["level"])
+	require.EqualValues(t, 150, stats["xpToNextLevel"])
+
+	var leaderboard handlers.Page[handlers.LeaderboardEntry]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/leaderboard", "", nil, &leaderboard))
+	require.Len(t, leaderboard.Items, 2)
+	require.Equal(t, models.Pseudonym(student.ID), leaderboard.Items[0].DisplayName)
+	require.Equal(t, 325, leaderboard.Items[0].XP)
+	require.Equal(t, 3, leaderboard.Items[0].Level)
+	require.Equal(t, models.Pseudonym(rival.ID), leaderboard.Items[1].DisplayName)
+	require.Equal(t, 2, leaderboard.Items[1].Level)
+}
+
+func TestAchievementsAwardedOnStartAndComplete(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	define := func(code string, ruleType models.RuleType, threshold int) models.Achievement {
+		var achievement models.Achievement
+		status := api.do("POST", "/admin/achievements", admin.Token, gin.H{
+			"code": code, "name": code, "rule": gin.H{"type": ruleType, "threshold": threshold},
+		}, &achievement)
+		require.Equal(t, http.StatusCreated, status)
+		return achievement
+	}
+	define("primer-paso", models.RuleMissionsStarted, 1)
+	define("dos-misiones", models.RuleMissionsCompleted, 2)
+	define("veloz", models.RuleFastCompletion, 10)
+	define("racha-2", models.RuleStreakDays, 2)
+
+	code := api.do("POST", "/admin/achievements", admin.Token, gin.H{
+		"code": "invalido", "name": "Inválido", "rule": gin.H{"type": "desconocida", "threshold": 1},
+	}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	code = api.do("POST", "/admin/achievements", tokens.Token, gin.H{
+		"code": "alumno", "name": "Alumno", "rule": gin.H{"type": models.RuleMissionsStarted, "threshold": 1},
+	}, nil)
+	require.Equal(t, http.StatusForbidden, code)
+
+	type result struct {
+		Achievements []models.UserAchievement `json:"achievements"`
+	}
+	codes := func(r result) []string {
+		var out []string
+		for _, a := range r.Achievements {
+			out = append(out, a.Code)
+		}
+		return out
+	}
+
+	first := api.createMission("Primera")
+	body := gin.H{"mission_id": first.ID.Hex()}
+	var started result
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, &started))
+	require.Equal(t, []string{"primer-paso"}, codes(started))
+
+	// Más de 10 minutos activos: no es una misión veloz
+	api.clock.Advance(30 * time.Minute)
+	var completed result
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, &completed))
+	require.Empty(t, completed.Achievements)
+
+	// Al día siguiente, una misión rápida completa la racha de dos días
+	api.clock.Advance(24 * time.Hour)
+	second := api.createMission("Segunda")
+	body = gin.H{"mission_id": second.ID.Hex()}
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, &started))
+	require.Empty(t, started.Achievements)
+	api.clock.Advance(5 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, &completed))
+	require.ElementsMatch(t, []string{"dos-misiones", "veloz", "racha-2"}, codes(completed))
+
+	var mine handlers.Page[models.UserAchievement]
+	require.Equal(t, http.StatusOK, api.do("GET", "/me/achievements", tokens.Token, nil, &mine))
+	require.Len(t, mine.Items, 4)
+	require.Equal(t, "primer-paso", mine.Items[0].Code)
+
+	// Las reglas nuevas reconocen el historial en la siguiente evaluación
+	define("una-mision", models.RuleMissionsCompleted, 1)
+	third := api.createMission("Tercera")
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": third.ID.Hex()}, &started))
+	require.Equal(t, []string{"una-mision"}, codes(started))
+}
+
+func TestStreakCountsDaysInUserTimezone(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.createUser("alumno", models.RoleStudent)
+	tokens := api.login(student)
+
+	code := api.do("PATCH", "/me/preferences", tokens.Token, gin.H{"timezone": "Marte/Olympus"}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	code = api.do("PATCH", "/me/preferences", tokens.Token, gin.H{"timezone": "America/Guatemala"}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	complete := func(title string) {
+		body := gin.H{"mission_id": api.createMission(title).ID.Hex()}
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, nil))
+	}
+	streak := func() handlers.StreakResponse {
+		var out handlers.StreakResponse
+		require.Equal(t, http.StatusOK, api.do("GET", "/me/streak?days=7", tokens.Token, nil, &out))
+		return out
+	}
+
+	// 03:00 UTC del 10 de marzo todavía es el 9 de marzo en Guatemala (UTC-6)
+	api.clock.Set(time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC))
+	complete("Noche")
+	got := streak()
+	require.Equal(t, "America/Guatemala", got.Timezone)
+	require.Equal(t, "2025-03-09", got.LastActiveDay)
+	require.Equal(t, 1, got.Current)
+
+	// 20:00 UTC del 10 de marzo es el día siguiente en Guatemala
+	api.clock.Set(time.Date(2025, 3, 10, 20, 0, 0, 0, time.UTC))
+	complete("Tarde")
+	complete("Otra tarde")
+	got = streak()
+	require.Equal(t, 2, got.Current)
+	require.True(t, got.ActiveToday)
+	require.Len(t, got.Heatmap, 7)
+	require.Equal(t, models.HeatmapDay{Date: "2025-03-10", Count: 2}, got.Heatmap[6])
+	require.Equal(t, models.HeatmapDay{Date: "2025-03-09", Count: 1}, got.Heatmap[5])
+
+	// Con una protección, faltar un día no rompe la racha
+	user, err := api.store.FindUserByID(student.ID)
+	require.NoError(t, err)
+	user.Streak.Freezes = 1
+	require.NoError(t, api.store.UpdateUserStreak(student.ID, user.Streak))
+
+	api.clock.Set(time.Date(2025, 3, 12, 20, 0, 0, 0, time.UTC))
+	require.Equal(t, 2, streak().Current)
+	complete("Regreso")
+	got = streak()
+	require.Equal(t, 3, got.Current)
+	require.Equal(t, 0, got.Freezes)
+	require.True(t, got.Heatmap[5].Frozen)
+
+	// Sin protecciones, dos días sin actividad la rompen
+	api.clock.Set(time.Date(2025, 3, 15, 20, 0, 0, 0, time.UTC))
+	got = streak()
+	require.Equal(t, 0, got.Current)
+	require.Equal(t, 3, got.Longest)
+	complete("Nuevo comienzo")
+	require.Equal(t, 1, streak().Current)
+
+	code = api.do("GET", "/me/streak?days=0", tokens.Token, nil, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestLeaderboardPeriodsPaginationAndPosition(t *testing.T) {
+	api := newTestAPI(t)
+	users := map[string]models.User{}
+	complete := func(username string, missions int, at time.Time) {
+		user, ok := users[username]
+		if !ok {
+			user = api.createUser(username, models.RoleStudent)
+			users[username] = user
+		}
+		for i := 0; i < missions; i++ {
+			require.NoError(t, api.store.InsertMissionProgress(models.MissionProgress{
+				ID:        primitive.NewObjectID(),
+				UserID:    user.ID,
+				MissionID: primitive.NewObjectID(),
+				Status:    models.ProgressCompleted,
+				StartDate: at.Add(-time.Minute),
+				EndDate:   at,
+				XPAwarded: models.DefaultXPReward,
+			}))
+			require.NoError(t, api.store.AddUserXP(user.ID, models.DefaultXPReward))
+		}
+	}
+	// La semana anterior
+	complete("veterano", 5, time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC))
+	// Esta semana: ana y beto empatan, pero ana llegó primero
+	complete("ana", 2, time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC))
+	complete("beto", 2, time.Date(2025, 3, 11, 10, 0, 0, 0, time.UTC))
+	complete("carla", 1, time.Date(2025, 3, 11, 11, 0, 0, 0, time.UTC))
+	complete("veterano", 1, time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, api.store.RebuildLeaderboard())
+	beto := api.login(users["beto"])
+	outsider := api.login(api.createUser("nuevo", models.RoleStudent))
+
+	// Miércoles 12 de marzo de 2025: la semana empezó el lunes 10
+	api.clock.Set(time.Date(2025, 3, 12, 12, 0, 0, 0, time.UTC))
+
+	get := func(path string) ([]handlers.LeaderboardEntry, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var entries handlers.Page[handlers.LeaderboardEntry]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		require.Equal(t, entries.NextCursor, w.Header().Get("X-Next-Cursor"))
+		return entries.Items, entries.NextCursor
+	}
+	// Sin fecha de nacimiento todos aparecen con su seudónimo
+	byPseudonym := map[string]string{}
+	for username, user := range users {
+		byPseudonym[models.Pseudonym(user.ID)] = username
+	}
+	names := func(entries []handlers.LeaderboardEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, byPseudonym[e.DisplayName])
+		}
+		return out
+	}
+
+	all, next := get("/missions/leaderboard")
+	require.Equal(t, []string{"veterano", "ana", "beto", "carla"}, names(all))
+	require.Empty(t, next)
+
+	page, next := get("/missions/leaderboard?period=weekly&limit=2")
+	require.Equal(t, []string{"ana", "beto"}, names(page))
+	require.Equal(t, 200, page[0].XP)
+	require.NotEmpty(t, next)
+	page, next = get("/missions/leaderboard?period=weekly&limit=2&cursor=" + next)
+	require.Equal(t, []string{"carla", "veterano"}, names(page))
+	require.Equal(t, 3, page[0].Rank)
+	// El nivel de veterano se calcula con la XP de todos los periodos
+	require.Equal(t, 100, page[1].XP)
+	require.Equal(t, 4, page[1].Level)
+	require.Empty(t, next)
+
+	_, cursor := get("/missions/leaderboard?period=weekly&limit=1")
+	code := api.do("GET", "/missions/leaderboard?period=monthly&cursor="+cursor, "", nil, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	code = api.do("GET", "/missions/leaderboard?period=daily", "", nil, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	var position handlers.LeaderboardPosition
+	code = api.do("GET", "/missions/leaderboard/me?period=weekly&neighbours=1", beto.Token, nil, &position)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, 2, position.Rank)
+	require.Equal(t, []string{"ana", "beto", "carla"}, names(position.Entries))
+	require.Equal(t, []int{1, 2, 3}, []int{position.Entries[0].Rank, position.Entries[1].Rank, position.Entries[2].Rank})
+
+	code = api.do("GET", "/missions/leaderboard/me", outsider.Token, nil, nil)
+	require.Equal(t, http.StatusNotFound, code)
+	code = api.do("GET", "/missions/leaderboard/me", "", nil, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestLeaderboardExposesNoPersonalData(t *testing.T) {
+	api := newTestAPI(t)
+	minor := api.createUser("juan.perez", models.RoleStudent)
+	adult := api.createUser("ana.lopez", models.RoleTeacher)
+	hidden := api.createUser("oculta", models.RoleStudent)
+	minorTokens := api.login(minor)
+	adultTokens := api.login(adult)
+	hiddenTokens := api.login(hidden)
+
+	code := api.do("PATCH", "/me/preferences", minorTokens.Token, gin.H{
+		"birth_date": "2013-06-01", "display_name": "Juan Pérez", "avatar": "cohete",
+	}, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("PATCH", "/me/preferences", adultTokens.Token, gin.H{
+		"birth_date": "1990-01-15", "display_name": "Profe Ana",
+	}, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("PATCH", "/me/preferences", hiddenTokens.Token, gin.H{"hide_from_leaderboard": true}, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("PATCH", "/me/preferences", adultTokens.Token, gin.H{"display_name": "ana@example.com"}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	code = api.do("PATCH", "/me/preferences", adultTokens.Token, gin.H{"avatar": "foto-propia"}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	for _, token := range []string{minorTokens.Token, adultTokens.Token, hiddenTokens.Token} {
+		body := gin.H{"mission_id": api.createMission("Misión").ID.Hex()}
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", token, body, nil))
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", token, body, nil))
+	}
+
+	req := httptest.NewRequest("GET", "/missions/leaderboard?limit=1", nil)
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var first, second handlers.Page[handlers.LeaderboardEntry]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	next := first.NextCursor
+	require.NotEmpty(t, next)
+	req = httptest.NewRequest("GET", "/missions/leaderboard?limit=1&cursor="+next, nil)
+	w2 := httptest.NewRecorder()
+	api.router.ServeHTTP(w2, req)
+	require.Equal(t, http.StatusOK, w2.Code)
+
+	// Ni el cuerpo ni las cabeceras, incluido el cursor decodificado, llevan
+	// datos personales o identificadores
+	cursor, err := base64.RawURLEncoding.DecodeString(next)
+	require.NoError(t, err)
+	output := w.Body.String() + w2.Body.String() + string(cursor) + fmt.Sprint(w.Header(), w2.Header())
+	for _, user := range []models.User{minor, adult, hidden} {
+		require.NotContains(t, output, user.Email)
+		require.NotContains(t, output, user.Username)
+		require.NotContains(t, output, user.ID.Hex())
+	}
+	for _, forbidden := range []string{"Juan", "2013", "1990", "email", "username", "userId", "birth"} {
+		require.NotContains(t, output, forbidden)
+	}
+
+	require.NoError(t, json.Unmarshal(w2.Body.Bytes(), &second))
+	entries := append(first.Items, second.Items...)
+	require.Len(t, entries, 2)
+	publicNames := []string{entries[0].DisplayName, entries[1].DisplayName}
+	require.ElementsMatch(t, []string{models.Pseudonym(minor.ID), "Profe Ana"}, publicNames)
+	for _, e := range entries {
+		if e.DisplayName == models.Pseudonym(minor.ID) {
+			require.Equal(t, "cohete", e.Avatar)
+		}
+	}
+
+	code = api.do("GET", "/missions/leaderboard/me", hiddenTokens.Token, nil, nil)
+	require.Equal(t, http.StatusNotFound, code)
+	var position handlers.LeaderboardPosition
+	code = api.do("GET", "/missions/leaderboard/me", minorTokens.Token, nil, &position)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, position.Entries, 2)
+	for _, e := range position.Entries {
+		require.Equal(t, e.DisplayName == models.Pseudonym(minor.ID), e.Me)
+	}
+}
+
+func TestClassroomEnrollmentAndTeacherDashboard(t *testing.T) {
+	api := newTestAPI(t)
+	teacherUser := api.createUser("docente", models.RoleTeacher)
+	teacher := api.login(teacherUser)
+	otherTeacher := api.login(api.createUser("otra", models.RoleTeacher))
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	ana := api.createUser("ana", models.RoleStudent)
+	beto := api.createUser("beto", models.RoleStudent)
+	anaTokens := api.login(ana)
+	betoTokens := api.login(beto)
+	outsider := api.login(api.createUser("ajeno", models.RoleStudent))
+
+	// Solo los docentes crean clases
+	require.Equal(t, http.StatusForbidden, api.do("POST", "/classrooms", anaTokens.Token, gin.H{"name": "Mi clase"}, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("POST", "/classrooms", teacher.Token, gin.H{"name": "   "}, nil))
+	var classroom models.Classroom
+	require.Equal(t, http.StatusCreated, api.do("POST", "/classrooms", teacher.Token, gin.H{"name": "Ciencias 5B"}, &classroom))
+	require.Len(t, classroom.JoinCode, models.JoinCodeLength)
+	require.Equal(t, teacherUser.ID, classroom.TeacherID)
+
+	// El código admite minúsculas y guiones; inscribirse dos veces no duplica
+	code := strings.ToLower(classroom.JoinCode[:4] + "-" + classroom.JoinCode[4:])
+	var joined handlers.StudentClassroom
+	require.Equal(t, http.StatusOK, api.do("POST", "/classrooms/join", anaTokens.Token, gin.H{"join_code": code}, &joined))
+	require.Equal(t, classroom.ID, joined.ID)
+	require.Equal(t, http.StatusOK, api.do("POST", "/classrooms/join", anaTokens.Token, gin.H{"join_code": classroom.JoinCode}, nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/classrooms/join", betoTokens.Token, gin.H{"join_code": classroom.JoinCode}, nil))
+	require.Equal(t, http.StatusNotFound, api.do("POST", "/classrooms/join", outsider.Token, gin.H{"join_code": "ZZZZZZZZ"}, nil))
+	require.Equal(t, http.StatusForbidden, api.do("POST", "/classrooms/join", teacher.Token, gin.H{"join_code": classroom.JoinCode}, nil))
+
+	var mine handlers.Page[map[string]interface{}]
+	require.Equal(t, http.StatusOK, api.do("GET", "/me/classrooms", anaTokens.Token, nil, &mine))
+	require.Len(t, mine.Items, 1)
+	require.Equal(t, "Ciencias 5B", mine.Items[0]["name"])
+	require.NotContains(t, mine.Items[0], "joinCode")
+
+	// ana completa una misión y deja otra iniciada; beto no empieza ninguna
+	first := api.createMission("Primera")
+	second := api.createMission("Segunda")
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", anaTokens.Token, gin.H{"mission_id": first.ID.Hex()}, nil))
+	api.clock.Advance(10 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", anaTokens.Token, gin.H{"mission_id": first.ID.Hex()}, nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", anaTokens.Token, gin.H{"mission_id": second.ID.Hex()}, nil))
+
+	dashboardPath := "/classrooms/" + classroom.ID.Hex() + "/dashboard"
+	var dashboard handlers.ClassroomDashboard
+	require.Equal(t, http.StatusOK, api.do("GET", dashboardPath, teacher.Token, nil, &dashboard))
+	require.Len(t, dashboard.Students, 2)
+	require.Equal(t, 2, dashboard.Summary.Students)
+	require.Equal(t, 1, dashboard.Summary.TotalCompleted)
+	require.Equal(t, 1, dashboard.Summary.ActiveCount)
+	require.InDelta(t, 0.5, dashboard.Summary.AverageCompleted, 0.001)
+	require.InDelta(t, float64(10*time.Minute/time.Millisecond), dashboard.Summary.AverageDuration, 1)
+	require.InDelta(t, 25.0, dashboard.Summary.AverageProgress, 0.001)
+	anaSummary := dashboard.Students[0]
+	require.Equal(t, ana.ID, anaSummary.StudentID)
+	require.Equal(t, 1, anaSummary.TotalCompleted)
+	require.Equal(t, models.DefaultXPReward, anaSummary.XP)
+	require.NotNil(t, anaSummary.LastCompletedAt)
+	require.Nil(t, dashboard.Students[1].LastCompletedAt)
+
+	studentPath := "/classrooms/" + classroom.ID.Hex() + "/students/" + ana.ID.Hex()
+	var report handlers.StudentReport
+	require.Equal(t, http.StatusOK, api.do("GET", studentPath, teacher.Token, nil, &report))
+	require.Len(t, report.ActiveMissions, 1)
+	require.Equal(t, second.ID, report.ActiveMissions[0].MissionID)
+	require.Len(t, report.CompletedMissions, 1)
+
+	// Otros docentes y los estudiantes no ven el tablero; los administradores sí
+	require.Equal(t, http.StatusNotFound, api.do("GET", dashboardPath, otherTeacher.Token, nil, nil))
+	require.Equal(t, http.StatusForbidden, api.do("GET", dashboardPath, anaTokens.Token, nil, nil))
+	require.Equal(t, http.StatusOK, api.do("GET", dashboardPath, admin.Token, nil, nil))
+	require.Equal(t, http.StatusNotFound, api.do("GET", "/classrooms/"+classroom.ID.Hex()+"/students/"+primitive.NewObjectID().Hex(), teacher.Token, nil, nil))
+
+	// beto abandona la clase y el docente da de baja a ana
+	require.Equal(t, http.StatusOK, api.do("DELETE", "/me/classrooms/"+classroom.ID.Hex(), betoTokens.Token, nil, nil))
+	require.Equal(t, http.StatusNotFound, api.do("DELETE", "/me/classrooms/"+classroom.ID.Hex(), betoTokens.Token, nil, nil))
+	require.Equal(t, http.StatusOK, api.do("DELETE", studentPath, teacher.Token, nil, nil))
+	require.Equal(t, http.StatusOK, api.do("GET", dashboardPath, teacher.Token, nil, &dashboard))
+	require.Empty(t, dashboard.Students)
+
+	var classrooms handlers.Page[models.Classroom]
+	require.Equal(t, http.StatusOK, api.do("GET", "/classrooms", teacher.Token, nil, &classrooms))
+	require.Len(t, classrooms.Items, 1)
+	require.Equal(t, http.StatusOK, api.do("GET", "/classrooms", otherTeacher.Token, nil, &classrooms))
+	require.Empty(t, classrooms.Items)
+}
+
+func TestAssignmentsWithDueDates(t *testing.T) {
+	api := newTestAPI(t)
+	teacher := api.login(api.createUser("docente", models.RoleTeacher))
+	otherTeacher := api.login(api.createUser("otra", models.RoleTeacher))
+	ana := api.createUser("ana", models.RoleStudent)
+	beto := api.createUser("beto", models.RoleStudent)
+	carla := api.createUser("carla", models.RoleStudent)
+	anaTokens := api.login(ana)
+	betoTokens := api.login(beto)
+	carlaTokens := api.login(carla)
+	outsider := api.createUser("ajeno", models.RoleStudent)
+
+	var classroom models.Classroom
+	require.Equal(t, http.StatusCreated, api.do("POST", "/classrooms", teacher.Token, gin.H{"name": "Ciencias"}, &classroom))
+	for _, tokens := range []handlers.TokenResponse{anaTokens, betoTokens, carlaTokens} {
+		require.Equal(t, http.StatusOK, api.do("POST", "/classrooms/join", tokens.Token, gin.H{"join_code": classroom.JoinCode}, nil))
+	}
+
+	homework := api.createMission("Tarea")
+	extra := api.createMission("Refuerzo")
+	later := api.createMission("Próxima semana")
+	api.createMission("Sin asignar")
+
+	now := api.clock.Now()
+	var assignment models.Assignment
+	require.Equal(t, http.StatusCreated, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id":   homework.ID.Hex(),
+		"classroom_id": classroom.ID.Hex(),
+		"due_at":       now.Add(time.Hour),
+	}, &assignment))
+	// Refuerzo solo para beto, y otra misión que se abre la semana próxima
+	require.Equal(t, http.StatusCreated, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id": extra.ID.Hex(),
+		"student_id": beto.ID.Hex(),
+		"due_at":     now.Add(48 * time.Hour),
+	}, nil))
+	require.Equal(t, http.StatusCreated, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id":   later.ID.Hex(),
+		"classroom_id": classroom.ID.Hex(),
+		"opens_at":     now.AddDate(0, 0, 7),
+		"due_at":       now.AddDate(0, 0, 14),
+	}, nil))
+
+	// Validaciones y permisos
+	require.Equal(t, http.StatusBadRequest, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id": homework.ID.Hex(), "classroom_id": classroom.ID.Hex(), "student_id": ana.ID.Hex(), "due_at": now.Add(time.Hour),
+	}, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id": homework.ID.Hex(), "classroom_id": classroom.ID.Hex(), "due_at": now.Add(-time.Hour),
+	}, nil))
+	require.Equal(t, http.StatusNotFound, api.do("POST", "/assignments", otherTeacher.Token, gin.H{
+		"mission_id": homework.ID.Hex(), "classroom_id": classroom.ID.Hex(), "due_at": now.Add(time.Hour),
+	}, nil))
+	require.Equal(t, http.StatusNotFound, api.do("POST", "/assignments", teacher.Token, gin.H{
+		"mission_id": homework.ID.Hex(), "student_id": outsider.ID.Hex(), "due_at": now.Add(time.Hour),
+	}, nil))
+	require.Equal(t, http.StatusForbidden, api.do("POST", "/assignments", anaTokens.Token, gin.H{
+		"mission_id": homework.ID.Hex(), "classroom_id": classroom.ID.Hex(), "due_at": now.Add(time.Hour),
+	}, nil))
+
+	titles := func(path, token string) []string {
+		var catalog handlers.MissionCatalog
+		require.Equal(t, http.StatusOK, api.do("GET", path, token, nil, &catalog))
+		var out []string
+		for _, m := range catalog.Items {
+			out = append(out, m.Title)
+		}
+		return out
+	}
+	// Los estudiantes con asignaciones abiertas ven por defecto solo esas
+	// misiones; assigned=false devuelve todo el catálogo
+	require.Equal(t, []string{"Tarea"}, titles("/missions/all", anaTokens.Token))
+	require.ElementsMatch(t, []string{"Tarea", "Refuerzo"}, titles("/missions/all", betoTokens.Token))
+	require.ElementsMatch(t, []string{"Tarea", "Refuerzo"}, titles("/missions/all?assigned=true", betoTokens.Token))
+	require.Len(t, titles("/missions/all?assigned=false", anaTokens.Token), 4)
+	// Sin asignaciones, el catálogo completo salvo que se pidan solo las asignadas
+	outsiderTokens := api.login(outsider)
+	require.Len(t, titles("/missions/all", outsiderTokens.Token), 4)
+	require.Empty(t, titles("/missions/all?assigned=true", outsiderTokens.Token))
+	require.Len(t, titles("/missions/all", teacher.Token), 4)
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/all?assigned=quizas", anaTokens.Token, nil, nil))
+
+	// ana completa a tiempo; beto empieza a tiempo pero termina tarde; carla no empieza
+	body := gin.H{"mission_id": homework.ID.Hex()}
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", anaTokens.Token, body, nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", betoTokens.Token, body, nil))
+	api.clock.Advance(30 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", anaTokens.Token, body, nil))
+
+	reportPath := "/assignments/" + assignment.ID.Hex() + "/report"
+	var report handlers.AssignmentReport
+	require.Equal(t, http.StatusOK, api.do("GET", reportPath, teacher.Token, nil, &report))
+	require.Equal(t, handlers.AssignmentSummary{Students: 3, NotStarted: 1, InProgress: 1, Completed: 1, OnTime: 1}, report.Summary)
+
+	api.clock.Advance(time.Hour)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", betoTokens.Token, body, nil))
+
+	require.Equal(t, http.StatusOK, api.do("GET", reportPath, teacher.Token, nil, &report))
+	require.Equal(t, handlers.AssignmentSummary{Students: 3, NotStarted: 1, Completed: 2, OnTime: 1, Late: 2}, report.Summary)
+	states := map[primitive.ObjectID]models.AssignmentState{}
+	for _, st := range report.Students {
+		states[st.StudentID] = st.AssignmentState
+	}
+	require.True(t, states[ana.ID].OnTime)
+	require.False(t, states[ana.ID].Late)
+	require.Equal(t, models.AssignmentCompleted, states[beto.ID].Status)
+	require.True(t, states[beto.ID].Late)
+	require.Equal(t, models.AssignmentNotStarted, states[carla.ID].Status)
+	require.True(t, states[carla.ID].Late)
+
+	// Quiénes no empezaron
+	require.Equal(t, http.StatusOK, api.do("GET", reportPath+"?status=not_started", teacher.Token, nil, &report))
+	require.Len(t, report.Students, 1)
+	require.Equal(t, "carla", report.Students[0].Username)
+	require.Equal(t, http.StatusBadRequest, api.do("GET", reportPath+"?status=otro", teacher.Token, nil, nil))
+	require.Equal(t, http.StatusNotFound, api.do("GET", reportPath, otherTeacher.Token, nil, nil))
+
+	var mine handlers.Page[handlers.StudentAssignment]
+	require.Equal(t, http.StatusOK, api.do("GET", "/me/assignments", betoTokens.Token, nil, &mine))
+	require.Len(t, mine.Items, 2)
+	require.Equal(t, "Tarea", mine.Items[0].MissionTitle)
+	require.True(t, mine.Items[0].Late)
+	require.Equal(t, "Refuerzo", mine.Items[1].MissionTitle)
+	require.Equal(t, models.AssignmentNotStarted, mine.Items[1].Status)
+	require.False(t, mine.Items[1].Late)
+
+	// La página siguiente continúa por fecha de entrega
+	var firstPage, secondPage handlers.Page[handlers.StudentAssignment]
+	require.Equal(t, http.StatusOK, api.do("GET", "/me/assignments?limit=1", betoTokens.Token, nil, &firstPage))
+	require.Len(t, firstPage.Items, 1)
+	require.NotEmpty(t, firstPage.NextCursor)
+	require.Equal(t, http.StatusOK, api.do("GET", "/me/assignments?limit=1&cursor="+firstPage.NextCursor, betoTokens.Token, nil, &secondPage))
+	require.Equal(t, "Refuerzo", secondPage.Items[0].MissionTitle)
+	require.Empty(t, secondPage.NextCursor)
+
+	var listed handlers.Page[models.Assignment]
+	require.Equal(t, http.StatusOK, api.do("GET", "/assignments", teacher.Token, nil, &listed))
+	require.Len(t, listed.Items, 3)
+}
+
+// overviewCounts returns how many times each mission title was completed
+// according to /missions/overview
+func (a *testAPI) overviewCounts(tenantID string) map[string]int {
+	a.t.Helper()
+	var overview struct {
+		AvgCompletionTimes []struct {
+			Count   int            `json:"count"`
+			Mission models.Mission `json:"mission"`
+		} `json:"avgCompletionTimes"`
+	}
+	require.Equal(a.t, http.StatusOK, a.doIn(tenantID, "GET", "/missions/overview", "", nil, &overview))
+	counts := map[string]int{}
+	for _, m := range overview.AvgCompletionTimes {
+		counts[m.Mission.Title] = m.Count
+	}
+	return counts
+}
+
+func TestTenantsAreIsolated(t *testing.T) {
+	api := newTestAPI(t)
+	platformAdmin := api.login(api.createUser("plataforma", models.RoleAdmin))
+
+	// Solo la organización por defecto da de alta organizaciones
+	const north = "colegio-norte"
+	var tenant models.Tenant
+	require.Equal(t, http.StatusCreated, api.do("POST", "/admin/tenants", platformAdmin.Token, gin.H{"id": north, "name": "Colegio Norte"}, &tenant))
+	require.Equal(t, north, tenant.ID)
+	require.Equal(t, http.StatusConflict, api.do("POST", "/admin/tenants", platformAdmin.Token, gin.H{"id": north, "name": "Otro"}, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("POST", "/admin/tenants", platformAdmin.Token, gin.H{"id": "Colegio Sur", "name": "Sur"}, nil))
+	require.Equal(t, http.StatusNotFound, api.doIn("colegio-sur", "GET", "/missions/leaderboard", "", nil, nil))
+
+	northAdmin := api.loginIn(north, api.createUserIn(north, "directora", models.RoleAdmin))
+	require.Equal(t, http.StatusForbidden, api.do("GET", "/admin/tenants", northAdmin.Token, nil, nil))
+
+	// Misiones: una global, una de cada organización
+	var created struct {
+		Mission models.Mission `json:"mission"`
+	}
+	require.Equal(t, http.StatusCreated, api.do("POST", "/admin/missions/create", platformAdmin.Token, gin.H{"title": "Global", "description": "Para todos", "global": true}, &created))
+	global := created.Mission
+	local := api.createMission("Solo ExploraX")
+	require.Equal(t, http.StatusForbidden, api.do("POST", "/admin/missions/create", northAdmin.Token, gin.H{"title": "Otra global", "description": "No", "global": true}, nil))
+	require.Equal(t, http.StatusCreated, api.do("POST", "/admin/missions/create", northAdmin.Token, gin.H{"title": "Solo Norte", "description": "Norte"}, &created))
+	northMission := created.Mission
+
+	// El registro y el login se hacen en la organización de la cabecera
+	require.Equal(t, http.StatusCreated, api.doIn(north, "POST", "/auth/register", "", gin.H{
+		"username": "nora", "email": "nora@example.com", "password": "secret123", "birth_date": "2000-01-01",
+	}, nil))
+	_, err := api.store.FindUserByEmail("nora@example.com")
+	require.ErrorIs(t, err, database.ErrNotFound)
+	require.Equal(t, http.StatusUnauthorized, api.do("POST", "/auth/login", "", gin.H{"email": "nora@example.com", "password": "secret123"}, nil))
+	var nora handlers.TokenResponse
+	require.Equal(t, http.StatusOK, api.doIn(north, "POST", "/auth/login", "", gin.H{"email": "nora@example.com", "password": "secret123"}, &nora))
+	emma := api.login(api.createUser("emma", models.RoleStudent))
+
+	// El token no sirve con la cabecera de otra organización
+	require.Equal(t, http.StatusForbidden, api.doIn(models.DefaultTenantID, "GET", "/missions/all", nora.Token, nil, nil))
+
+	var catalog handlers.MissionCatalog
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/all", nora.Token, nil, &catalog))
+	var titles []string
+	for _, m := range catalog.Items {
+		titles = append(titles, m.Title)
+	}
+	require.ElementsMatch(t, []string{"Global", "Solo Norte"}, titles)
+	require.Equal(t, http.StatusNotFound, api.do("GET", "/mission/"+local.ID.Hex(), nora.Token, nil, nil))
+	require.Equal(t, http.StatusNotFound, api.do("POST", "/missions/start", nora.Token, gin.H{"mission_id": local.ID.Hex()}, nil))
+	require.Equal(t, http.StatusNotFound, api.do("GET", "/mission/"+northMission.ID.Hex(), emma.Token, nil, nil))
+	// Las misiones globales solo se editan desde su organización
+	require.Equal(t, http.StatusNotFound, api.do("PATCH", "/admin/missions/"+global.ID.Hex(), northAdmin.Token, gin.H{"title": "Mía"}, nil))
+
+	complete := func(tokens handlers.TokenResponse, mission models.Mission) {
+		body := gin.H{"mission_id": mission.ID.Hex()}
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, nil))
+	}
+	complete(emma, global)
+	complete(emma, local)
+	complete(nora, global)
+	complete(nora, northMission)
+
+	// Cada ranking muestra solo a los usuarios de su organización
+	var board handlers.Page[handlers.LeaderboardEntry]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/leaderboard", "", nil, &board))
+	require.Len(t, board.Items, 1)
+	require.Equal(t, 2, board.Items[0].CompletedCount)
+	require.Equal(t, http.StatusOK, api.doIn(north, "GET", "/missions/leaderboard", "", nil, &board))
+	require.Len(t, board.Items, 1)
+
+	var position handlers.LeaderboardPosition
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/leaderboard/me", nora.Token, nil, &position))
+	require.Equal(t, 1, position.Rank)
+	require.Len(t, position.Entries, 1)
+	require.Equal(t, board.Items[0].DisplayName, position.Entries[0].DisplayName)
+
+	// El resumen cuenta solo los progresos de la organización, también en
+	// la misión global
+	require.Equal(t, map[string]int{"Global": 1, "Solo ExploraX": 1}, api.overviewCounts(""))
+	require.Equal(t, map[string]int{"Global": 1, "Solo Norte": 1}, api.overviewCounts(north))
+
+	// Reconstruir el ranking de una organización no toca el de las demás
+	require.NoError(t, api.store.RebuildLeaderboard())
+	require.Equal(t, http.StatusOK, api.doIn(north, "GET", "/missions/leaderboard", "", nil, &board))
+	require.Len(t, board.Items, 1)
+
+	// La misión global tiene progreso en otra organización: no se puede borrar
+	require.Equal(t, http.StatusConflict, api.do("DELETE", "/admin/missions/"+global.ID.Hex(), platformAdmin.Token, nil, nil))
+}
+
+func TestMissionPrerequisitesAndPaths(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+	sun := api.createMission("Sol")
+	mercury := api.createMission("Mercurio")
+	venus := api.createMission("Venus")
+
+	requires := func(mission models.Mission, prerequisites ...models.Mission) int {
+		ids := []string{}
+		for _, p := range prerequisites {
+			ids = append(ids, p.ID.Hex())
+		}
+		return api.do("PATCH", "/admin/missions/"+mission.ID.Hex(), admin.Token, gin.H{"prerequisites": ids}, nil)
+	}
+	require.Equal(t, http.StatusOK, requires(mercury, sun))
+	require.Equal(t, http.StatusOK, requires(venus, mercury))
+
+	// Cerrar un ciclo, directo o indirecto, se rechaza
+	var cycle apierror.Response
+	code := api.do("PATCH", "/admin/missions/"+sun.ID.Hex(), admin.Token, gin.H{"prerequisites": []string{venus.ID.Hex()}}, &cycle)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, apierror.ValidationFailed, cycle.Error.Code)
+	require.Len(t, cycle.Error.Details, 1)
+	detail := cycle.Error.Details[0]
+	require.Equal(t, "prerequisites", detail.Field)
+	require.Equal(t, apierror.PrerequisiteCycle, detail.Code)
+	require.Equal(t, []interface{}{sun.ID.Hex(), venus.ID.Hex(), mercury.ID.Hex(), sun.ID.Hex()}, detail.Params["missionIds"])
+	require.Contains(t, detail.Message, "Sol → Venus → Mercurio → Sol")
+	require.Equal(t, http.StatusBadRequest, requires(sun, sun))
+	code = api.do("PATCH", "/admin/missions/"+sun.ID.Hex(), admin.Token, gin.H{"prerequisites": []string{primitive.NewObjectID().Hex()}}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// Una misión no puede aparecer antes que sus prerrequisitos en la ruta
+	chapters := []gin.H{
+		{"title": "Inicio", "mission_ids": []string{sun.ID.Hex()}},
+		{"title": "Planetas", "mission_ids": []string{venus.ID.Hex(), mercury.ID.Hex()}},
+	}
+	code = api.do("POST", "/admin/paths", admin.Token, gin.H{"title": "Sistema solar", "chapters": chapters}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	chapters[1]["mission_ids"] = []string{mercury.ID.Hex(), venus.ID.Hex()}
+	var path models.LearningPath
+	code = api.do("POST", "/admin/paths", admin.Token, gin.H{"title": "Sistema solar", "chapters": chapters}, &path)
+	require.Equal(t, http.StatusCreated, code)
+	code = api.do("POST", "/admin/paths", student.Token, gin.H{"title": "Sistema solar", "chapters": chapters}, nil)
+	require.Equal(t, http.StatusForbidden, code)
+
+	states := func() handlers.LearningPathView {
+		var view handlers.LearningPathView
+		code := api.do("GET", "/paths/"+path.ID.Hex(), student.Token, nil, &view)
+		require.Equal(t, http.StatusOK, code)
+		return view
+	}
+	view := states()
+	require.Equal(t, 3, view.Total)
+	require.Equal(t, models.NodeUnlocked, view.Chapters[0].Nodes[0].State)
+	require.Equal(t, models.NodeLocked, view.Chapters[1].Nodes[0].State)
+	require.Equal(t, []primitive.ObjectID{sun.ID}, view.Chapters[1].Nodes[0].MissingPrerequisites)
+	require.Equal(t, models.NodeLocked, view.Chapters[1].Nodes[1].State)
+
+	var locked apierror.Response
+	code = api.do("POST", "/missions/start", student.Token, gin.H{"mission_id": mercury.ID.Hex()}, &locked)
+	require.Equal(t, http.StatusConflict, code)
+	require.Equal(t, apierror.MissionLocked, locked.Error.Code)
+	require.Equal(t, []interface{}{sun.ID.Hex()}, locked.Error.Params["prerequisites"])
+
+	for _, action := range []string{"start", "complete"} {
+		code = api.do("POST", "/missions/"+action, student.Token, gin.H{"mission_id": sun.ID.Hex()}, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+	view = states()
+	require.Equal(t, 1, view.Completed)
+	require.Equal(t, models.NodeCompleted, view.Chapters[0].Nodes[0].State)
+	require.Equal(t, models.NodeUnlocked, view.Chapters[1].Nodes[0].State)
+	require.Equal(t, models.NodeLocked, view.Chapters[1].Nodes[1].State)
+
+	// Un prerrequisito archivado deja de bloquear y sale de la ruta
+	code = api.do("PATCH", "/admin/missions/"+mercury.ID.Hex(), admin.Token, gin.H{"archived": true}, nil)
+	require.Equal(t, http.StatusOK, code)
+	view = states()
+	require.Equal(t, 2, view.Total)
+	require.Equal(t, models.NodeUnlocked, view.Chapters[1].Nodes[0].State)
+	code = api.do("POST", "/missions/start", student.Token, gin.H{"mission_id": venus.ID.Hex()}, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var paths handlers.Page[models.LearningPath]
+	code = api.do("GET", "/paths", student.Token, nil, &paths)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, paths.Items, 1)
+
+	code = api.do("DELETE", "/admin/paths/"+path.ID.Hex(), admin.Token, nil, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = api.do("GET", "/paths/"+path.ID.Hex(), student.Token, nil, nil)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestMissionCatalogFiltersSortsAndFacets(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+
+	create := func(body gin.H) {
+		body["description"] = body["title"]
+		require.Equal(t, http.StatusCreated, api.do("POST", "/admin/missions/create", admin.Token, body, nil))
+		api.clock.Advance(time.Minute)
+	}
+	create(gin.H{"title": "Marte", "category": "Astronomia", "tags": []string{"Planetas", "marte "}, "difficulty": 3, "grade_level": 5, "estimated_minutes": 30})
+	create(gin.H{"title": "Luna", "category": "astronomia", "tags": []string{"satelites"}, "difficulty": 1, "grade_level": 3, "estimated_minutes": 10})
+	create(gin.H{"title": "Células", "category": "biologia", "tags": []string{"planetas"}, "difficulty": 2, "grade_level": 5})
+	create(gin.H{"title": "Sin clasificar"})
+
+	require.Equal(t, http.StatusBadRequest, api.do("POST", "/admin/missions/create", admin.Token, gin.H{
+		"title": "Imposible", "description": "Imposible", "difficulty": 6,
+	}, nil))
+
+	list := func(query string) handlers.MissionCatalog {
+		var catalog handlers.MissionCatalog
+		require.Equal(t, http.StatusOK, api.do("GET", "/missions/all"+query, student.Token, nil, &catalog))
+		return catalog
+	}
+	titles := func(catalog handlers.MissionCatalog) []string {
+		out := []string{}
+		for _, m := range catalog.Items {
+			out = append(out, m.Title)
+		}
+		return out
+	}
+
+	all := list("")
+	require.Equal(t, []string{"Marte", "Luna", "Células", "Sin clasificar"}, titles(all))
+	require.Equal(t, 4, all.Total)
+	require.Equal(t, map[string]int{"astronomia": 2, "biologia": 1}, all.Facets.Categories)
+	require.Equal(t, map[string]int{"planetas": 2, "marte": 1, "satelites": 1}, all.Facets.Tags)
+	require.Equal(t, map[int]int{3: 1, 1: 1, 2: 1}, all.Facets.Difficulties)
+	require.Equal(t, map[int]int{5: 2, 3: 1}, all.Facets.GradeLevels)
+
+	// Las facetas cuentan solo las misiones filtradas
+	astronomy := list("?category=Astronomia")
+	require.Equal(t, []string{"Marte", "Luna"}, titles(astronomy))
+	require.Equal(t, map[int]int{5: 1, 3: 1}, astronomy.Facets.GradeLevels)
+
+	require.Equal(t, []string{"Marte"}, titles(list("?tags=planetas,marte")))
+	require.Equal(t, []string{"Marte", "Células"}, titles(list("?grade=5")))
+	require.Equal(t, []string{"Luna"}, titles(list("?max_minutes=15")))
+	require.Equal(t, []string{"Células"}, titles(list("?difficulty=2")))
+	require.Equal(t, []string{"Sin clasificar", "Luna", "Células", "Marte"}, titles(list("?sort=difficulty")))
+	require.Equal(t, []string{"Marte", "Células", "Luna", "Sin clasificar"}, titles(list("?sort=-grade")))
+	require.Equal(t, []string{"Sin clasificar", "Marte", "Luna", "Células"}, titles(list("?sort=-title")))
+
+	for _, query := range []string{"?sort=xp", "?difficulty=9", "?grade=cero", "?max_minutes=-5"} {
+		require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/all"+query, student.Token, nil, nil), query)
+	}
+}
+
+func TestListEndpointsPaginateSortAndSelectFields(t *testing.T) {
+	api := newTestAPI(t)
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	// get devuelve el cuerpo crudo y las cabeceras de una lista
+	get := func(path string) (int, []byte, http.Header) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, req)
+		return w.Code, w.Body.Bytes(), w.Header()
+	}
+	progressIDs := func(path string) ([]primitive.ObjectID, http.Header) {
+		code, body, header := get(path)
+		require.Equal(t, http.StatusOK, code, string(body))
+		var progress handlers.Page[models.MissionProgress]
+		require.NoError(t, json.Unmarshal(body, &progress))
+		require.Equal(t, progress.NextCursor, header.Get("X-Next-Cursor"))
+		ids := []primitive.ObjectID{}
+		for _, p := range progress.Items {
+			ids = append(ids, p.MissionID)
+		}
+		return ids, header
+	}
+
+	var missions []models.Mission
+	for _, title := range []string{"Marte", "Luna", "Saturno"} {
+		mission := api.createMission(title)
+		missions = append(missions, mission)
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, gin.H{"mission_id": mission.ID.Hex()}, nil))
+		api.clock.Advance(time.Minute)
+	}
+	marte, luna, saturno := missions[0].ID, missions[1].ID, missions[2].ID
+	for _, id := range []primitive.ObjectID{luna, marte} {
+		require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, gin.H{"mission_id": id.Hex()}, nil))
+		api.clock.Advance(time.Minute)
+	}
+
+	// Por defecto, de la iniciada más recientemente a la más antigua
+	page, header := progressIDs("/missions/progress?limit=2")
+	require.Equal(t, []primitive.ObjectID{saturno, luna}, page)
+	next := header.Get("X-Next-Cursor")
+	require.NotEmpty(t, next)
+	link := header.Get("Link")
+	require.True(t, strings.HasSuffix(link, `>; rel="next"`), link)
+	page, header = progressIDs(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+	require.Equal(t, []primitive.ObjectID{marte}, page)
+	require.Empty(t, header.Get("X-Next-Cursor"))
+
+	// El tamaño de página puede cambiar entre páginas, pero no el orden
+	page, _ = progressIDs("/missions/progress?limit=1&cursor=" + next)
+	require.Equal(t, []primitive.ObjectID{marte}, page)
+	for _, path := range []string{
+		"/missions/progress?sort=started&cursor=" + next,
+		"/missions/completed?cursor=" + next,
+		"/missions/progress?cursor=no-es-un-cursor",
+	} {
+		code, body, _ := get(path)
+		require.Equal(t, http.StatusBadRequest, code, path)
+		require.Contains(t, string(body), string(apierror.InvalidCursor), path)
+	}
+
+	page, _ = progressIDs("/missions/completed")
+	require.Equal(t, []primitive.ObjectID{marte, luna}, page)
+	page, _ = progressIDs("/missions/completed?sort=started")
+	require.Equal(t, []primitive.ObjectID{marte, luna}, page)
+	page, _ = progressIDs("/missions/completed?sort=ended")
+	require.Equal(t, []primitive.ObjectID{luna, marte}, page)
+	page, _ = progressIDs("/missions/active")
+	require.Equal(t, []primitive.ObjectID{saturno}, page)
+
+	// fields devuelve solo los campos pedidos de cada elemento
+	code, body, _ := get("/missions/active?fields=missionId,status")
+	require.Equal(t, http.StatusOK, code)
+	var selected handlers.Page[map[string]interface{}]
+	require.NoError(t, json.Unmarshal(body, &selected))
+	require.Equal(t, []map[string]interface{}{{"missionId": saturno.Hex(), "status": string(models.ProgressStarted)}}, selected.Items)
+	code, body, header = get("/missions/progress?fields=missionId,completionPercentage&sort=started&limit=1")
+	require.Equal(t, http.StatusOK, code)
+	require.JSONEq(t, `{"items":[{"missionId":"`+marte.Hex()+`","completionPercentage":100}],"nextCursor":"`+header.Get("X-Next-Cursor")+`"}`, string(body))
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?sort=title", "?sort=-", "?fields=password", "?fields=missionId,"} {
+		var apiErr apierror.Response
+		require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/progress"+query, tokens.Token, nil, &apiErr), query)
+		require.Equal(t, apierror.InvalidQueryParams, apiErr.Error.Code, query)
+	}
+
+	// El catálogo usa la misma forma, con el total de todas las misiones y
+	// las facetas
+	var catalog handlers.MissionCatalog
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/all?sort=title&limit=2", tokens.Token, nil, &catalog))
+	require.Len(t, catalog.Items, 2)
+	require.Equal(t, "Luna", catalog.Items[0].Title)
+	require.Equal(t, 3, catalog.Total)
+	require.NotEmpty(t, catalog.NextCursor)
+	var rest handlers.MissionCatalog
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/all?sort=title&limit=2&cursor="+catalog.NextCursor, tokens.Token, nil, &rest))
+	require.Len(t, rest.Items, 1)
+	require.Equal(t, "Saturno", rest.Items[0].Title)
+	require.Equal(t, 3, rest.Total)
+	require.Empty(t, rest.NextCursor)
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/all?sort=-title&cursor="+catalog.NextCursor, tokens.Token, nil, nil))
+
+	code, body, _ = get("/missions/all?fields=title&sort=-title&limit=1")
+	require.Equal(t, http.StatusOK, code)
+	var titles struct {
+		Items      []map[string]interface{} `json:"items"`
+		Total      int                      `json:"total"`
+		NextCursor string                   `json:"nextCursor"`
+	}
+	require.NoError(t, json.Unmarshal(body, &titles))
+	require.Equal(t, []map[string]interface{}{{"title": "Saturno"}}, titles.Items)
+	require.Equal(t, 3, titles.Total)
+	require.NotEmpty(t, titles.NextCursor)
+}
+
+func TestProgressExpandsMissionAndComputesFields(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+
+	landing := models.MissionStep{ID: primitive.NewObjectID(), Title: "Aterriza"}
+	mars := models.Mission{
+		ID: primitive.NewObjectID(), Title: "Explorar Marte", Description: "Recorre Marte", CreatedAt: api.clock.Now(),
+		Steps: []models.MissionStep{landing, {ID: primitive.NewObjectID(), Title: "Conduce el rover"}, {ID: primitive.NewObjectID(), Title: "Foto", Optional: true}},
+	}
+	require.NoError(t, api.store.InsertMission(mars))
+	require.Equal(t, http.StatusOK, api.do("PUT", "/admin/missions/"+mars.ID.Hex()+"/translations/en", admin.Token, gin.H{
+		"title": "Explore Mars", "description": "Travel across Mars",
+	}, nil))
+	moon := api.createMission("Luna")
+
+	body := func(mission models.Mission) gin.H { return gin.H{"mission_id": mission.ID.Hex()} }
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body(moon), nil))
+	api.clock.Advance(4 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body(moon), nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body(mars), nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/steps/complete", tokens.Token, gin.H{
+		"mission_id": mars.ID.Hex(), "step_id": landing.ID.Hex(),
+	}, nil))
+	api.clock.Advance(3 * time.Minute)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/pause", tokens.Token, body(mars), nil))
+	api.clock.Advance(time.Hour)
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/resume", tokens.Token, body(mars), nil))
+	api.clock.Advance(2 * time.Minute)
+
+	// Sin expand no se consulta la misión, así que tampoco hay avance en
+	// los pasos; el tiempo transcurrido incluye el tramo en curso pero no
+	// la pausa
+	var active handlers.Page[handlers.MissionProgressView]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/active", tokens.Token, nil, &active))
+	require.Len(t, active.Items, 1)
+	require.Nil(t, active.Items[0].Mission)
+	require.Nil(t, active.Items[0].StepsCompleted)
+	require.Nil(t, active.Items[0].StepsRequired)
+	require.Nil(t, active.Items[0].CompletionPercentage)
+	require.Equal(t, (3 * time.Minute).Milliseconds(), active.Items[0].ActiveDurationMs)
+	require.Equal(t, (5 * time.Minute).Milliseconds(), active.Items[0].ElapsedMs)
+
+	// Las misiones se devuelven en el idioma del usuario, incluso archivadas
+	require.Equal(t, http.StatusOK, api.do("PATCH", "/admin/missions/"+moon.ID.Hex(), admin.Token, gin.H{"archived": true}, nil))
+	var progress handlers.Page[handlers.MissionProgressView]
+	req := httptest.NewRequest("GET", "/missions/progress?expand=mission", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.Token)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	api.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &progress))
+	require.Len(t, progress.Items, 2)
+	require.Equal(t, mars.ID, progress.Items[0].MissionID)
+	require.Equal(t, "Explore Mars", progress.Items[0].Mission.Title)
+	require.Len(t, progress.Items[0].Mission.Steps, 3)
+	require.Equal(t, 1, *progress.Items[0].StepsCompleted)
+	require.Equal(t, 2, *progress.Items[0].StepsRequired)
+	require.InDelta(t, 50.0, *progress.Items[0].CompletionPercentage, 0.001)
+	require.Equal(t, "Luna", progress.Items[1].Mission.Title)
+	require.True(t, progress.Items[1].Mission.Archived)
+	require.InDelta(t, 100.0, *progress.Items[1].CompletionPercentage, 0.001)
+	require.Equal(t, (4 * time.Minute).Milliseconds(), progress.Items[1].ElapsedMs)
+
+	var completed handlers.Page[map[string]interface{}]
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/completed?expand=mission&fields=mission,elapsedMs", tokens.Token, nil, &completed))
+	require.Len(t, completed.Items, 1)
+	require.Len(t, completed.Items[0], 2)
+	require.Equal(t, "Luna", completed.Items[0]["mission"].(map[string]interface{})["title"])
+
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/progress?expand=steps", tokens.Token, nil, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/progress?expand=mission,", tokens.Token, nil, nil))
+}
+
+func TestSearchMissionsRanksHighlightsAndPaginates(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+
+	insert := func(m models.Mission) models.Mission {
+		m.ID = primitive.NewObjectID()
+		m.CreatedAt = api.clock.Now()
+		require.NoError(t, api.store.InsertMission(m))
+		return m
+	}
+	mars := insert(models.Mission{Title: "Explorar Marte", Description: "Recorre la superficie <b>roja</b>", Tags: []string{"planetas"}})
+	moon := insert(models.Mission{Title: "Misión a la Luna", Description: "Aterriza en el satélite natural y compáralo con Marte"})
+	insert(models.Mission{Title: "Células", Description: "Observa una célula al microscopio"})
+	insert(models.Mission{Title: "Marte archivado", Description: "Marte", Archived: true})
+	long := insert(models.Mission{
+		Title:       "Viaje largo",
+		Description: strings.Repeat("Una travesía muy extensa por el espacio profundo. ", 6) + "Al final aparece Saturno con sus anillos. " + strings.Repeat("Después el regreso dura meses. ", 6),
+	})
+
+	search := func(query string) ([]handlers.MissionSearchHit, string) {
+		req := httptest.NewRequest("GET", "/missions/search?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+student.Token)
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var hits handlers.Page[handlers.MissionSearchHit]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hits))
+		require.Equal(t, hits.NextCursor, w.Header().Get("X-Next-Cursor"))
+		return hits.Items, hits.NextCursor
+	}
+
+	// Una coincidencia en el título pesa más que en la descripción; las
+	// misiones archivadas no aparecen
+	hits, next := search("q=MARTE")
+	require.Len(t, hits, 2)
+	require.Empty(t, next)
+	require.Equal(t, mars.ID, hits[0].Mission.ID)
+	require.Equal(t, moon.ID, hits[1].Mission.ID)
+	require.Greater(t, hits[0].Score, hits[1].Score)
+	require.Equal(t, "Explorar <mark>Marte</mark>", hits[0].Highlights.Title)
+	require.Empty(t, hits[0].Highlights.Description)
+	require.Equal(t, "Aterriza en el satélite natural y compáralo con <mark>Marte</mark>", hits[1].Highlights.Description)
+
+	// Sin acentos y en plural también coincide
+	hits, _ = search("q=satelite")
+	require.Len(t, hits, 1)
+	require.Contains(t, hits[0].Highlights.Description, "<mark>satélite</mark>")
+	hits, _ = search("q=misiones")
+	require.Len(t, hits, 1)
+	require.Equal(t, "<mark>Misión</mark> a la Luna", hits[0].Highlights.Title)
+	hits, _ = search("q=planeta")
+	require.Len(t, hits, 1)
+	require.Equal(t, []string{"<mark>planetas</mark>"}, hits[0].Highlights.Tags)
+
+	// El texto fuera de las marcas se escapa
+	hits, _ = search("q=superficie")
+	require.Equal(t, "Recorre la <mark>superficie</mark> &lt;b&gt;roja&lt;/b&gt;", hits[0].Highlights.Description)
+
+	// Las descripciones largas se recortan alrededor de la coincidencia
+	hits, _ = search("q=saturno")
+	require.Equal(t, long.ID, hits[0].Mission.ID)
+	snippet := hits[0].Highlights.Description
+	require.True(t, strings.HasPrefix(snippet, "…"), snippet)
+	require.True(t, strings.HasSuffix(snippet, "…"), snippet)
+	require.Contains(t, snippet, "<mark>Saturno</mark>")
+	require.Less(t, len([]rune(snippet)), 200)
+
+	// Paginación con cursor
+	hits, next = search("q=marte&limit=1")
+	require.Len(t, hits, 1)
+	require.Equal(t, mars.ID, hits[0].Mission.ID)
+	require.NotEmpty(t, next)
+	hits, last := search("q=marte&limit=1&cursor=" + next)
+	require.Len(t, hits, 1)
+	require.Equal(t, moon.ID, hits[0].Mission.ID)
+	require.Empty(t, last)
+
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/search?q=luna&cursor="+next, student.Token, nil, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/search?q=de+la", student.Token, nil, nil))
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/missions/search", student.Token, nil, nil))
+	require.Equal(t, http.StatusUnauthorized, api.do("GET", "/missions/search?q=marte", "", nil, nil))
+}
+
+func TestMissionTranslationsAndLocaleNegotiation(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.login(api.createUser("admin", models.RoleAdmin))
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+
+	landing := models.MissionStep{ID: primitive.NewObjectID(), Title: "Aterriza", Description: "Elige dónde"}
+	rover := models.MissionStep{ID: primitive.NewObjectID(), Title: "Conduce el rover"}
+	mars := models.Mission{
+		ID: primitive.NewObjectID(), Title: "Explorar Marte", Description: "Recorre Marte",
+		Steps: []models.MissionStep{landing, rover}, CreatedAt: api.clock.Now(),
+	}
+	require.NoError(t, api.store.InsertMission(mars))
+	moon := api.createMission("Luna")
+
+	translate := func(mission models.Mission, locale string, body gin.H) int {
+		return api.do("PUT", "/admin/missions/"+mission.ID.Hex()+"/translations/"+locale, admin.Token, body, nil)
+	}
+	var saved models.Mission
+	require.Equal(t, http.StatusOK, api.do("PUT", "/admin/missions/"+mars.ID.Hex()+"/translations/en", admin.Token, gin.H{
+		"title": "Explore Mars", "description": " Travel across Mars ",
+		"steps": []gin.H{{"id": landing.ID.Hex(), "title": "Land"}},
+	}, &saved))
+	require.Equal(t, "Travel across Mars", saved.Translations["en"].Description)
+	require.Equal(t, http.StatusOK, translate(moon, "en", gin.H{"title": "Moon", "description": "Moon"}))
+
+	require.Equal(t, http.StatusBadRequest, translate(mars, "es", gin.H{"title": "Explorar"}), "idioma original")
+	require.Equal(t, http.StatusBadRequest, translate(mars, "fr", gin.H{"title": "Explorer"}))
+	require.Equal(t, http.StatusBadRequest, translate(mars, "pt", gin.H{}))
+	require.Equal(t, http.StatusBadRequest, translate(mars, "pt", gin.H{"steps": []gin.H{{"id": moon.ID.Hex(), "title": "Pousar"}}}))
+	require.Equal(t, http.StatusForbidden, api.do("PUT", "/admin/missions/"+mars.ID.Hex()+"/translations/pt", student.Token, gin.H{"title": "Explorar"}, nil))
+
+	get := func(acceptLanguage string) (models.Mission, string) {
+		req := httptest.NewRequest("GET", "/mission/"+mars.ID.Hex(), nil)
+		req.Header.Set("Authorization", "Bearer "+student.Token)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var mission models.Mission
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &mission))
+		return mission, w.Header().Get("Content-Language")
+	}
+
+	// Lo que no está traducido queda en el idioma original
+	mission, language := get("en-US,en;q=0.9,es;q=0.5")
+	require.Equal(t, "en", language)
+	require.Equal(t, "en", mission.Locale)
+	require.Equal(t, "Explore Mars", mission.Title)
+	require.Equal(t, []string{"Land", "Conduce el rover"}, []string{mission.Steps[0].Title, mission.Steps[1].Title})
+	require.Equal(t, "Elige dónde", mission.Steps[0].Description)
+	require.Nil(t, mission.Translations)
+
+	for _, header := range []string{"", "fr-FR", "es-GT"} {
+		mission, language = get(header)
+		require.Equal(t, "es", language, header)
+		require.Equal(t, "Explorar Marte", mission.Title, header)
+	}
+
+	// La preferencia del usuario gana sobre Accept-Language
+	require.Equal(t, http.StatusOK, api.do("PATCH", "/me/preferences", student.Token, gin.H{"locale": "pt"}, nil))
+	mission, language = get("en")
+	require.Equal(t, "pt", language)
+	require.Equal(t, "es", mission.Locale, "sin traducción al portugués")
+	require.Equal(t, "Explorar Marte", mission.Title)
+	require.Equal(t, http.StatusBadRequest, api.do("PATCH", "/me/preferences", student.Token, gin.H{"locale": "fr"}, nil))
+	require.Equal(t, http.StatusOK, api.do("PATCH", "/me/preferences", student.Token, gin.H{"locale": "en"}, nil))
+
+	var catalog handlers.MissionCatalog
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/all", student.Token, nil, &catalog))
+	require.Equal(t, []string{"Explore Mars", "Moon"}, []string{catalog.Items[0].Title, catalog.Items[1].Title})
+
+	// complete cuenta, por idioma, las misiones totalmente traducidas
+	complete := func(report handlers.Page[handlers.TranslationStatus]) map[string]int {
+		counts := map[string]int{}
+		for _, status := range report.Items {
+			if _, ok := counts[status.Locale]; !ok {
+				counts[status.Locale] = 0
+			}
+			if status.Complete {
+				counts[status.Locale]++
+			}
+		}
+		return counts
+	}
+	var report handlers.Page[handlers.TranslationStatus]
+	require.Equal(t, http.StatusOK, api.do("GET", "/admin/translations/missing?locale=en", admin.Token, nil, &report))
+	require.Len(t, report.Items, 2)
+	require.Equal(t, map[string]int{"en": 1}, complete(report))
+	for _, status := range report.Items {
+		if status.MissionID != mars.ID {
+			require.Empty(t, status.Fields)
+			continue
+		}
+		require.False(t, status.Complete)
+		require.Equal(t, []string{
+			"steps." + landing.ID.Hex() + ".description",
+			"steps." + rover.ID.Hex() + ".title",
+		}, status.Fields)
+	}
+
+	require.Equal(t, http.StatusOK, api.do("GET", "/admin/translations/missing", admin.Token, nil, &report))
+	require.Equal(t, map[string]int{"es": 2, "en": 1, "pt": 0}, complete(report))
+	require.Len(t, report.Items, 6)
+
+	// limit cuenta misiones: cada una trae una entrada por idioma
+	require.Equal(t, http.StatusOK, api.do("GET", "/admin/translations/missing?limit=1", admin.Token, nil, &report))
+	require.Len(t, report.Items, 3)
+	require.NotEmpty(t, report.NextCursor)
+	require.Equal(t, http.StatusBadRequest, api.do("GET", "/admin/translations/missing?locale=fr", admin.Token, nil, nil))
+
+	require.Equal(t, http.StatusOK, api.do("DELETE", "/admin/missions/"+mars.ID.Hex()+"/translations/en", admin.Token, nil, nil))
+	require.Equal(t, http.StatusNotFound, api.do("DELETE", "/admin/missions/"+mars.ID.Hex()+"/translations/en", admin.Token, nil, nil))
+	mission, _ = get("")
+	require.Equal(t, "Explorar Marte", mission.Title)
+}
+
+func TestErrorEnvelopeCodesAndLocalization(t *testing.T) {
+	api := newTestAPI(t)
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+
+	send := func(method, path, token, body string, headers map[string]string) (int, apierror.Error, http.Header) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, req)
+		var response apierror.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+		return w.Code, response.Error, w.Header()
+	}
+
+	// Los campos inválidos se nombran como en el JSON, en el idioma pedido
+	code, body, header := send("POST", "/auth/register", "", `{"username":"ana","email":"no-es-email"}`,
+		map[string]string{"Accept-Language": "en-US", middleware.RequestIDHeader: "app-42"})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, apierror.ValidationFailed, body.Code)
+	require.Equal(t, "Invalid data", body.Message)
+	require.Equal(t, "app-42", body.RequestID)
+	require.Equal(t, "app-42", header.Get(middleware.RequestIDHeader))
+	require.Equal(t, "en", header.Get("Content-Language"))
+	require.Equal(t, []apierror.FieldError{
+		{Field: "email", Code: apierror.InvalidEmail, Message: "Is not a valid email"},
+		{Field: "password", Code: apierror.Required, Message: "Is required"},
+	}, body.Details)
+
+	_, body, _ = send("POST", "/auth/register", "", `{"username":5}`, nil)
+	require.Equal(t, []apierror.FieldError{{
+		Field: "username", Code: apierror.InvalidType, Message: "Debe ser de tipo string",
+		Params: map[string]interface{}{"expected": "string"},
+	}}, body.Details)
+	_, body, _ = send("POST", "/auth/register", "", `{"username":`, nil)
+	require.Equal(t, apierror.InvalidJSON, body.Details[0].Code)
+
+	// Los errores de los middlewares también llevan código e ID de petición
+	code, body, header = send("GET", "/missions/progress", "", "", map[string]string{"Accept-Language": "pt-BR"})
+	require.Equal(t, http.StatusUnauthorized, code)
+	require.Equal(t, apierror.TokenMissing, body.Code)
+	require.Equal(t, "Nenhum token fornecido", body.Message)
+	require.Len(t, body.RequestID, 16)
+	require.Equal(t, body.RequestID, header.Get(middleware.RequestIDHeader))
+
+	code, body, _ = send("GET", "/mission/no-es-un-id", student.Token, "", nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, apierror.InvalidObjectID, body.Code)
+	require.Equal(t, "id no es un ID válido", body.Message)
+
+	// La preferencia del usuario gana sobre Accept-Language
+	require.Equal(t, http.StatusOK, api.do("PATCH", "/me/preferences", student.Token, gin.H{"locale": "en"}, nil))
+	code, body, _ = send("GET", "/mission/"+primitive.NewObjectID().Hex(), student.Token, "", map[string]string{"Accept-Language": "pt"})
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, apierror.MissionNotFound, body.Code)
+	require.Equal(t, "Mission not found", body.Message)
+
+	mission := api.createMission("Marte")
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", student.Token, gin.H{"mission_id": mission.ID.Hex()}, nil))
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", student.Token, gin.H{"mission_id": mission.ID.Hex()}, nil))
+	code, body, _ = send("POST", "/missions/pause", student.Token, `{"mission_id":"`+mission.ID.Hex()+`"}`, nil)
+	require.Equal(t, http.StatusConflict, code)
+	require.Equal(t, apierror.InvalidTransition, body.Code)
+	require.Equal(t, map[string]interface{}{"action": "pause", "status": "completada"}, body.Params)
+	require.Equal(t, `The mission is "completada" and does not allow this action`, body.Message)
+}
+
+// missionsDown is a store whose missions cannot be read
+type missionsDown struct{ database.Store }
+
+func (missionsDown) GetMissionByID(primitive.ObjectID) (*models.Mission, error) {
+	return nil, errors.New("conexión perdida")
+}
+
+func TestGetMissionByIDReportsStoreFailures(t *testing.T) {
+	api := newTestAPIWith(t, func(store database.Store) database.Store { return missionsDown{store} })
+	student := api.login(api.createUser("alumno", models.RoleStudent))
+
+	// Un fallo de la base de datos no se confunde con una misión inexistente
+	var body apierror.Response
+	code := api.do("GET", "/mission/"+primitive.NewObjectID().Hex(), student.Token, nil, &body)
+	require.Equal(t, http.StatusInternalServerError, code)
+	require.Equal(t, apierror.InternalError, body.Error.Code)
+}
+
+// xpCreditDown is a store that cannot update the users' XP while down is set
+type xpCreditDown struct {
+	database.Store
+	down *bool
+}
+
+func (s xpCreditDown) CreditPendingXP(userID primitive.ObjectID) (int, error) {
+	if *s.down {
+		return 0, errors.New("conexión perdida")
+	}
+	return s.Store.CreditPendingXP(userID)
+}
+
+func TestXPCreditedAfterUserUpdateFails(t *testing.T) {
+	down := true
+	api := newTestAPIWith(t, func(store database.Store) database.Store { return xpCreditDown{store, &down} })
+	tokens := api.login(api.createUser("alumno", models.RoleStudent))
+	mission := api.createMission("Órbitas")
+
+	// La misión se completa aunque no se pueda sumar la XP: queda pendiente
+	body := gin.H{"mission_id": mission.ID.Hex()}
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/start", tokens.Token, body, nil))
+	var result struct {
+		XPAwarded int `json:"xpAwarded"`
+	}
+	require.Equal(t, http.StatusOK, api.do("POST", "/missions/complete", tokens.Token, body, &result))
+	require.Equal(t, models.DefaultXPReward, result.XPAwarded)
+
+	var stats map[string]interface{}
+	require.Equal(t, http.StatusOK, api.do("GET", "/missions/statistics", tokens.Token, nil, &stats))
+	require.EqualValues(t, 0, stats["xp"])
+
+	// Al recuperarse se acredita una sola vez
+	down = false
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, api.do("GET", "/missions/statistics", tokens.Token, nil, &stats))
+		require.EqualValues(t, models.DefaultXPReward, stats["xp"])
+	}
+}
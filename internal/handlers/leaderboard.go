@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultLeaderboardLimit = 50
+	maxLeaderboardLimit     = 100
+	defaultNeighbours       = 2
+	maxNeighbours           = 10
+)
+
+// leaderboardFields son los campos que se pueden pedir en fields.
+var leaderboardFields = pagination.FieldsOf(LeaderboardEntry{})
+
+// LeaderboardEntry es la posición de un usuario en el ranking público. Solo
+// incluye su nombre público y su avatar: nunca el email, el nombre de usuario
+// ni identificadores. XP y CompletedCount cuentan solo el periodo consultado;
+// el nivel se calcula con toda la XP del usuario.
+type LeaderboardEntry struct {
+	Rank           int    `json:"rank" example:"1"`
+	DisplayName    string `json:"displayName" example:"Cometa Veloz 42"`
+	Avatar         string `json:"avatar" example:"cohete"`
+	XP             int    `json:"xp" example:"450"`
+	Level          int    `json:"level" example:"3"`
+	CompletedCount int    `json:"completedCount" example:"4"`
+	// Me marca la entrada del usuario autenticado en /missions/leaderboard/me
+	Me bool `json:"me,omitempty"`
+}
+
+// leaderboardKey es la clave de orden de la última entrada de una página, que
+// el cursor guarda para leer la siguiente con LeaderboardQuery.After. El
+// usuario, que solo desempata, va como los bytes del ObjectID y no como el
+// identificador hexadecimal que usan las demás rutas.
+type leaderboardKey struct {
+	XP             int       `json:"x"`
+	CompletedCount int       `json:"c"`
+	ReachedAt      time.Time `json:"t"`
+	User           []byte    `json:"u"`
+}
+
+func newLeaderboardKey(entry models.LeaderboardEntry) interface{} {
+	return leaderboardKey{
+		XP:             entry.XP,
+		CompletedCount: entry.CompletedCount,
+		ReachedAt:      entry.ReachedAt,
+		User:           entry.UserID[:],
+	}
+}
+
+func (k leaderboardKey) entry() (*models.LeaderboardEntry, bool) {
+	var userID primitive.ObjectID
+	if len(k.User) != len(userID) {
+		return nil, false
+	}
+	copy(userID[:], k.User)
+	return &models.LeaderboardEntry{
+		UserID:         userID,
+		XP:             k.XP,
+		CompletedCount: k.CompletedCount,
+		ReachedAt:      k.ReachedAt,
+	}, true
+}
+
+// LeaderboardPosition es la posición del usuario autenticado junto con sus
+// vecinos en el ranking.
+type LeaderboardPosition struct {
+	Period string `json:"period" example:"weekly"`
+	Rank   int    `json:"rank" example:"7"`
+	// Entries incluye al usuario, precedido y seguido por sus vecinos
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// leaderboardBucket lee el parámetro period y devuelve el ranking del periodo
+// actual; si no es válido responde 400 y devuelve false.
+func (s *Server) leaderboardBucket(c *gin.Context) (string, string, bool) {
+	period := c.DefaultQuery("period", models.PeriodAllTime)
+	bucket, ok := models.LeaderboardBucket(period, s.now())
+	if !ok {
+		fail(c, apierror.InvalidQuery(apierror.Field("period", apierror.UnknownValue).
+			With("allowed", strings.Join([]string{models.PeriodWeekly, models.PeriodMonthly, models.PeriodAllTime}, ", "))))
+		return "", "", false
+	}
+	return period, bucket, true
+}
+
+// queryInt lee un parámetro entero entre min y max, o def si no se envió. Si
+// no es válido responde 400 y devuelve false.
+func queryInt(c *gin.Context, name string, def, min, max int) (int, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		fail(c, apierror.InvalidQuery(apierror.Field(name, apierror.OutOfRange).With("min", min).With("max", max)))
+		return 0, false
+	}
+	return n, true
+}
+
+// recordLeaderboard suma la misión completada a los rankings. Como recordStreak,
+// registra los errores sin afectar la respuesta; los rankings afectados se
+// corrigen con cmd/rebuild-leaderboard.
+func (s *Server) recordLeaderboard(progress models.MissionProgress) {
+	if err := s.leaderboard.RecordLeaderboardCompletion(progress.UserID, progress.XPAwarded, progress.EndDate); err != nil {
+		s.logger.Printf("No se pudo actualizar el leaderboard con el progreso %s: %v", progress.ID.Hex(), err)
+	}
+}
+
+func (s *Server) newLeaderboardEntry(entry models.LeaderboardEntry, rank int) LeaderboardEntry {
+	return LeaderboardEntry{
+		Rank:           rank,
+		DisplayName:    entry.User.PublicName(s.now()),
+		Avatar:         entry.User.PublicAvatar(),
+		XP:             entry.XP,
+		Level:          s.levels.Progress(entry.User.XP).Level,
+		CompletedCount: entry.CompletedCount,
+	}
+}
+
+// GetLeaderboard godoc
+// @Summary Obtiene el ranking de usuarios
+// @Description Devuelve una página del ranking semanal, mensual o histórico de una organización, que se actualiza al completar cada misión. Cada entrada muestra solo un nombre público y un avatar; los menores aparecen con un seudónimo y quienes ocultaron su perfil no aparecen. Se ordena por XP, luego por misiones completadas y, a igual puntaje, primero quien lo alcanzó antes. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Missions
+// @Produce json
+// @Param X-Tenant-ID header string false "Organización cuyo ranking se consulta; por defecto, la organización por defecto"
+// @Param period query string false "Periodo: weekly, monthly o all_time" default(all_time)
+// @Param limit query int false "Entradas por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param sort query string false "Solo rank" default(rank)
+// @Param fields query string false "Campos de cada entrada, separados por comas"
+// @Success 200 {object} Page[LeaderboardEntry]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
+// @Router /missions/leaderboard [get]
+func (s *Server) GetLeaderboard(c *gin.Context) {
+	_, bucket, ok := s.leaderboardBucket(c)
+	if !ok {
+		return
+	}
+	// El cursor vale solo para el ranking en que se emitió, aunque la URL
+	// siga siendo la misma al empezar otra semana o mes
+	page, ok := parsePage(c, pagination.Spec{
+		DefaultLimit: defaultLeaderboardLimit,
+		MaxLimit:     maxLeaderboardLimit,
+		Sorts:        []string{"rank"},
+		Fields:       leaderboardFields,
+		Scope:        bucket,
+	})
+	if !ok {
+		return
+	}
+
+	// Cada página sigue a la última entrada de la anterior; page.Offset
+	// cuenta las entradas ya entregadas para numerar las posiciones
+	query := database.LeaderboardQuery{Bucket: bucket, Limit: page.Fetch()}
+	var key leaderboardKey
+	if found, apiErr := page.After(&key); apiErr != nil {
+		fail(c, apiErr)
+		return
+	} else if found {
+		if query.After, ok = key.entry(); !ok {
+			fail(c, apierror.New(apierror.InvalidCursor))
+			return
+		}
+	}
+
+	ranking, err := s.leaderboard.GetLeaderboard(query)
+	if err != nil {
+		s.internalError(c, "Error al obtener el leaderboard", err)
+		return
+	}
+
+	ranking, next := pagination.NextAfter(c, page, ranking, newLeaderboardKey)
+	entries := make([]LeaderboardEntry, 0, len(ranking))
+	for i, entry := range ranking {
+		entries = append(entries, s.newLeaderboardEntry(entry, page.Offset+i+1))
+	}
+	respondPage(s, c, page, entries, next)
+}
+
+// GetMyLeaderboardPosition godoc
+// @Summary Obtiene la posición del usuario en el ranking
+// @Description Devuelve la posición del usuario autenticado en el ranking del periodo junto con los usuarios inmediatamente anteriores y posteriores.
+// @Tags Missions
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Periodo: weekly, monthly o all_time" default(all_time)
+// @Param neighbours query int false "Vecinos a cada lado, hasta 10" default(2)
+// @Success 200 {object} LeaderboardPosition
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 404 {object} apierror.Response "El usuario no completó misiones en el periodo u ocultó su perfil"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
+// @Router /missions/leaderboard/me [get]
+func (s *Server) GetMyLeaderboardPosition(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	period, bucket, ok := s.leaderboardBucket(c)
+	if !ok {
+		return
+	}
+	neighbours, ok := queryInt(c, "neighbours", defaultNeighbours, 0, maxNeighbours)
+	if !ok {
+		return
+	}
+
+	entry, ahead, err := s.leaderboard.GetLeaderboardPosition(userObjID, bucket)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.NotOnLeaderboard))
+		} else {
+			s.internalError(c, "Error al obtener la posición en el leaderboard", err)
+		}
+		return
+	}
+
+	var above, below []models.LeaderboardEntry
+	if neighbours > 0 {
+		above, err = s.leaderboard.GetLeaderboard(database.LeaderboardQuery{Bucket: bucket, Limit: neighbours, Before: entry})
+		if err == nil {
+			below, err = s.leaderboard.GetLeaderboard(database.LeaderboardQuery{Bucket: bucket, Limit: neighbours, After: entry})
+		}
+		if err != nil {
+			s.internalError(c, "Error al obtener la posición en el leaderboard", err)
+			return
+		}
+	}
+
+	rank := ahead + 1
+	entries := make([]LeaderboardEntry, 0, len(above)+1+len(below))
+	for i, e := range above {
+		entries = append(entries, s.newLeaderboardEntry(e, rank-len(above)+i))
+	}
+	me := s.newLeaderboardEntry(*entry, rank)
+	me.Me = true
+	entries = append(entries, me)
+	for i, e := range below {
+		entries = append(entries, s.newLeaderboardEntry(e, rank+1+i))
+	}
+	c.JSON(http.StatusOK, LeaderboardPosition{Period: period, Rank: rank, Entries: entries})
+}
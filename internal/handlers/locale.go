@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/i18n"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// locale devuelve el idioma en que se responde la petición: el preferido por
+// el usuario autenticado o, si no eligió uno, el negociado con
+// Accept-Language. Lo anuncia en la cabecera Content-Language.
+func (s *Server) locale(c *gin.Context) string {
+	if locale := c.GetString(i18n.ContextKey); locale != "" {
+		return locale
+	}
+	locale := ""
+	if userObjID, err := primitive.ObjectIDFromHex(c.GetString("user_id")); err == nil {
+		if user, err := s.users.FindUserByID(userObjID); err == nil {
+			locale = user.Locale
+		}
+	}
+	if !models.IsSupportedLocale(locale) {
+		locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+	}
+	c.Set(i18n.ContextKey, locale)
+	c.Header("Content-Language", locale)
+	return locale
+}
+
+// unknownLocale es el error de un campo con un idioma no soportado.
+func unknownLocale(field string) *apierror.FieldError {
+	return apierror.Field(field, apierror.UnknownValue).With("allowed", strings.Join(models.SupportedLocales, ", "))
+}
@@ -1,33 +1,38 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"explorax-backend/internal/apierror"
 	"explorax-backend/internal/database"
+	"explorax-backend/internal/middleware"
 	"explorax-backend/internal/models"
+	"explorax-backend/internal/pagination"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// GenericResponse es la respuesta de las operaciones que solo informan un
+// mensaje. Los errores se responden con apierror.Response.
 type GenericResponse struct {
 	Message string `json:"message"`
-	Error   string `json:"error,omitempty"` // Si hay error, lo incluye
-}
-
-type LeaderboardEntry struct {
-	Username       string `json:"username"`
-	Email          string `json:"email"`
-	CompletedCount int    `json:"completed_count"`
 }
 
 // UserStatistics contiene estadísticas generales del usuario
 type UserStatistics struct {
-	TotalCompleted     int     `json:"total_completed"`
-	AverageDuration    float64 `json:"average_duration"`
-	ProgressPercentage float64 `json:"progress_percentage"`
+	TotalCompleted     int     `json:"totalCompleted"`
+	AverageDuration    float64 `json:"averageDuration"`
+	ProgressPercentage float64 `json:"progressPercentage"`
+	XP                 int     `json:"xp"`
+	Level              int     `json:"level"`
+	XPToNextLevel      int     `json:"xpToNextLevel"`
 }
 
 // MissionsOverview contiene el resumen de misiones
@@ -42,9 +47,16 @@ type StartMissionRequest struct {
 	MissionID string `json:"mission_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b6"`
 }
 
+// MissionActionRequest es el cuerpo de las acciones sobre el progreso de una
+// misión: completar, pausar, reanudar y abandonar.
+// @Description Estructura del request para cambiar el estado de una misión
+type MissionActionRequest struct {
+	MissionID string `json:"mission_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b6"`
+}
+
 // StartMission godoc
 // @Summary Inicia una misión
-// @Description Registra el progreso de una misión como "iniciada" para el usuario autenticado.
+// @Description Registra el progreso de una misión como "iniciada" para el usuario autenticado. Si la misión fue abandonada, la reinicia desde cero. Una misión con prerrequisitos sin completar está bloqueada y responde 409 con la lista de los que faltan.
 // @Tags Missions
 // @Accept json
 // @Produce json
@@ -52,394 +64,1028 @@ type StartMissionRequest struct {
 // @Param mission body StartMissionRequest true "ID de la misión a iniciar"
 // @Security BearerAuth
 // @Success 200 {object} map[string]string "Misión iniciada exitosamente"
-// @Failure 400 {object} map[string]string "Datos inválidos"
-// @Failure 401 {object} map[string]string "Usuario no autenticado"
-// @Failure 500 {object} map[string]string "Error al iniciar la misión"
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión ya está en curso o completada, o está bloqueada por sus prerrequisitos"
+// @Failure 500 {object} apierror.Response "Error al iniciar la misión"
 // @Router /missions/start [post]
 
-func StartMission(c *gin.Context) {
-	// Obtener el user_id del contexto y convertirlo a string
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
-		return
-	}
-	userIDStr, ok := userIDVal.(string)
+func (s *Server) StartMission(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
-		return
-	}
-
-	// Convertir el userID de string a ObjectID
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
 		return
 	}
 
 	// Vincular el JSON recibido a la estructura StartMissionRequest
 	var input StartMissionRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		fail(c, apierror.Invalid(err))
 		return
 	}
 
 	// Convertir MissionID a ObjectID
 	missionObjID, err := primitive.ObjectIDFromHex(input.MissionID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de misión inválido"})
+		fail(c, apierror.Invalid(apierror.Field("mission_id", apierror.InvalidObjectID)))
+		return
+	}
+
+	// Solo se pueden iniciar misiones existentes y disponibles
+	mission, err := s.missions.GetMissionByID(missionObjID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		s.internalError(c, "Error al iniciar la misión", err)
+		return
+	}
+	if err != nil || mission.Archived {
+		fail(c, apierror.New(apierror.MissionNotFound))
 		return
 	}
 
-	progress := models.MissionProgress{
-		ID:        primitive.NewObjectID(),
-		UserID:    userObjID,
-		MissionID: missionObjID,
-		Status:    "iniciada",
-		StartDate: time.Now(),
+	// Un progreso previo solo puede reiniciarse si fue abandonado
+	existing, err := s.progress.FindMissionProgress(userObjID, missionObjID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		s.internalError(c, "Error al iniciar la misión", err)
+		return
+	}
+	if existing == nil || existing.Status == models.ProgressAbandoned {
+		if !s.checkUnlocked(c, userObjID, mission) {
+			return
+		}
+	}
+	if existing != nil {
+		s.applyTransition(c, mission, existing, models.ActionStart, "Misión iniciada")
+		return
 	}
 
-	if err := database.InsertMissionProgress(progress); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al iniciar la misión"})
+	// El índice único (userId, missionId) impide iniciar dos veces la misma misión
+	progress := models.NewMissionProgress(userObjID, missionObjID, s.now())
+	if err := s.progress.InsertMissionProgress(progress); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			fail(c, apierror.New(apierror.MissionAlreadyStarted))
+		} else {
+			s.internalError(c, "Error al iniciar la misión", err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Misión iniciada"})
+	c.JSON(http.StatusOK, gin.H{"message": "Misión iniciada", "achievements": s.checkAchievements(userObjID)})
 }
 
 // CompleteMission godoc
 // @Summary Completa una misión
-// @Description Actualiza el estado de la misión a "completada".
+// @Description Actualiza el estado de la misión a "completada". Solo se pueden completar misiones iniciadas; una misión pausada debe reanudarse antes.
 // @Tags Missions
 // @Accept  json
 // @Produce  json
-// @Param mission body object true "Mission ID"
+// @Param mission body MissionActionRequest true "ID de la misión"
 // @Security BearerAuth
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} apierror.Response "La misión no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión no está en curso"
+// @Failure 500 {object} apierror.Response
 // @Router /missions/complete [post]
-func CompleteMission(c *gin.Context) {
-	// Obtener el user_id del contexto y convertirlo a ObjectID
-	userIDValue, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
-		return
-	}
-	userIDStr, ok := userIDValue.(string)
+func (s *Server) CompleteMission(c *gin.Context) {
+	s.transitionMission(c, models.ActionComplete, "Misión completada")
+}
+
+// PauseMission godoc
+// @Summary Pausa una misión
+// @Description Pausa una misión iniciada. El tiempo en pausa no cuenta para la duración de la misión.
+// @Tags Missions
+// @Accept  json
+// @Produce  json
+// @Param mission body MissionActionRequest true "ID de la misión"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apierror.Response "La misión no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión no está en curso"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/pause [post]
+func (s *Server) PauseMission(c *gin.Context) {
+	s.transitionMission(c, models.ActionPause, "Misión pausada")
+}
+
+// ResumeMission godoc
+// @Summary Reanuda una misión
+// @Description Reanuda una misión pausada.
+// @Tags Missions
+// @Accept  json
+// @Produce  json
+// @Param mission body MissionActionRequest true "ID de la misión"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apierror.Response "La misión no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión no está pausada"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/resume [post]
+func (s *Server) ResumeMission(c *gin.Context) {
+	s.transitionMission(c, models.ActionResume, "Misión reanudada")
+}
+
+// AbandonMission godoc
+// @Summary Abandona una misión
+// @Description Abandona una misión iniciada o pausada. Puede volver a iniciarse más tarde desde cero.
+// @Tags Missions
+// @Accept  json
+// @Produce  json
+// @Param mission body MissionActionRequest true "ID de la misión"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apierror.Response "La misión no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión no está en curso"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/abandon [post]
+func (s *Server) AbandonMission(c *gin.Context) {
+	s.transitionMission(c, models.ActionAbandon, "Misión abandonada")
+}
+
+// transitionMission aplica una acción sobre el progreso del usuario en la
+// misión indicada en el body.
+func (s *Server) transitionMission(c *gin.Context, action models.ProgressAction, message string) {
+	userObjID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
-		return
-	}
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
 		return
 	}
 
 	// Vincular y convertir el MissionID del body a ObjectID
-	var input struct {
-		MissionID string `json:"mission_id" binding:"required"`
-	}
+	var input MissionActionRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		fail(c, apierror.Invalid(err))
 		return
 	}
 	missionObjID, err := primitive.ObjectIDFromHex(input.MissionID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de misión inválido"})
+		fail(c, apierror.Invalid(apierror.Field("mission_id", apierror.InvalidObjectID)))
 		return
 	}
 
-	// Actualizar el progreso; la función UpdateMissionProgress usa un filtro
-	// que solo coincide si el status es "iniciada"
-	err = database.UpdateMissionProgress(userObjID, missionObjID)
+	mission, err := s.missions.GetMissionByID(missionObjID)
 	if err != nil {
-		// Si no se encontró ningún documento, se asume que la misión no fue iniciada
-		if err == mongo.ErrNoDocuments {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "No has iniciado esta misión, no puedes completarla"})
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al completar la misión"})
+			s.internalError(c, "Error al actualizar la misión", err)
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Misión completada"})
-}
+	progress, err := s.progress.FindMissionProgress(userObjID, missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotStarted).With("action", action))
+		} else {
+			s.internalError(c, "Error al consultar el progreso", err)
+		}
+		return
+	}
 
-// GetProgress godoc
-// @Summary Obtiene el progreso de misiones
-// @Description Devuelve todas las misiones iniciadas y completadas del usuario.
-// @Tags Missions
-// @Accept  json
-// @Produce  json
-// @Security BearerAuth
-// @Success 200 {object} []models.MissionProgress
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /missions/progress [get]
-func GetProgress(c *gin.Context) {
-	// Obtener el user_id del contexto y convertirlo a ObjectID
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+	// Las misiones con cuestionario solo se completan al aprobarlo
+	if action == models.ActionComplete && mission.Quiz != nil {
+		fail(c, apierror.New(apierror.QuizRequired))
 		return
 	}
 
-	userIDStr, ok := userIDVal.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
+	// Las misiones con pasos solo se completan al terminar los obligatorios
+	if action == models.ActionComplete && progress.Status == models.ProgressStarted && !progress.RequiredStepsDone(*mission) {
+		done, required := progress.StepsCompleted(*mission)
+		fail(c, apierror.New(apierror.StepsIncomplete).With("done", done).With("required", required))
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
+	s.applyTransition(c, mission, progress, action, message)
+}
+
+// applyTransition valida la acción contra la máquina de estados y guarda el
+// progreso. UpdateMissionProgress solo escribe si nadie cambió el estado
+// entretanto, así dos peticiones simultáneas no pueden aplicar la misma acción.
+func (s *Server) applyTransition(c *gin.Context, mission *models.Mission, progress *models.MissionProgress, action models.ProgressAction, message string) {
+	from := progress.Status
+	var err error
+	if action == models.ActionComplete {
+		err = progress.Complete(*mission, s.now())
+	} else {
+		err = progress.Apply(action, s.now())
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
+		fail(c, apierror.New(apierror.InvalidTransition).With("action", action).With("status", from))
 		return
 	}
 
-	progress, err := database.GetMissionProgress(userObjID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el progreso"})
+	if err := s.saveProgress(*progress, from); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.ProgressConflict))
+		} else {
+			s.internalError(c, "Error al actualizar la misión", err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, progress)
+	response := gin.H{"message": message, "status": progress.Status}
+	if progress.Status == models.ProgressCompleted {
+		response["xpAwarded"] = progress.XPAwarded
+	}
+	if action == models.ActionStart || action == models.ActionComplete {
+		response["achievements"] = s.checkAchievements(progress.UserID)
+	}
+	c.JSON(http.StatusOK, response)
 }
 
-// GetActiveMissions godoc
-// @Summary Obtiene misiones activas de un usuario
-// @Description Retorna todas las misiones con estado "iniciada" para un usuario autenticado
+// saveProgress guarda el progreso tras una transición desde el estado from.
+// Si la transición completó la misión, acredita su XP al usuario y actualiza
+// su racha. La XP queda registrada como pendiente en el mismo documento del
+// progreso, así que si acreditarla falla no se pierde: se suma en la próxima
+// reconciliación (ver creditXP) y nunca dos veces.
+func (s *Server) saveProgress(progress models.MissionProgress, from models.ProgressStatus) error {
+	if err := s.progress.UpdateMissionProgress(progress, from); err != nil {
+		return err
+	}
+	if progress.Status != models.ProgressCompleted {
+		return nil
+	}
+	s.recordStreak(progress.UserID, progress.EndDate)
+	s.recordLeaderboard(progress)
+	s.creditXP(progress.UserID)
+	return nil
+}
+
+// creditXP suma al usuario la XP pendiente de sus misiones completadas. Si
+// falla, la XP sigue pendiente y se reintenta en la siguiente misión
+// completada o al consultar las estadísticas.
+func (s *Server) creditXP(userID primitive.ObjectID) {
+	if _, err := s.progress.CreditPendingXP(userID); err != nil {
+		s.logger.Printf("No se pudo acreditar la XP pendiente del usuario %s: %v", userID.Hex(), err)
+	}
+}
+
+// CompleteStepRequest es el cuerpo de la solicitud para completar un paso.
+// @Description Estructura del request para completar un paso de una misión
+type CompleteStepRequest struct {
+	MissionID string `json:"mission_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b6"`
+	StepID    string `json:"step_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b7"`
+}
+
+// MissionProgressView es el progreso de una misión junto con el avance en sus
+// pasos obligatorios y el tiempo transcurrido.
+// @Description Progreso de una misión con su porcentaje de avance
+type MissionProgressView struct {
+	models.MissionProgress
+	// El avance en los pasos necesita la misión: en las listas de progreso
+	// solo se incluye con expand=mission, salvo el 100% de las completadas
+	StepsCompleted       *int     `json:"stepsCompleted,omitempty" example:"2"`
+	StepsRequired        *int     `json:"stepsRequired,omitempty" example:"4"`
+	CompletionPercentage *float64 `json:"completionPercentage,omitempty" example:"50"`
+	// ElapsedMs es el tiempo activo hasta el momento de la respuesta: a
+	// diferencia de activeDurationMs, incluye el tramo en curso
+	ElapsedMs int64 `json:"elapsedMs" example:"420000"`
+	// Mission es la misión, en el idioma del usuario, con expand=mission
+	Mission *models.Mission `json:"mission,omitempty"`
+}
+
+// newProgressView calcula el avance del progreso en el instante now. Sin la
+// misión, porque no se cargó o ya no existe, solo se puede saber si está
+// completada.
+func newProgressView(progress models.MissionProgress, mission *models.Mission, now time.Time) MissionProgressView {
+	view := MissionProgressView{MissionProgress: progress, ElapsedMs: progress.Elapsed(now).Milliseconds()}
+	if mission == nil {
+		if progress.Status == models.ProgressCompleted {
+			percentage := 100.0
+			view.CompletionPercentage = &percentage
+		}
+		return view
+	}
+	done, required := progress.StepsCompleted(*mission)
+	percentage := progress.CompletionPercentage(*mission)
+	view.StepsCompleted, view.StepsRequired, view.CompletionPercentage = &done, &required, &percentage
+	return view
+}
+
+// CompleteMissionStep godoc
+// @Summary Completa un paso de una misión
+// @Description Marca un paso de una misión iniciada como completado. Al completar el último paso obligatorio la misión se completa automáticamente.
 // @Tags Missions
-// @Accept json
-// @Produce json
+// @Accept  json
+// @Produce  json
+// @Param step body CompleteStepRequest true "Misión y paso a completar"
 // @Security BearerAuth
-// @Success 200 {array} models.MissionProgress
-// @Failure 401 {object} GenericResponse "Usuario no autenticado"
-// @Failure 500 {object} GenericResponse "Error interno del servidor"
-// @Router /missions/active [get]
-func GetActiveMissions(c *gin.Context) {
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+// @Success 200 {object} map[string]interface{} "Paso completado y progreso actualizado"
+// @Failure 400 {object} apierror.Response "La misión no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión o paso no encontrado"
+// @Failure 409 {object} apierror.Response "La misión no está en curso o el paso ya fue completado"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/steps/complete [post]
+func (s *Server) CompleteMissionStep(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	userIDStr, ok := userIDVal.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
+	var input CompleteStepRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	missionObjID, err := primitive.ObjectIDFromHex(input.MissionID)
+	if err != nil {
+		fail(c, apierror.Invalid(apierror.Field("mission_id", apierror.InvalidObjectID)))
+		return
+	}
+	stepObjID, err := primitive.ObjectIDFromHex(input.StepID)
+	if err != nil {
+		fail(c, apierror.Invalid(apierror.Field("step_id", apierror.InvalidObjectID)))
 		return
 	}
 
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
+	mission, err := s.missions.GetMissionByID(missionObjID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "Error al completar el paso", err)
+		}
+		return
+	}
+	if _, ok := mission.Step(stepObjID); !ok {
+		fail(c, apierror.New(apierror.StepNotFound))
+		return
+	}
+
+	progress, err := s.progress.FindMissionProgress(userObjID, missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotStarted))
+		} else {
+			s.internalError(c, "Error al consultar el progreso", err)
+		}
+		return
+	}
+	if progress.Status != models.ProgressStarted {
+		fail(c, apierror.New(apierror.InvalidTransition).With("action", "complete_step").With("status", progress.Status))
+		return
+	}
+	if progress.HasCompletedStep(stepObjID) {
+		fail(c, apierror.New(apierror.StepAlreadyCompleted))
 		return
 	}
 
-	active, err := database.GetActiveMissions(userObjID)
+	now := s.now()
+	updated, err := s.progress.CompleteMissionStep(progress.ID, models.StepProgress{StepID: stepObjID, CompletedAt: now})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener misiones activas"})
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.ProgressConflict))
+		} else {
+			s.internalError(c, "Error al completar el paso", err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, active)
+	// Completar el último paso obligatorio completa la misión. Si otra petición
+	// ya la completó o la pausó, se respeta ese estado.
+	if updated.RequiredStepsDone(*mission) && updated.CanApply(models.ActionComplete) {
+		completed := *updated
+		if err := completed.Complete(*mission, now); err != nil {
+			s.internalError(c, "Error al completar la misión", err)
+			return
+		}
+		err := s.saveProgress(completed, models.ProgressStarted)
+		if err == nil {
+			updated = &completed
+		} else if !errors.Is(err, database.ErrNotFound) {
+			s.internalError(c, "Error al completar la misión", err)
+			return
+		}
+	}
+
+	response := gin.H{
+		"message":          "Paso completado",
+		"missionCompleted": updated.Status == models.ProgressCompleted,
+		"xpAwarded":        updated.XPAwarded,
+		"progress":         newProgressView(*updated, mission, s.now()),
+	}
+	if updated.Status == models.ProgressCompleted {
+		response["achievements"] = s.checkAchievements(userObjID)
+	}
+	c.JSON(http.StatusOK, response)
 }
 
-// GetCompletedMissions godoc
-// @Summary Obtiene misiones completadas de un usuario
-// @Description Retorna todas las misiones con estado "completada" para un usuario autenticado
-// @Tags Missions
-// @Accept json
-// @Produce json
-// @Security BearerAuth
-// @Success 200 {array} models.MissionProgress
-// @Failure 401 {object} GenericResponse "Usuario no autenticado"
-// @Failure 500 {object} GenericResponse "Error interno del servidor"
-// @Router /missions/completed [get]
-func GetCompletedMissions(c *gin.Context) {
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
-		return
+const (
+	defaultProgressLimit = 50
+	maxProgressLimit     = 100
+)
+
+// progressSorts traduce el parámetro sort a los campos de ordenamiento del progreso.
+var progressSorts = map[string]string{
+	"started": database.SortByStartDate,
+	"ended":   database.SortByEndDate,
+}
+
+// progressFields son los campos que se pueden pedir en fields en las listas
+// de progreso.
+var progressFields = pagination.FieldsOf(MissionProgressView{})
+
+// progressKey es la clave de orden del último progreso de una página, que el
+// cursor guarda para leer la siguiente con ProgressQuery.After.
+type progressKey struct {
+	Date time.Time          `json:"d"`
+	ID   primitive.ObjectID `json:"i"`
+}
+
+func progressKeyFor(field string) func(database.ProgressWithMission) interface{} {
+	return func(p database.ProgressWithMission) interface{} {
+		if field == database.SortByEndDate {
+			return progressKey{Date: p.Progress.EndDate, ID: p.Progress.ID}
+		}
+		return progressKey{Date: p.Progress.StartDate, ID: p.Progress.ID}
 	}
+}
+
+func (k progressKey) progress(field string) *models.MissionProgress {
+	if field == database.SortByEndDate {
+		return &models.MissionProgress{ID: k.ID, EndDate: k.Date}
+	}
+	return &models.MissionProgress{ID: k.ID, StartDate: k.Date}
+}
 
-	userIDStr, ok := userIDVal.(string)
+// progressExpansions son los valores que acepta expand en las listas de progreso.
+var progressExpansions = []string{"mission"}
+
+// respondProgress responde una página del progreso del usuario autenticado
+// en los estados indicados, o en todos si no se indica ninguno, con el avance
+// de cada misión. defaultSort es el orden sin sort.
+func (s *Server) respondProgress(c *gin.Context, defaultSort string, statuses ...models.ProgressStatus) {
+	userObjID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
 		return
 	}
-
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
+	page, ok := parsePage(c, pagination.Spec{
+		DefaultLimit: defaultProgressLimit,
+		MaxLimit:     maxProgressLimit,
+		Sorts:        []string{"started", "ended"},
+		Reversible:   true,
+		DefaultSort:  defaultSort,
+		Fields:       progressFields,
+	})
+	if !ok {
 		return
 	}
+	expandMission := false
+	if raw := c.Query("expand"); raw != "" {
+		for _, value := range strings.Split(raw, ",") {
+			if !slices.Contains(progressExpansions, strings.TrimSpace(value)) {
+				fail(c, apierror.InvalidQuery(apierror.Field("expand", apierror.UnknownValue).
+					With("allowed", strings.Join(progressExpansions, ", "))))
+				return
+			}
+		}
+		expandMission = true
+	}
 
-	completed, err := database.GetCompletedMissions(userObjID)
+	query := database.ProgressQuery{
+		UserID:         userObjID,
+		Statuses:       statuses,
+		Sort:           progressSorts[page.Sort],
+		SortDescending: page.Descending,
+		Limit:          page.Fetch(),
+		WithMission:    expandMission,
+	}
+	var key progressKey
+	if found, apiErr := page.After(&key); apiErr != nil {
+		fail(c, apiErr)
+		return
+	} else if found {
+		query.After = key.progress(query.Sort)
+	}
+	progress, err := s.progress.ListMissionProgress(query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener misiones completadas"})
+		s.internalError(c, "Error al obtener el progreso", err)
 		return
 	}
+	progress, next := pagination.NextAfter(c, page, progress, progressKeyFor(query.Sort))
 
-	c.JSON(http.StatusOK, completed)
+	now := s.now()
+	locale := ""
+	if expandMission {
+		locale = s.locale(c)
+	}
+	views := make([]MissionProgressView, 0, len(progress))
+	for _, p := range progress {
+		view := newProgressView(p.Progress, p.Mission, now)
+		if expandMission && p.Mission != nil {
+			mission := p.Mission.Public().Localize(locale)
+			view.Mission = &mission
+		}
+		views = append(views, view)
+	}
+	respondPage(s, c, page, views, next)
+}
+
+// GetProgress godoc
+// @Summary Obtiene el progreso de misiones
+// @Description Devuelve una página del progreso del usuario en sus misiones, por defecto de la iniciada más recientemente a la más antigua, con el tiempo activo transcurrido. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios; sin él no se consultan las misiones y solo las completadas traen su porcentaje (100). Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Missions
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param sort query string false "started o ended; con - delante, en orden descendente" default(-started)
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Param expand query string false "mission para incluir la misión de cada progreso"
+// @Success 200 {object} Page[MissionProgressView]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response
+// @Failure 500 {object} apierror.Response
+// @Router /missions/progress [get]
+func (s *Server) GetProgress(c *gin.Context) {
+	s.respondProgress(c, "-started")
 }
 
-// GetLeaderboard godoc
-// @Summary Obtiene el ranking de usuarios basado en misiones completadas
-// @Description Devuelve un leaderboard con los usuarios ordenados por número de misiones completadas
+// GetActiveMissions godoc
+// @Summary Obtiene misiones activas de un usuario
+// @Description Retorna una página de las misiones en curso (iniciadas o pausadas) del usuario autenticado, por defecto de la iniciada más recientemente a la más antigua, con el mismo tiempo transcurrido que /missions/progress. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
 // @Tags Missions
 // @Accept json
 // @Produce json
-// @Success 200 {array} LeaderboardEntry
-// @Failure 500 {object} GenericResponse "Error interno del servidor"
-// @Router /missions/leaderboard [get]
-func GetLeaderboard(c *gin.Context) {
-	leaderboard, err := database.GetLeaderboard()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener el leaderboard"})
-		return
-	}
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param sort query string false "started o ended; con - delante, en orden descendente" default(-started)
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Param expand query string false "mission para incluir la misión de cada progreso"
+// @Success 200 {object} Page[MissionProgressView]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
+// @Router /missions/active [get]
+func (s *Server) GetActiveMissions(c *gin.Context) {
+	s.respondProgress(c, "-started", models.ProgressStarted, models.ProgressPaused)
+}
 
-	c.JSON(http.StatusOK, leaderboard)
+// GetCompletedMissions godoc
+// @Summary Obtiene misiones completadas de un usuario
+// @Description Retorna una página de las misiones con estado "completada" del usuario autenticado, por defecto de la completada más recientemente a la más antigua, con el mismo tiempo transcurrido que /missions/progress. Con expand=mission incluye además cada misión en el idioma del usuario y el avance en sus pasos obligatorios. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param sort query string false "started o ended; con - delante, en orden descendente" default(-ended)
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Param expand query string false "mission para incluir la misión de cada progreso"
+// @Success 200 {object} Page[MissionProgressView]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
+// @Router /missions/completed [get]
+func (s *Server) GetCompletedMissions(c *gin.Context) {
+	s.respondProgress(c, "-ended", models.ProgressCompleted)
 }
 
 // GetStatistics godoc
 // @Summary Obtiene estadísticas del usuario
-// @Description Devuelve estadísticas como el número total de misiones completadas y duración promedio
+// @Description Devuelve estadísticas como el número total de misiones completadas, la duración promedio, la XP acumulada y el nivel
 // @Tags Missions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} UserStatistics
-// @Failure 401 {object} GenericResponse "Usuario no autenticado"
-// @Failure 500 {object} GenericResponse "Error interno del servidor"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
 // @Router /missions/statistics [get]
-func GetStatistics(c *gin.Context) {
-	userIDVal, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Usuario no autenticado"})
+func (s *Server) GetStatistics(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
 
-	userIDStr, ok := userIDVal.(string)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Formato de ID de usuario incorrecto"})
+	s.creditXP(userObjID)
+	user, err := s.users.FindUserByID(userObjID)
+	if err != nil {
+		s.internalError(c, "Error al obtener estadísticas", err)
 		return
 	}
-	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
+	stats, err := s.userStatistics(*user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al convertir el ID del usuario"})
+		s.internalError(c, "Error al obtener estadísticas", err)
 		return
 	}
 
-	stats, err := database.GetUserStatistics(userObjID)
+	c.JSON(http.StatusOK, stats)
+}
+
+// userStatistics combina las estadísticas de progreso del usuario con su XP y
+// su nivel. La usan /missions/statistics y los reportes de las clases.
+func (s *Server) userStatistics(user models.User) (UserStatistics, error) {
+	raw, err := s.progress.GetUserStatistics(user.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener estadísticas"})
-		return
+		return UserStatistics{}, err
 	}
+	level := s.levels.Progress(user.XP)
+	return UserStatistics{
+		TotalCompleted:     int(bsonNumber(raw["totalCompleted"])),
+		AverageDuration:    bsonNumber(raw["averageDuration"]),
+		ProgressPercentage: bsonNumber(raw["progressPercentage"]),
+		XP:                 level.XP,
+		Level:              level.Level,
+		XPToNextLevel:      level.XPToNextLevel,
+	}, nil
+}
 
-	c.JSON(http.StatusOK, stats)
+// bsonNumber convierte los números que devuelven las agregaciones, que según
+// el caso llegan como int32, int64 o float64; cualquier otro valor cuenta como 0.
+func bsonNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// CreateMissionRequest representa los datos de una misión nueva.
+// @Description Estructura para crear una misión
+type CreateMissionRequest struct {
+	Title       string             `json:"title" binding:"required" example:"Explorar Marte"`
+	Description string             `json:"description" binding:"required" example:"Recorre la superficie marciana"`
+	Steps       []MissionStepInput `json:"steps"`
+	Quiz        *QuizInput         `json:"quiz"`
+	// Prerequisites son los IDs de las misiones que hay que completar antes
+	Prerequisites []string `json:"prerequisites" example:"60a7b97f5e41c42e7c2e30b5"`
+	// Category, Tags, Difficulty (1 a 5), GradeLevel (1 a 12) y
+	// EstimatedMinutes clasifican la misión; en cero no se indican
+	Category         string   `json:"category" example:"astronomia"`
+	Tags             []string `json:"tags" example:"planetas,marte"`
+	Difficulty       int      `json:"difficulty" example:"2"`
+	GradeLevel       int      `json:"grade_level" example:"5"`
+	EstimatedMinutes int      `json:"estimated_minutes" example:"20"`
+	// XPReward es la XP base; si no se indica se usa models.DefaultXPReward
+	XPReward             int     `json:"xp_reward" example:"150"`
+	DifficultyMultiplier float64 `json:"difficulty_multiplier" example:"1.5"`
+	// Global ofrece la misión en todas las organizaciones; solo pueden
+	// crearlas los administradores de la organización por defecto
+	Global bool `json:"global" example:"false"`
+	// Locale es el idioma en que está escrita; por defecto, español
+	Locale string `json:"locale" example:"es"`
 }
 
 // CreateMission godoc
 // @Summary Crea una nueva misión
-// @Description Crea una nueva misión con un título y descripción proporcionados
+// @Description Crea una nueva misión de la organización del administrador con un título, una descripción y, opcionalmente, una lista ordenada de pasos, un cuestionario y prerrequisitos. Con global en true, que solo admite la organización por defecto, la misión se ofrece en todas las organizaciones.
 // @Tags Missions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param mission body models.Mission true "Detalles de la misión"
+// @Param mission body CreateMissionRequest true "Detalles de la misión"
 // @Success 201 {object} GenericResponse "Misión creada exitosamente"
-// @Failure 400 {object} GenericResponse "Datos inválidos"
-// @Failure 500 {object} GenericResponse"No se pudo crear la misión"
-// @Router /missions [post]
-func CreateMission(c *gin.Context) {
-	var input struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description" binding:"required"`
-	}
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador o, para una misión global, de la organización por defecto"
+// @Failure 500 {object} apierror.Response"No se pudo crear la misión"
+// @Router /admin/missions/create [post]
+func (s *Server) CreateMission(c *gin.Context) {
+	var input CreateMissionRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos: " + err.Error()})
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if input.Global && middleware.CurrentTenant(c) != models.DefaultTenantID {
+		fail(c, apierror.New(apierror.GlobalMissionForbidden))
+		return
+	}
+	steps, err := buildMissionSteps(input.Steps)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if err := validateXP(input.XPReward, input.DifficultyMultiplier); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	if err := validateClassification(input.Difficulty, input.GradeLevel, input.EstimatedMinutes); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	prerequisites, err := parsePrerequisites(input.Prerequisites)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	if input.Locale != "" && !models.IsSupportedLocale(input.Locale) {
+		fail(c, apierror.Invalid(unknownLocale("locale")))
 		return
 	}
 
 	mission := models.Mission{
-		ID:          primitive.NewObjectID(),
-		Title:       input.Title,
-		Description: input.Description,
-		CreatedAt:   time.Now(),
+		ID:                   primitive.NewObjectID(),
+		Title:                input.Title,
+		Description:          input.Description,
+		Locale:               input.Locale,
+		Global:               input.Global,
+		Prerequisites:        prerequisites,
+		Category:             normalizeCategory(input.Category),
+		Tags:                 tags,
+		Difficulty:           input.Difficulty,
+		GradeLevel:           input.GradeLevel,
+		EstimatedMinutes:     input.EstimatedMinutes,
+		Steps:                steps,
+		XPReward:             input.XPReward,
+		DifficultyMultiplier: input.DifficultyMultiplier,
+		CreatedAt:            s.now(),
+	}
+	if input.Quiz != nil {
+		if mission.Quiz, err = buildQuiz(*input.Quiz); err != nil {
+			fail(c, apierror.Invalid(err))
+			return
+		}
+	}
+
+	if !s.checkPrerequisites(c, mission, "No se pudo crear la misión") {
+		return
 	}
 
-	if err := database.InsertMission(mission); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear la misión"})
+	if err := s.missions.InsertMission(mission); err != nil {
+		s.internalError(c, "No se pudo crear la misión", err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Misión creada exitosamente", "mission": mission})
 }
 
+// MissionCatalog es una página del catálogo de misiones filtrado, con las
+// facetas de las misiones que cumplen los filtros.
+type MissionCatalog struct {
+	Page[models.Mission]
+	// Total cuenta todas las misiones que cumplen los filtros, no solo las de la página
+	Total  int                  `json:"total" example:"12"`
+	Facets models.MissionFacets `json:"facets"`
+}
+
+const (
+	defaultCatalogLimit = 50
+	maxCatalogLimit     = 100
+)
+
+// missionSorts traduce el parámetro sort a los campos de ordenamiento del catálogo.
+var missionSorts = map[string]string{
+	"created":    database.SortByCreatedAt,
+	"title":      database.SortByTitle,
+	"difficulty": database.SortByDifficulty,
+	"grade":      database.SortByGradeLevel,
+	"duration":   database.SortByDuration,
+}
+
+// missionKey es la clave de orden de la última misión de una página del
+// catálogo, que el cursor guarda para leer la siguiente con
+// MissionQuery.After. Solo lleva el campo del orden pedido, además de la
+// fecha de creación y el ID que desempatan.
+type missionKey struct {
+	Title      string             `json:"n,omitempty"`
+	Difficulty int                `json:"d,omitempty"`
+	GradeLevel int                `json:"g,omitempty"`
+	Minutes    int                `json:"m,omitempty"`
+	CreatedAt  time.Time          `json:"t"`
+	ID         primitive.ObjectID `json:"i"`
+}
+
+func missionKeyFor(field string) func(models.Mission) interface{} {
+	return func(m models.Mission) interface{} {
+		key := missionKey{CreatedAt: m.CreatedAt, ID: m.ID}
+		switch field {
+		case database.SortByTitle:
+			key.Title = m.Title
+		case database.SortByDifficulty:
+			key.Difficulty = m.Difficulty
+		case database.SortByGradeLevel:
+			key.GradeLevel = m.GradeLevel
+		case database.SortByDuration:
+			key.Minutes = m.EstimatedMinutes
+		}
+		return key
+	}
+}
+
+func (k missionKey) mission() *models.Mission {
+	return &models.Mission{
+		ID:               k.ID,
+		Title:            k.Title,
+		Difficulty:       k.Difficulty,
+		GradeLevel:       k.GradeLevel,
+		EstimatedMinutes: k.Minutes,
+		CreatedAt:        k.CreatedAt,
+	}
+}
+
+// catalogSpec pagina el catálogo; sort acepta las claves de missionSorts.
+var catalogSpec = pagination.Spec{
+	DefaultLimit: defaultCatalogLimit,
+	MaxLimit:     maxCatalogLimit,
+	Sorts:        []string{"created", "title", "difficulty", "grade", "duration"},
+	Reversible:   true,
+	Fields:       pagination.FieldsOf(models.Mission{}),
+}
+
 // GetAllMissions godoc
-// @Summary Obtiene todas las misiones
-// @Description Retorna una lista de todas las misiones disponibles en la base de datos
+// @Summary Obtiene el catálogo de misiones
+// @Description Retorna las misiones disponibles (no archivadas), sin las respuestas de los cuestionarios y en el idioma del usuario, junto con el total y las facetas (cuántas misiones hay por categoría, etiqueta, dificultad y grado) de todas las que cumplen los filtros. Las misiones vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link. A los estudiantes con alguna asignación abierta, dirigida a ellos o a sus clases, les devuelve por defecto solo esas misiones; assigned=false devuelve todo el catálogo y assigned=true solo las asignadas, para cualquier usuario.
 // @Tags Missions
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Mission
-// @Failure 500 {object} GenericResponse "No se pudieron obtener las misiones"
-// @Router /missions [get]
-func GetAllMissions(c *gin.Context) {
-	missions, err := database.GetAllMissions()
+// @Security BearerAuth
+// @Param assigned query bool false "Solo las misiones asignadas al usuario; por defecto, true para los estudiantes con asignaciones abiertas"
+// @Param category query string false "Categoría"
+// @Param tags query string false "Etiquetas separadas por comas; la misión debe tenerlas todas"
+// @Param difficulty query int false "Dificultad, de 1 a 5"
+// @Param grade query int false "Grado escolar, de 1 a 12"
+// @Param max_minutes query int false "Duración estimada máxima en minutos"
+// @Param sort query string false "created, title, difficulty, grade o duration; con - delante, en orden descendente" default(created)
+// @Param limit query int false "Misiones por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada misión, separados por comas"
+// @Success 200 {object} MissionCatalog
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las misiones"
+// @Router /missions/all [get]
+func (s *Server) GetAllMissions(c *gin.Context) {
+	query, err := parseMissionQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener las misiones"})
+		fail(c, apierror.InvalidQuery(err))
 		return
 	}
-	c.JSON(http.StatusOK, missions)
+	assigned, assignedOnly, ok := s.catalogAssignments(c)
+	if !ok {
+		return
+	}
+	// Sin assigned la URL es la misma con y sin el filtro: el cursor vale
+	// solo con el mismo
+	spec := catalogSpec
+	if assignedOnly {
+		spec.Scope = "assigned"
+		query.IDs = assigned
+	}
+	page, ok := parsePage(c, spec)
+	if !ok {
+		return
+	}
+	query.Sort = missionSorts[page.Sort]
+	query.SortDescending = page.Descending
+	query.Limit = page.Fetch()
+	var key missionKey
+	if found, apiErr := page.After(&key); apiErr != nil {
+		fail(c, apiErr)
+		return
+	} else if found {
+		query.After = key.mission()
+	}
+
+	result, err := s.missions.FindMissions(query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las misiones", err)
+		return
+	}
+	// La clave se toma antes de traducir las misiones: el título ordena en
+	// su idioma original
+	missions, next := pagination.NextAfter(c, page, result.Missions, missionKeyFor(query.Sort))
+	locale := s.locale(c)
+	for i := range missions {
+		missions[i] = missions[i].Public().Localize(locale)
+	}
+	catalog := MissionCatalog{
+		Page:   Page[models.Mission]{Items: missions, NextCursor: next},
+		Total:  result.Total,
+		Facets: result.Facets,
+	}
+	if len(page.Fields) == 0 {
+		c.JSON(http.StatusOK, catalog)
+		return
+	}
+	selected, err := pagination.Select(missions, page.Fields)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las misiones", err)
+		return
+	}
+	// Items oculta el campo del mismo nombre de MissionCatalog
+	c.JSON(http.StatusOK, struct {
+		MissionCatalog
+		Items []map[string]json.RawMessage `json:"items"`
+	}{catalog, selected})
+}
+
+// parseMissionQuery lee los filtros del catálogo de la query string; el
+// orden y la página los lee pagination.Parse.
+func parseMissionQuery(c *gin.Context) (database.MissionQuery, error) {
+	query := database.MissionQuery{Category: normalizeCategory(c.Query("category"))}
+	if raw := c.Query("tags"); raw != "" {
+		tags, err := normalizeTags(strings.Split(raw, ","))
+		if err != nil {
+			return query, err
+		}
+		query.Tags = tags
+	}
+
+	numbers := []struct {
+		name  string
+		max   int
+		value *int
+	}{
+		{"difficulty", models.MaxDifficulty, &query.Difficulty},
+		{"grade", models.MaxGradeLevel, &query.GradeLevel},
+		{"max_minutes", 0, &query.MaxMinutes},
+	}
+	for _, n := range numbers {
+		raw := c.Query(n.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || (n.max > 0 && value > n.max) {
+			if n.max > 0 {
+				return query, apierror.Field(n.name, apierror.OutOfRange).With("min", 1).With("max", n.max)
+			}
+			return query, apierror.Field(n.name, apierror.TooSmall).With("min", 1)
+		}
+		*n.value = value
+	}
+
+	return query, nil
 }
 
 // GetMissionByID godoc
 // @Summary Obtiene una misión por su ID
-// @Description Retorna los detalles de una misión específica por su ID
+// @Description Retorna los detalles de una misión específica por su ID, en el idioma del usuario: el de su preferencia o, si no eligió uno, el de Accept-Language. Lo que no está traducido se muestra en el idioma original. Las respuestas del cuestionario no se incluyen.
 // @Tags Missions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID de la misión"
 // @Success 200 {object} models.Mission
-// @Failure 400 {object} GenericResponse "ID de misión inválido"
-// @Failure 404 {object}  GenericResponse "Misión no encontrada"
+// @Failure 400 {object} apierror.Response "ID de misión inválido"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 500 {object} apierror.Response "Error interno del servidor"
 // @Router /mission/{id} [get]
-func GetMissionByID(c *gin.Context) {
+func (s *Server) GetMissionByID(c *gin.Context) {
 	idParam := c.Param("id")
 	objID, err := primitive.ObjectIDFromHex(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID de misión inválido"})
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
 		return
 	}
 
-	mission, err := database.GetMissionByID(objID)
+	mission, err := s.missions.GetMissionByID(objID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Misión no encontrada"})
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "Error al obtener la misión", err)
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, mission)
+	c.JSON(http.StatusOK, mission.Public().Localize(s.locale(c)))
 }
 
 // GetMissionsOverview obtiene una visión general de las misiones.
 //
 // @Summary Obtiene el resumen de las misiones
-// @Description Devuelve estadísticas sobre las misiones, incluyendo la misión más popular y el tiempo promedio de finalización.
+// @Description Devuelve estadísticas sobre las misiones completadas en una organización, incluyendo la misión más popular y el tiempo promedio de finalización. Como es público, de cada misión solo incluye el ID y el título.
 // @Tags Missions
 // @Produce json
+// @Param X-Tenant-ID header string false "Organización consultada; por defecto, la organización por defecto"
 // @Success 200 {object} map[string]interface{} "Resumen de misiones"
-// @Failure 500 {object} map[string]interface{} "Error interno al obtener el resumen"
+// @Failure 500 {object} apierror.Response "Error interno al obtener el resumen"
 // @Router /missions/overview [get]
-func GetMissionsOverview(c *gin.Context) {
-	overview, err := database.GetMissionsOverview()
+func (s *Server) GetMissionsOverview(c *gin.Context) {
+	overview, err := s.progress.GetMissionsOverview()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo obtener el overview"})
+		s.internalError(c, "No se pudo obtener el overview", err)
 		return
 	}
 	c.JSON(http.StatusOK, overview)
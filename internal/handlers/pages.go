@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/pagination"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 100
+)
+
+// Page es la respuesta de los endpoints que devuelven listas: los elementos
+// de la página y, si hay más, el cursor de la siguiente.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor es el cursor de la página siguiente, si la hay; también se
+	// envía en las cabeceras X-Next-Cursor y Link
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// parsePage lee limit, cursor, sort y fields según spec. Si no son válidos
+// responde 400 y devuelve false.
+func parsePage(c *gin.Context, spec pagination.Spec) (pagination.Page, bool) {
+	page, err := pagination.Parse(c, spec)
+	if err != nil {
+		fail(c, err)
+		return page, false
+	}
+	return page, true
+}
+
+// respondPage responde una Page con los elementos, con solo los campos
+// pedidos en fields, y el cursor next de la página siguiente.
+func respondPage[T any](s *Server, c *gin.Context, page pagination.Page, items []T, next string) {
+	if len(page.Fields) == 0 {
+		c.JSON(http.StatusOK, Page[T]{Items: items, NextCursor: next})
+		return
+	}
+	selected, err := pagination.Select(items, page.Fields)
+	if err != nil {
+		s.internalError(c, "No se pudieron seleccionar los campos", err)
+		return
+	}
+	c.JSON(http.StatusOK, Page[map[string]json.RawMessage]{Items: selected, NextCursor: next})
+}
+
+// listSpec pagina las listas de un solo orden que se leen con
+// database.ListQuery; fields acepta los campos JSON de item.
+func listSpec(item interface{}) pagination.Spec {
+	return pagination.Spec{
+		DefaultLimit: defaultListLimit,
+		MaxLimit:     maxListLimit,
+		Fields:       pagination.FieldsOf(item),
+	}
+}
+
+// dateKey es la clave de orden del último elemento de una página de las listas
+// ordenadas por una fecha y luego por ID.
+type dateKey struct {
+	Date time.Time          `json:"d"`
+	ID   primitive.ObjectID `json:"i"`
+}
+
+// listOrder describe el orden de una lista que se lee con database.ListQuery:
+// key toma la clave de orden de un elemento, que el cursor guarda, e item
+// arma con ella el elemento After de la página siguiente.
+type listOrder[T, K any] struct {
+	key  func(T) K
+	item func(K) T
+}
+
+// query arma la consulta de la página pedida, con uno más que el límite para
+// saber si hay otra. Si el cursor no es válido responde 400 y devuelve false.
+func (o listOrder[T, K]) query(c *gin.Context, page pagination.Page) (database.ListQuery[T], bool) {
+	query := database.ListQuery[T]{Limit: page.Fetch()}
+	var key K
+	if found, apiErr := page.After(&key); apiErr != nil {
+		fail(c, apiErr)
+		return query, false
+	} else if found {
+		after := o.item(key)
+		query.After = &after
+	}
+	return query, true
+}
+
+// next recorta items al tamaño de la página y devuelve el cursor de la
+// siguiente, como pagination.NextAfter.
+func (o listOrder[T, K]) next(c *gin.Context, page pagination.Page, items []T) ([]T, string) {
+	return pagination.NextAfter(c, page, items, func(item T) interface{} { return o.key(item) })
+}
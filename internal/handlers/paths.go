@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PathChapterInput describe un capítulo al crear o editar una ruta.
+// @Description Capítulo de una ruta de aprendizaje
+type PathChapterInput struct {
+	Title      string   `json:"title" example:"Los planetas interiores"`
+	MissionIDs []string `json:"mission_ids" example:"60a7b97f5e41c42e7c2e30b6"`
+}
+
+// LearningPathRequest es el cuerpo para crear o reemplazar una ruta de aprendizaje.
+// @Description Datos de una ruta de aprendizaje
+type LearningPathRequest struct {
+	Title       string             `json:"title" binding:"required" example:"Sistema solar"`
+	Description string             `json:"description" example:"Del Sol a Neptuno"`
+	Chapters    []PathChapterInput `json:"chapters" binding:"required"`
+}
+
+// PathNodeView es una misión de la ruta con su estado para el usuario.
+type PathNodeView struct {
+	Mission models.Mission       `json:"mission"`
+	State   models.PathNodeState `json:"state" example:"locked"`
+	// MissingPrerequisites son los prerrequisitos que faltan para desbloquearla
+	MissingPrerequisites []primitive.ObjectID `json:"missingPrerequisites,omitempty"`
+}
+
+// PathChapterView es un capítulo de la ruta con el estado de sus misiones.
+type PathChapterView struct {
+	Title     string         `json:"title" example:"Los planetas interiores"`
+	Completed int            `json:"completed" example:"2"`
+	Total     int            `json:"total" example:"4"`
+	Nodes     []PathNodeView `json:"nodes"`
+}
+
+// LearningPathView es una ruta de aprendizaje vista por un usuario.
+type LearningPathView struct {
+	ID          primitive.ObjectID `json:"id"`
+	Title       string             `json:"title" example:"Sistema solar"`
+	Description string             `json:"description,omitempty"`
+	Completed   int                `json:"completed" example:"2"`
+	Total       int                `json:"total" example:"9"`
+	Chapters    []PathChapterView  `json:"chapters"`
+}
+
+// unlockState reúne lo necesario para saber qué misiones tiene bloqueadas un
+// usuario: el catálogo disponible y, de las misiones consultadas, las que ya
+// completó.
+type unlockState struct {
+	missions  map[primitive.ObjectID]models.Mission
+	available map[primitive.ObjectID]bool
+	completed map[primitive.ObjectID]bool
+}
+
+// loadUnlockState carga el catálogo y el progreso del usuario solo en las
+// misiones missionIDs y sus prerrequisitos, que son las que se van a evaluar.
+func (s *Server) loadUnlockState(userID primitive.ObjectID, missionIDs []primitive.ObjectID) (unlockState, error) {
+	missions, err := s.missions.GetAllMissions(false)
+	if err != nil {
+		return unlockState{}, err
+	}
+	state := unlockState{
+		missions:  make(map[primitive.ObjectID]models.Mission, len(missions)),
+		available: make(map[primitive.ObjectID]bool, len(missions)),
+		completed: map[primitive.ObjectID]bool{},
+	}
+	for _, m := range missions {
+		state.missions[m.ID] = m
+		state.available[m.ID] = true
+	}
+
+	relevant := make([]primitive.ObjectID, 0, len(missionIDs))
+	for _, id := range missionIDs {
+		relevant = append(append(relevant, id), state.missions[id].Prerequisites...)
+	}
+	progress, err := s.progress.GetProgressForMissions(userID, relevant)
+	if err != nil {
+		return unlockState{}, err
+	}
+	for _, p := range progress {
+		if p.Status == models.ProgressCompleted {
+			state.completed[p.MissionID] = true
+		}
+	}
+	return state, nil
+}
+
+// checkUnlocked comprueba que el usuario haya completado los prerrequisitos
+// de la misión. Si no, responde 409 con los que faltan y devuelve false.
+func (s *Server) checkUnlocked(c *gin.Context, userID primitive.ObjectID, mission *models.Mission) bool {
+	if len(mission.Prerequisites) == 0 {
+		return true
+	}
+	state, err := s.loadUnlockState(userID, mission.Prerequisites)
+	if err != nil {
+		s.internalError(c, "Error al iniciar la misión", err)
+		return false
+	}
+	if missing := mission.MissingPrerequisites(state.completed, state.available); len(missing) > 0 {
+		fail(c, apierror.New(apierror.MissionLocked).With("prerequisites", missing))
+		return false
+	}
+	return true
+}
+
+// pathOrder es el orden de las rutas: por fecha de creación y luego por ID.
+var pathOrder = listOrder[models.LearningPath, dateKey]{
+	key:  func(p models.LearningPath) dateKey { return dateKey{Date: p.CreatedAt, ID: p.ID} },
+	item: func(k dateKey) models.LearningPath { return models.LearningPath{ID: k.ID, CreatedAt: k.Date} },
+}
+
+// ListPaths godoc
+// @Summary Lista las rutas de aprendizaje
+// @Description Devuelve una página de las rutas de aprendizaje de la organización, con sus capítulos y los IDs de sus misiones, por fecha de creación. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Paths
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.LearningPath]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las rutas"
+// @Router /paths [get]
+func (s *Server) ListPaths(c *gin.Context) {
+	page, ok := parsePage(c, listSpec(models.LearningPath{}))
+	if !ok {
+		return
+	}
+	query, ok := pathOrder.query(c, page)
+	if !ok {
+		return
+	}
+	paths, err := s.paths.GetPaths(query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las rutas", err)
+		return
+	}
+	paths, next := pathOrder.next(c, page, paths)
+	respondPage(s, c, page, paths, next)
+}
+
+// GetPath godoc
+// @Summary Obtiene una ruta de aprendizaje con el avance del usuario
+// @Description Devuelve la ruta con sus capítulos y, por cada misión, su estado para el usuario autenticado: completed si la completó, locked si le falta completar algún prerrequisito y unlocked si puede iniciarla. Las misiones archivadas o eliminadas se omiten y no bloquean a las demás. Las misiones se muestran en el idioma del usuario.
+// @Tags Paths
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la ruta"
+// @Success 200 {object} LearningPathView
+// @Failure 400 {object} apierror.Response "ID de ruta inválido"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 404 {object} apierror.Response "Ruta no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo obtener la ruta"
+// @Router /paths/{id} [get]
+func (s *Server) GetPath(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	path, ok := s.findPath(c, "No se pudo obtener la ruta")
+	if !ok {
+		return
+	}
+	state, err := s.loadUnlockState(userObjID, path.MissionIDs())
+	if err != nil {
+		s.internalError(c, "No se pudo obtener la ruta", err)
+		return
+	}
+
+	locale := s.locale(c)
+	view := LearningPathView{
+		ID:          path.ID,
+		Title:       path.Title,
+		Description: path.Description,
+		Chapters:    make([]PathChapterView, 0, len(path.Chapters)),
+	}
+	for _, chapter := range path.Chapters {
+		chapterView := PathChapterView{Title: chapter.Title, Nodes: []PathNodeView{}}
+		for _, id := range chapter.MissionIDs {
+			mission, ok := state.missions[id]
+			if !ok {
+				continue
+			}
+			node := PathNodeView{
+				Mission: mission.Public().Localize(locale),
+				State:   mission.NodeState(state.completed, state.available),
+			}
+			if node.State == models.NodeLocked {
+				node.MissingPrerequisites = mission.MissingPrerequisites(state.completed, state.available)
+			}
+			if node.State == models.NodeCompleted {
+				chapterView.Completed++
+			}
+			chapterView.Total++
+			chapterView.Nodes = append(chapterView.Nodes, node)
+		}
+		view.Completed += chapterView.Completed
+		view.Total += chapterView.Total
+		view.Chapters = append(view.Chapters, chapterView)
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// CreatePath godoc
+// @Summary Crea una ruta de aprendizaje
+// @Description Agrupa misiones de la organización, o globales, en capítulos ordenados. Una misión no puede repetirse en la ruta ni aparecer antes que alguno de sus prerrequisitos.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param path body LearningPathRequest true "Datos de la ruta"
+// @Success 201 {object} models.LearningPath
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 500 {object} apierror.Response "No se pudo crear la ruta"
+// @Router /admin/paths [post]
+func (s *Server) CreatePath(c *gin.Context) {
+	var input LearningPathRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	path, ok := s.buildPath(c, input, "No se pudo crear la ruta")
+	if !ok {
+		return
+	}
+	path.ID = primitive.NewObjectID()
+	path.CreatedAt = s.now()
+
+	if err := s.paths.InsertPath(path); err != nil {
+		s.internalError(c, "No se pudo crear la ruta", err)
+		return
+	}
+	c.JSON(http.StatusCreated, path)
+}
+
+// UpdatePath godoc
+// @Summary Reemplaza una ruta de aprendizaje
+// @Description Reemplaza el título, la descripción y los capítulos de la ruta, con las mismas reglas que al crearla
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la ruta"
+// @Param path body LearningPathRequest true "Nuevos datos de la ruta"
+// @Success 200 {object} models.LearningPath
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Ruta no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo actualizar la ruta"
+// @Router /admin/paths/{id} [put]
+func (s *Server) UpdatePath(c *gin.Context) {
+	existing, ok := s.findPath(c, "No se pudo actualizar la ruta")
+	if !ok {
+		return
+	}
+	var input LearningPathRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	path, ok := s.buildPath(c, input, "No se pudo actualizar la ruta")
+	if !ok {
+		return
+	}
+	path.ID = existing.ID
+	path.CreatedAt = existing.CreatedAt
+	path.UpdatedAt = s.now()
+
+	if err := s.paths.ReplacePath(path); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.PathNotFound))
+		} else {
+			s.internalError(c, "No se pudo actualizar la ruta", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, path)
+}
+
+// DeletePath godoc
+// @Summary Elimina una ruta de aprendizaje
+// @Description Elimina la ruta; sus misiones y el progreso de los usuarios no se modifican
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la ruta"
+// @Success 200 {object} GenericResponse "Ruta eliminada"
+// @Failure 400 {object} apierror.Response "ID de ruta inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Ruta no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo eliminar la ruta"
+// @Router /admin/paths/{id} [delete]
+func (s *Server) DeletePath(c *gin.Context) {
+	pathID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return
+	}
+	if err := s.paths.DeletePath(pathID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.PathNotFound))
+		} else {
+			s.internalError(c, "No se pudo eliminar la ruta", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Ruta eliminada"})
+}
+
+// findPath busca la ruta del parámetro :id. Si no es válido o la ruta no
+// existe responde y devuelve false.
+func (s *Server) findPath(c *gin.Context, message string) (*models.LearningPath, bool) {
+	pathID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		fail(c, apierror.New(apierror.InvalidObjectID).With("field", "id"))
+		return nil, false
+	}
+	path, err := s.paths.GetPathByID(pathID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.PathNotFound))
+		} else {
+			s.internalError(c, message, err)
+		}
+		return nil, false
+	}
+	return path, true
+}
+
+// buildPath valida la ruta recibida contra el catálogo de la organización y
+// la convierte en una ruta sin ID ni fechas. Si no es válida responde y
+// devuelve false.
+func (s *Server) buildPath(c *gin.Context, input LearningPathRequest, message string) (models.LearningPath, bool) {
+	invalid := func(field *apierror.FieldError) (models.LearningPath, bool) {
+		fail(c, apierror.Invalid(field))
+		return models.LearningPath{}, false
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return invalid(apierror.Field("title", apierror.Required))
+	}
+	if len(input.Chapters) == 0 {
+		return invalid(apierror.Field("chapters", apierror.TooFew).With("min", 1))
+	}
+
+	catalog, err := s.missions.GetAllMissions(true)
+	if err != nil {
+		s.internalError(c, message, err)
+		return models.LearningPath{}, false
+	}
+	missions := make(map[primitive.ObjectID]models.Mission, len(catalog))
+	for _, m := range catalog {
+		missions[m.ID] = m
+	}
+
+	path := models.LearningPath{
+		Title:       title,
+		Description: strings.TrimSpace(input.Description),
+		Chapters:    make([]models.PathChapter, 0, len(input.Chapters)),
+	}
+	// position guarda el lugar de cada misión en el recorrido de la ruta y
+	// fields, el campo en que se indicó
+	position := make(map[primitive.ObjectID]int)
+	fields := make(map[primitive.ObjectID]string)
+	for i, chapterInput := range input.Chapters {
+		chapter := models.PathChapter{
+			Title:      strings.TrimSpace(chapterInput.Title),
+			MissionIDs: make([]primitive.ObjectID, 0, len(chapterInput.MissionIDs)),
+		}
+		if chapter.Title == "" {
+			return invalid(apierror.Field(fmt.Sprintf("chapters[%d].title", i), apierror.Required))
+		}
+		if len(chapterInput.MissionIDs) == 0 {
+			return invalid(apierror.Field(fmt.Sprintf("chapters[%d].mission_ids", i), apierror.TooFew).With("min", 1))
+		}
+		for j, hex := range chapterInput.MissionIDs {
+			field := fmt.Sprintf("chapters[%d].mission_ids[%d]", i, j)
+			id, err := primitive.ObjectIDFromHex(hex)
+			if err != nil {
+				return invalid(apierror.Field(field, apierror.InvalidObjectID))
+			}
+			if _, ok := missions[id]; !ok {
+				return invalid(apierror.Field(field, apierror.NotFound))
+			}
+			if _, repeated := position[id]; repeated {
+				return invalid(apierror.Field(field, apierror.Duplicate))
+			}
+			position[id] = len(position)
+			fields[id] = field
+			chapter.MissionIDs = append(chapter.MissionIDs, id)
+		}
+		path.Chapters = append(path.Chapters, chapter)
+	}
+
+	// Los prerrequisitos que están en la ruta deben recorrerse antes
+	for _, id := range path.MissionIDs() {
+		mission := missions[id]
+		for _, prerequisite := range mission.Prerequisites {
+			if at, ok := position[prerequisite]; ok && at > position[id] {
+				return invalid(apierror.Field(fields[id], apierror.PrerequisiteOrder).With("prerequisite", missions[prerequisite].Title))
+			}
+		}
+	}
+	return path, true
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuizInput describe el cuestionario al crear o editar una misión.
+// @Description Cuestionario de una misión
+type QuizInput struct {
+	PassingScore float64             `json:"passing_score" example:"70"`
+	MaxAttempts  int                 `json:"max_attempts" example:"3"`
+	Questions    []QuizQuestionInput `json:"questions"`
+}
+
+// QuizQuestionInput describe una pregunta del cuestionario. Según el tipo se
+// indica correct_option (opción múltiple), numeric_answer y tolerance
+// (numérica) o accepted_answers (texto corto).
+// @Description Pregunta de un cuestionario
+type QuizQuestionInput struct {
+	ID              string   `json:"id,omitempty" example:"60a7b97f5e41c42e7c2e30b8"`
+	Type            string   `json:"type" example:"multiple_choice"`
+	Prompt          string   `json:"prompt" example:"¿Cuál es el planeta rojo?"`
+	Options         []string `json:"options,omitempty"`
+	CorrectOption   *int     `json:"correct_option,omitempty" example:"1"`
+	NumericAnswer   *float64 `json:"numeric_answer,omitempty"`
+	Tolerance       float64  `json:"tolerance,omitempty"`
+	AcceptedAnswers []string `json:"accepted_answers,omitempty"`
+}
+
+// SubmitQuizRequest es el envío de respuestas de un cuestionario.
+// @Description Respuestas a un cuestionario
+type SubmitQuizRequest struct {
+	Answers []QuizAnswerInput `json:"answers" binding:"required"`
+}
+
+// QuizAnswerInput es la respuesta a una pregunta: option para opción
+// múltiple, number para numéricas y text para texto corto.
+// @Description Respuesta a una pregunta
+type QuizAnswerInput struct {
+	QuestionID string   `json:"question_id" binding:"required" example:"60a7b97f5e41c42e7c2e30b8"`
+	Option     *int     `json:"option,omitempty" example:"1"`
+	Number     *float64 `json:"number,omitempty"`
+	Text       string   `json:"text,omitempty"`
+}
+
+// maxQuizAttempts es el máximo de intentos que puede configurar un cuestionario.
+const maxQuizAttempts = 10
+
+// buildQuiz valida el cuestionario recibido y lo convierte al modelo. Las
+// preguntas que ya existían conservan su id.
+func buildQuiz(input QuizInput) (*models.Quiz, error) {
+	if len(input.Questions) == 0 {
+		return nil, apierror.Field("quiz.questions", apierror.TooFew).With("min", 1)
+	}
+	passingScore := input.PassingScore
+	if passingScore == 0 {
+		passingScore = models.DefaultPassingScore
+	}
+	if passingScore < 0 || passingScore > 100 {
+		return nil, apierror.Field("quiz.passing_score", apierror.OutOfRange).With("min", 0).With("max", 100)
+	}
+
+	maxAttempts := input.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = models.DefaultMaxAttempts
+	}
+	if maxAttempts < 1 || maxAttempts > maxQuizAttempts {
+		return nil, apierror.Field("quiz.max_attempts", apierror.OutOfRange).With("min", 1).With("max", maxQuizAttempts)
+	}
+
+	quiz := &models.Quiz{PassingScore: passingScore, MaxAttempts: maxAttempts}
+	seen := make(map[primitive.ObjectID]bool, len(input.Questions))
+	for i, q := range input.Questions {
+		field := fmt.Sprintf("quiz.questions[%d].", i)
+		question := models.QuizQuestion{
+			ID:     primitive.NewObjectID(),
+			Type:   models.QuestionType(q.Type),
+			Prompt: strings.TrimSpace(q.Prompt),
+		}
+		if q.ID != "" {
+			id, err := primitive.ObjectIDFromHex(q.ID)
+			if err != nil {
+				return nil, apierror.Field(field+"id", apierror.InvalidObjectID)
+			}
+			question.ID = id
+		}
+		if seen[question.ID] {
+			return nil, apierror.Field(field+"id", apierror.Duplicate)
+		}
+		seen[question.ID] = true
+		if question.Prompt == "" {
+			return nil, apierror.Field(field+"prompt", apierror.Required)
+		}
+
+		switch question.Type {
+		case models.QuestionMultipleChoice:
+			if len(q.Options) < 2 {
+				return nil, apierror.Field(field+"options", apierror.TooFew).With("min", 2)
+			}
+			if q.CorrectOption == nil || *q.CorrectOption < 0 || *q.CorrectOption >= len(q.Options) {
+				return nil, apierror.Field(field+"correct_option", apierror.OutOfRange).With("min", 0).With("max", len(q.Options)-1)
+			}
+			question.Options = q.Options
+			question.CorrectOption = q.CorrectOption
+		case models.QuestionNumeric:
+			if q.NumericAnswer == nil {
+				return nil, apierror.Field(field+"numeric_answer", apierror.Required)
+			}
+			if q.Tolerance < 0 {
+				return nil, apierror.Field(field+"tolerance", apierror.TooSmall).With("min", 0)
+			}
+			question.NumericAnswer = q.NumericAnswer
+			question.Tolerance = q.Tolerance
+		case models.QuestionShortText:
+			for _, answer := range q.AcceptedAnswers {
+				if answer = strings.TrimSpace(answer); answer != "" {
+					question.AcceptedAnswers = append(question.AcceptedAnswers, answer)
+				}
+			}
+			if len(question.AcceptedAnswers) == 0 {
+				return nil, apierror.Field(field+"accepted_answers", apierror.TooFew).With("min", 1)
+			}
+		default:
+			return nil, apierror.Field(field+"type", apierror.UnknownValue).
+				With("allowed", strings.Join([]string{string(models.QuestionMultipleChoice), string(models.QuestionNumeric), string(models.QuestionShortText)}, ", "))
+		}
+		quiz.Questions = append(quiz.Questions, question)
+	}
+	return quiz, nil
+}
+
+// SubmitQuiz godoc
+// @Summary Envía las respuestas de un cuestionario
+// @Description Califica en el servidor las respuestas al cuestionario de una misión iniciada y guarda el intento. Si el puntaje alcanza el mínimo para aprobar, la misión se completa. Cada cuestionario admite maxAttempts intentos; el intento solo incluye las respuestas calificadas si aprobó o si fue el último disponible.
+// @Tags Missions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param answers body SubmitQuizRequest true "Respuestas"
+// @Success 200 {object} map[string]interface{} "Intento calificado"
+// @Failure 400 {object} apierror.Response "Datos inválidos, la misión no tiene cuestionario o no fue iniciada"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 409 {object} apierror.Response "La misión no está en curso o no quedan intentos"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/{id}/submit [post]
+func (s *Server) SubmitQuiz(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input SubmitQuizRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	answers := make([]models.QuizAnswer, 0, len(input.Answers))
+	for i, a := range input.Answers {
+		questionID, err := primitive.ObjectIDFromHex(a.QuestionID)
+		if err != nil {
+			fail(c, apierror.Invalid(apierror.Field(fmt.Sprintf("answers[%d].question_id", i), apierror.InvalidObjectID)))
+			return
+		}
+		answers = append(answers, models.QuizAnswer{QuestionID: questionID, Option: a.Option, Number: a.Number, Text: a.Text})
+	}
+
+	mission, err := s.missions.GetMissionByID(missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "Error al calificar el cuestionario", err)
+		}
+		return
+	}
+	if mission.Quiz == nil {
+		fail(c, apierror.New(apierror.NoQuiz))
+		return
+	}
+
+	progress, err := s.progress.FindMissionProgress(userObjID, missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotStarted))
+		} else {
+			s.internalError(c, "Error al consultar el progreso", err)
+		}
+		return
+	}
+	if progress.Status != models.ProgressStarted {
+		fail(c, apierror.New(apierror.InvalidTransition).With("action", "submit_quiz").With("status", progress.Status))
+		return
+	}
+
+	previous, err := s.quizzes.GetQuizAttemptSummary(userObjID, missionObjID)
+	if err != nil {
+		s.internalError(c, "Error al obtener los intentos", err)
+		return
+	}
+	limit := mission.Quiz.AttemptLimit()
+	if previous.Attempts >= limit {
+		fail(c, apierror.New(apierror.QuizAttemptsExhausted).With("max", limit))
+		return
+	}
+
+	now := s.now()
+	graded, correct, score := mission.Quiz.Grade(answers)
+	attempt := models.QuizAttempt{
+		ID:          primitive.NewObjectID(),
+		UserID:      userObjID,
+		MissionID:   missionObjID,
+		Number:      previous.Attempts + 1,
+		Answers:     graded,
+		Correct:     correct,
+		Total:       len(mission.Quiz.Questions),
+		Score:       score,
+		Passed:      mission.Quiz.Passed(score),
+		SubmittedAt: now,
+	}
+	// El número del intento es único por usuario y misión: si otro envío
+	// simultáneo ya lo ocupó, este no cuenta y no supera el límite
+	if err := s.quizzes.InsertQuizAttempt(attempt); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			fail(c, apierror.New(apierror.ProgressConflict))
+		} else {
+			s.internalError(c, "Error al guardar el intento", err)
+		}
+		return
+	}
+
+	// Aprobar completa la misión por la misma vía que /missions/complete,
+	// siempre que no le falten pasos obligatorios
+	missionCompleted, xpAwarded := false, 0
+	if attempt.Passed && progress.RequiredStepsDone(*mission) {
+		if err := progress.Complete(*mission, now); err != nil {
+			s.internalError(c, "Error al completar la misión", err)
+			return
+		}
+		err := s.saveProgress(*progress, models.ProgressStarted)
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			s.internalError(c, "Error al completar la misión", err)
+			return
+		}
+		if err == nil {
+			missionCompleted, xpAwarded = true, progress.XPAwarded
+		}
+	}
+
+	remaining := limit - attempt.Number
+	shown := attempt
+	if !attempt.Passed && remaining > 0 {
+		shown = attempt.Summary()
+	}
+	response := gin.H{
+		"attempt":           shown,
+		"attemptsRemaining": remaining,
+		"passingScore":      mission.Quiz.PassingScore,
+		"missionCompleted":  missionCompleted,
+		"xpAwarded":         xpAwarded,
+	}
+	if missionCompleted {
+		response["achievements"] = s.checkAchievements(userObjID)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// attemptOrder es el orden de los intentos: por fecha de envío y luego por ID.
+var attemptOrder = listOrder[models.QuizAttempt, dateKey]{
+	key:  func(a models.QuizAttempt) dateKey { return dateKey{Date: a.SubmittedAt, ID: a.ID} },
+	item: func(k dateKey) models.QuizAttempt { return models.QuizAttempt{ID: k.ID, SubmittedAt: k.Date} },
+}
+
+// GetQuizAttempts godoc
+// @Summary Lista los intentos de un cuestionario
+// @Description Devuelve una página de los intentos calificados del usuario en el cuestionario de una misión, del más antiguo al más reciente. Las respuestas calificadas solo se incluyen cuando el usuario ya aprobó o agotó sus intentos. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Missions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.QuizAttempt]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "ID de misión o parámetros inválidos"
+// @Failure 401 {object} apierror.Response
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 500 {object} apierror.Response
+// @Router /missions/{id}/attempts [get]
+func (s *Server) GetQuizAttempts(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return
+	}
+	page, ok := parsePage(c, listSpec(models.QuizAttempt{}))
+	if !ok {
+		return
+	}
+	query, ok := attemptOrder.query(c, page)
+	if !ok {
+		return
+	}
+
+	mission, err := s.missions.GetMissionByID(missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, "Error al obtener los intentos", err)
+		}
+		return
+	}
+	// Las respuestas se revelan según todos los intentos, no solo los de la página
+	summary, err := s.quizzes.GetQuizAttemptSummary(userObjID, missionObjID)
+	if err != nil {
+		s.internalError(c, "Error al obtener los intentos", err)
+		return
+	}
+	attempts, err := s.quizzes.GetQuizAttempts(userObjID, missionObjID, query)
+	if err != nil {
+		s.internalError(c, "Error al obtener los intentos", err)
+		return
+	}
+	attempts, next := attemptOrder.next(c, page, attempts)
+	if !answersRevealed(mission.Quiz, summary) {
+		for i := range attempts {
+			attempts[i] = attempts[i].Summary()
+		}
+	}
+	respondPage(s, c, page, attempts, next)
+}
+
+// answersRevealed indica si ya se pueden mostrar las respuestas calificadas:
+// cuando algún intento aprobó o no quedan intentos. Sin cuestionario no hay
+// nada que volver a intentar.
+func answersRevealed(quiz *models.Quiz, summary database.QuizAttemptSummary) bool {
+	return quiz == nil || summary.Passed || summary.Attempts >= quiz.AttemptLimit()
+}
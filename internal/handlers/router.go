@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/middleware"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// NewRouter construye el router de la API con todas sus rutas. Lo usan tanto
+// cmd/main.go como las pruebas.
+func NewRouter(deps Deps) *gin.Engine {
+	s := NewServer(deps)
+	auth := middleware.JWTAuthMiddleware(s.tokens, s.IsSessionActive)
+	// Cada handler usa los repositorios de la organización de la petición
+	h := s.scoped
+
+	// Los errores de validación nombran los campos como en el JSON
+	apierror.UseJSONFieldNames()
+
+	router := gin.Default()
+	router.Use(middleware.RequestID())
+	router.Use(cors.Default())
+	router.Use(middleware.TenantMiddleware(s.TenantExists))
+	// Agregar Swagger UI
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Grupo de endpoints de autenticación
+	authGroup := router.Group("/auth")
+	{
+		authGroup.POST("/register", h((*Server).Register))
+		authGroup.POST("/login", h((*Server).Login))
+		authGroup.POST("/refresh", h((*Server).Refresh))
+		authGroup.POST("/logout", auth, h((*Server).Logout))
+	}
+
+	// Endpoints protegidos con JWT, exclusivos para administradores
+	admin := router.Group("/admin")
+	admin.Use(auth, middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.POST("/missions/create", h((*Server).CreateMission))
+		admin.GET("/missions", h((*Server).ListMissionsAdmin))
+		admin.PUT("/missions/:id", h((*Server).UpdateMission))
+		admin.PATCH("/missions/:id", h((*Server).PatchMission))
+		admin.DELETE("/missions/:id", h((*Server).DeleteMission))
+		admin.PUT("/missions/:id/translations/:locale", h((*Server).PutMissionTranslation))
+		admin.DELETE("/missions/:id/translations/:locale", h((*Server).DeleteMissionTranslation))
+		admin.GET("/translations/missing", h((*Server).GetMissingTranslations))
+		admin.GET("/achievements", h((*Server).ListAchievements))
+		admin.POST("/achievements", h((*Server).CreateAchievement))
+		admin.PATCH("/achievements/:id", h((*Server).PatchAchievement))
+		admin.DELETE("/achievements/:id", h((*Server).DeleteAchievement))
+		admin.POST("/paths", h((*Server).CreatePath))
+		admin.PUT("/paths/:id", h((*Server).UpdatePath))
+		admin.DELETE("/paths/:id", h((*Server).DeletePath))
+
+		// Solo los administradores de la organización por defecto dan de alta
+		// las demás
+		platform := admin.Group("/tenants")
+		platform.Use(middleware.RequireTenant(models.DefaultTenantID))
+		platform.POST("", s.CreateTenant)
+		platform.GET("", s.ListTenants)
+	}
+
+	missions := router.Group("/missions")
+	missions.Use(auth)
+	{
+		missions.GET("/all", h((*Server).GetAllMissions))
+		missions.GET("/search", h((*Server).SearchMissions))
+		missions.POST("/start", h((*Server).StartMission))
+		missions.POST("/complete", h((*Server).CompleteMission))
+		missions.POST("/pause", h((*Server).PauseMission))
+		missions.POST("/resume", h((*Server).ResumeMission))
+		missions.POST("/abandon", h((*Server).AbandonMission))
+		missions.POST("/steps/complete", h((*Server).CompleteMissionStep))
+		missions.POST("/:id/submit", h((*Server).SubmitQuiz))
+		missions.GET("/:id/attempts", h((*Server).GetQuizAttempts))
+		missions.GET("/progress", h((*Server).GetProgress))
+		missions.GET("/active", h((*Server).GetActiveMissions))
+		missions.GET("/completed", h((*Server).GetCompletedMissions))
+		missions.GET("/statistics", h((*Server).GetStatistics))
+		missions.GET("/leaderboard/me", h((*Server).GetMyLeaderboardPosition))
+	}
+
+	// Rutas de aprendizaje con el avance del usuario
+	paths := router.Group("/paths")
+	paths.Use(auth)
+	{
+		paths.GET("", h((*Server).ListPaths))
+		paths.GET("/:id", h((*Server).GetPath))
+	}
+
+	// Clases: los docentes las crean y siguen el avance de sus estudiantes,
+	// que se inscriben con el código de la clase
+	classrooms := router.Group("/classrooms")
+	classrooms.Use(auth)
+	{
+		classrooms.POST("/join", middleware.RequireRole(models.RoleStudent), h((*Server).JoinClassroom))
+
+		teacher := classrooms.Group("")
+		teacher.Use(middleware.RequireRole(models.RoleTeacher, models.RoleAdmin))
+		teacher.POST("", h((*Server).CreateClassroom))
+		teacher.GET("", h((*Server).ListClassrooms))
+		teacher.GET("/:id/dashboard", h((*Server).GetClassroomDashboard))
+		teacher.GET("/:id/students/:studentId", h((*Server).GetClassroomStudentReport))
+		teacher.DELETE("/:id/students/:studentId", h((*Server).RemoveClassroomStudent))
+	}
+
+	// Misiones asignadas por los docentes a sus clases o estudiantes
+	assignments := router.Group("/assignments")
+	assignments.Use(auth, middleware.RequireRole(models.RoleTeacher, models.RoleAdmin))
+	{
+		assignments.POST("", h((*Server).CreateAssignment))
+		assignments.GET("", h((*Server).ListAssignments))
+		assignments.GET("/:id/report", h((*Server).GetAssignmentReport))
+	}
+
+	// Endpoints públicos
+	publicMissions := router.Group("/missions")
+	{
+		publicMissions.GET("/leaderboard", h((*Server).GetLeaderboard))
+		publicMissions.GET("/overview", h((*Server).GetMissionsOverview))
+	}
+
+	// Endpoints del usuario autenticado
+	me := router.Group("/me")
+	me.Use(auth)
+	{
+		me.GET("/achievements", h((*Server).GetMyAchievements))
+		me.GET("/streak", h((*Server).GetMyStreak))
+		me.PATCH("/preferences", h((*Server).UpdatePreferences))
+		me.GET("/classrooms", h((*Server).GetMyClassrooms))
+		me.DELETE("/classrooms/:id", h((*Server).LeaveClassroom))
+		me.GET("/assignments", h((*Server).GetMyAssignments))
+	}
+
+	mission := router.Group("/mission")
+	mission.Use(auth)
+	{
+		mission.GET("/:id", h((*Server).GetMissionByID))
+	}
+
+	return router
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/pagination"
+	"explorax-backend/internal/textnorm"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	// snippetLength es el largo aproximado, en caracteres, del fragmento de
+	// la descripción que se devuelve alrededor de la primera coincidencia
+	snippetLength = 160
+)
+
+// MissionHighlights son los campos de una misión en que se encontró la
+// búsqueda, con las palabras coincidentes entre <mark> y </mark>. El resto
+// del texto está escapado como HTML.
+type MissionHighlights struct {
+	Title string `json:"title,omitempty" example:"Explorar <mark>Marte</mark>"`
+	// Description es un fragmento de la descripción alrededor de la primera
+	// coincidencia, con "…" si se recortó
+	Description string   `json:"description,omitempty" example:"…la superficie de <mark>Marte</mark> en un rover…"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// MissionSearchHit es un resultado de la búsqueda de misiones.
+type MissionSearchHit struct {
+	Mission    models.Mission    `json:"mission"`
+	Score      float64           `json:"score" example:"10.5"`
+	Highlights MissionHighlights `json:"highlights"`
+}
+
+// searchKey es la clave de orden del último resultado de una página, que el
+// cursor guarda para leer la siguiente con MissionSearch.After.
+type searchKey struct {
+	Score float64            `json:"s"`
+	ID    primitive.ObjectID `json:"i"`
+}
+
+func newSearchKey(match database.MissionMatch) interface{} {
+	return searchKey{Score: match.Score, ID: match.Mission.ID}
+}
+
+// searchFields son los campos que se pueden pedir en fields.
+var searchFields = pagination.FieldsOf(MissionSearchHit{})
+
+// SearchMissions godoc
+// @Summary Busca misiones por texto
+// @Description Busca las palabras de q en el título, la descripción y las etiquetas de las misiones disponibles, sin distinguir mayúsculas ni acentos y reconociendo los plurales. Los resultados se ordenan por relevancia: pesa más una coincidencia en el título que en las etiquetas, y en estas más que en la descripción. Se busca en el idioma original de las misiones, pero se devuelven en el idioma del usuario; cada resultado incluye fragmentos de ese texto con las coincidencias marcadas con <mark>. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Missions
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Texto a buscar"
+// @Param limit query int false "Resultados por página, hasta 50" default(20)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param sort query string false "Solo relevance" default(relevance)
+// @Param fields query string false "Campos de cada resultado, separados por comas: mission, score o highlights"
+// @Success 200 {object} Page[MissionSearchHit]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "No se pudo buscar"
+// @Router /missions/search [get]
+func (s *Server) SearchMissions(c *gin.Context) {
+	terms := textnorm.Terms(c.Query("q"))
+	if len(terms) == 0 {
+		fail(c, apierror.InvalidQuery(apierror.Field("q", apierror.NoText)))
+		return
+	}
+	text := strings.Join(terms, " ")
+	page, ok := parsePage(c, pagination.Spec{
+		DefaultLimit: defaultSearchLimit,
+		MaxLimit:     maxSearchLimit,
+		Sorts:        []string{"relevance"},
+		Fields:       searchFields,
+	})
+	if !ok {
+		return
+	}
+
+	search := database.MissionSearch{Text: text, Limit: page.Fetch()}
+	var key searchKey
+	if found, apiErr := page.After(&key); apiErr != nil {
+		fail(c, apiErr)
+		return
+	} else if found {
+		search.After = &database.MissionMatch{Mission: models.Mission{ID: key.ID}, Score: key.Score}
+	}
+	matches, err := s.missions.SearchMissions(search)
+	if err != nil {
+		s.internalError(c, "No se pudo buscar", err)
+		return
+	}
+
+	matches, next := pagination.NextAfter(c, page, matches, newSearchKey)
+	stems := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		stems[textnorm.Stem(term)] = true
+	}
+	locale := s.locale(c)
+	hits := make([]MissionSearchHit, 0, len(matches))
+	for _, match := range matches {
+		mission := match.Mission.Public().Localize(locale)
+		highlights := MissionHighlights{
+			Title:       highlight(mission.Title, stems, 0),
+			Description: highlight(mission.Description, stems, snippetLength),
+		}
+		for _, tag := range mission.Tags {
+			if marked := highlight(tag, stems, 0); marked != "" {
+				highlights.Tags = append(highlights.Tags, marked)
+			}
+		}
+		hits = append(hits, MissionSearchHit{Mission: mission, Score: match.Score, Highlights: highlights})
+	}
+	respondPage(s, c, page, hits, next)
+}
+
+// wordSpan es la posición, en bytes, de una palabra dentro de un texto.
+type wordSpan struct {
+	start, end int
+}
+
+// words devuelve las posiciones de las palabras de text.
+func words(text string) []wordSpan {
+	var spans []wordSpan
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, wordSpan{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, wordSpan{start, len(text)})
+	}
+	return spans
+}
+
+// highlight marca con <mark> las palabras de text cuya raíz está en stems y
+// escapa el resto como HTML. Con maxLength mayor que 0, si el texto es más
+// largo devuelve solo un fragmento de unos maxLength caracteres alrededor de
+// la primera coincidencia, sin cortar palabras. Si nada coincide devuelve "".
+func highlight(text string, stems map[string]bool, maxLength int) string {
+	spans := words(text)
+	var matched []wordSpan
+	for _, span := range spans {
+		if stems[textnorm.Stem(textnorm.Fold(text[span.start:span.end]))] {
+			matched = append(matched, span)
+		}
+	}
+	if len(matched) == 0 {
+		return ""
+	}
+
+	from, to := 0, len(text)
+	if maxLength > 0 && utf8.RuneCountInString(text) > maxLength {
+		// La ventana empieza un tercio antes de la primera coincidencia,
+		// en el inicio de una palabra, y termina al final de otra
+		from = matched[0].start
+		for i := len(spans) - 1; i >= 0; i-- {
+			if spans[i].start < matched[0].start && utf8.RuneCountInString(text[spans[i].start:matched[0].start]) <= maxLength/3 {
+				from = spans[i].start
+			}
+		}
+		to = from
+		for _, span := range spans {
+			if span.start >= from && utf8.RuneCountInString(text[from:span.end]) <= maxLength {
+				to = span.end
+			}
+		}
+		if to < matched[0].end {
+			to = matched[0].end
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, span := range matched {
+		if span.start < from || span.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:span.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[span.start:span.end]))
+		b.WriteString("</mark>")
+		pos = span.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
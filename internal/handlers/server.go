@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/middleware"
+	"explorax-backend/internal/models"
+	"explorax-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Deps reúne las dependencias de los handlers. Clock, Logger y Levels son
+// opcionales: por defecto se usan time.Now, el logger estándar y
+// models.DefaultLevelCurve. Los repositorios son los de la organización
+// models.DefaultTenantID; Scope devuelve los de otra. Sin Scope ni Tenants
+// todas las peticiones usan los mismos repositorios.
+type Deps struct {
+	Users        database.UserStore
+	Sessions     database.SessionStore
+	Missions     database.MissionStore
+	Progress     database.ProgressStore
+	Quizzes      database.QuizStore
+	Achievements database.AchievementStore
+	Leaderboard  database.LeaderboardStore
+	Classrooms   database.ClassroomStore
+	Assignments  database.AssignmentStore
+	Paths        database.PathStore
+	Tenants      database.TenantStore
+	Scope        func(tenantID string) database.Store
+	Tokens       utils.TokenSigner
+	Clock        func() time.Time
+	Logger       *log.Logger
+	Levels       models.LevelCurve
+}
+
+// StoreDeps arma las dependencias a partir de un único Store que implementa
+// todos los repositorios.
+func StoreDeps(store database.Store, tokens utils.TokenSigner) Deps {
+	return Deps{
+		Users:        store,
+		Sessions:     store,
+		Missions:     store,
+		Progress:     store,
+		Quizzes:      store,
+		Achievements: store,
+		Leaderboard:  store,
+		Classrooms:   store,
+		Assignments:  store,
+		Paths:        store,
+		Tenants:      store,
+		Scope:        store.ForTenant,
+		Tokens:       tokens,
+	}
+}
+
+// Server agrupa los handlers HTTP junto con sus dependencias.
+type Server struct {
+	users        database.UserStore
+	sessions     database.SessionStore
+	missions     database.MissionStore
+	progress     database.ProgressStore
+	quizzes      database.QuizStore
+	achievements database.AchievementStore
+	leaderboard  database.LeaderboardStore
+	classrooms   database.ClassroomStore
+	assignments  database.AssignmentStore
+	paths        database.PathStore
+	tenants      database.TenantStore
+	scope        func(tenantID string) database.Store
+	tokens       utils.TokenSigner
+	now          func() time.Time
+	logger       *log.Logger
+	levels       models.LevelCurve
+}
+
+// NewServer construye un Server con las dependencias indicadas.
+func NewServer(deps Deps) *Server {
+	s := &Server{
+		users:        deps.Users,
+		sessions:     deps.Sessions,
+		missions:     deps.Missions,
+		progress:     deps.Progress,
+		quizzes:      deps.Quizzes,
+		achievements: deps.Achievements,
+		leaderboard:  deps.Leaderboard,
+		classrooms:   deps.Classrooms,
+		assignments:  deps.Assignments,
+		paths:        deps.Paths,
+		tenants:      deps.Tenants,
+		scope:        deps.Scope,
+		tokens:       deps.Tokens,
+		now:          deps.Clock,
+		logger:       deps.Logger,
+		levels:       deps.Levels,
+	}
+	if s.now == nil {
+		s.now = time.Now
+	}
+	if s.logger == nil {
+		s.logger = log.Default()
+	}
+	if !s.levels.Valid() {
+		s.levels = models.DefaultLevelCurve
+	}
+	return s
+}
+
+// forTenant devuelve una copia del Server cuyos repositorios están limitados
+// a la organización tenantID.
+func (s *Server) forTenant(tenantID string) *Server {
+	if s.scope == nil {
+		return s
+	}
+	store := s.scope(tenantID)
+	scoped := *s
+	scoped.users = store
+	scoped.sessions = store
+	scoped.missions = store
+	scoped.progress = store
+	scoped.quizzes = store
+	scoped.achievements = store
+	scoped.leaderboard = store
+	scoped.classrooms = store
+	scoped.assignments = store
+	scoped.paths = store
+	return &scoped
+}
+
+// scoped adapta un handler para que atienda la petición con los repositorios
+// de la organización que resolvieron TenantMiddleware o JWTAuthMiddleware.
+func (s *Server) scoped(handler func(*Server, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scoped := s.forTenant(middleware.CurrentTenant(c))
+		// fail lo usa para responder en el idioma preferido por el usuario
+		c.Set(serverKey, scoped)
+		handler(scoped, c)
+	}
+}
+
+// IsSessionActive indica si la sesión de la organización sigue activa según
+// el reloj del servidor. Es el SessionChecker que usa JWTAuthMiddleware.
+func (s *Server) IsSessionActive(tenantID string, sessionID primitive.ObjectID) (bool, error) {
+	return s.forTenant(tenantID).sessions.IsSessionActive(sessionID, s.now())
+}
+
+// TenantExists indica si la organización está registrada. Es el
+// TenantChecker que usa TenantMiddleware.
+func (s *Server) TenantExists(tenantID string) (bool, error) {
+	if s.tenants == nil {
+		return tenantID == models.DefaultTenantID, nil
+	}
+	_, err := s.tenants.GetTenant(tenantID)
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// currentUserID obtiene el ID del usuario autenticado desde el contexto. Si no
+// es posible, responde con el error correspondiente y devuelve false.
+func currentUserID(c *gin.Context) (primitive.ObjectID, bool) {
+	userIDVal, exists := c.Get("user_id")
+	if !exists {
+		fail(c, apierror.New(apierror.Unauthenticated))
+		return primitive.NilObjectID, false
+	}
+
+	userIDStr, ok := userIDVal.(string)
+	if !ok {
+		fail(c, apierror.New(apierror.TokenInvalid))
+		return primitive.NilObjectID, false
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		fail(c, apierror.New(apierror.TokenInvalid))
+		return primitive.NilObjectID, false
+	}
+	return userObjID, true
+}
+
+// currentRole devuelve el rol del usuario autenticado, que JWTAuthMiddleware
+// toma del token.
+func currentRole(c *gin.Context) models.Role {
+	role, _ := c.Get("role")
+	name, _ := role.(string)
+	return models.Role(name)
+}
+
+// internalError registra el error original, con el ID de la petición, y
+// responde con el error genérico apierror.InternalError.
+func (s *Server) internalError(c *gin.Context, message string, err error) {
+	s.logger.Printf("%s %s %s: %s: %v", c.GetString("request_id"), c.Request.Method, c.FullPath(), message, err)
+	fail(c, apierror.New(apierror.InternalError))
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultHeatmapDays = 365
+	maxHeatmapDays     = 366
+)
+
+// StreakResponse es el estado de la racha del usuario junto con su
+// calendario de actividad.
+// @Description Racha diaria y calendario de actividad
+type StreakResponse struct {
+	Timezone      string `json:"timezone" example:"America/Guatemala"`
+	Current       int    `json:"current" example:"4"`
+	Longest       int    `json:"longest" example:"12"`
+	LastActiveDay string `json:"lastActiveDay,omitempty" example:"2025-03-14"`
+	ActiveToday   bool   `json:"activeToday" example:"true"`
+	Freezes       int    `json:"freezes" example:"1"`
+	// Heatmap tiene un elemento por día, del más antiguo a hoy
+	Heatmap []models.HeatmapDay `json:"heatmap"`
+}
+
+// recordStreak suma la completación del instante at a la racha del usuario,
+// contada en su zona horaria. Los errores se registran sin afectar la
+// respuesta: la misión ya quedó completada.
+func (s *Server) recordStreak(userID primitive.ObjectID, at time.Time) {
+	user, err := s.users.FindUserByID(userID)
+	if err == nil {
+		err = s.users.UpdateUserStreak(userID, user.Streak.Record(at, user.Location()))
+	}
+	if err != nil {
+		s.logger.Printf("No se pudo actualizar la racha del usuario %s: %v", userID.Hex(), err)
+	}
+}
+
+// GetMyStreak godoc
+// @Summary Obtiene la racha del usuario
+// @Description Devuelve la racha diaria actual y la más larga, contadas en la zona horaria del usuario, las protecciones disponibles y un calendario con las misiones completadas cada día. Se gana una protección cada 7 días seguidos (máximo 2); cada una cubre un día sin actividad.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Días del calendario, hasta 366" default(365)
+// @Success 200 {object} StreakResponse
+// @Failure 400 {object} apierror.Response "Cantidad de días inválida"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 500 {object} apierror.Response "Error al obtener la racha"
+// @Router /me/streak [get]
+func (s *Server) GetMyStreak(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	days, ok := queryInt(c, "days", defaultHeatmapDays, 1, maxHeatmapDays)
+	if !ok {
+		return
+	}
+
+	user, err := s.users.FindUserByID(userObjID)
+	if err != nil {
+		s.internalError(c, "Error al obtener la racha", err)
+		return
+	}
+	now := s.now()
+	loc := user.Location()
+	from := now.AddDate(0, 0, 1-days)
+	// El calendario empieza a la medianoche del primer día en la zona del usuario
+	y, m, d := from.In(loc).Date()
+	activity, err := s.progress.CountCompletionsByDay(userObjID, time.Date(y, m, d, 0, 0, 0, 0, loc), loc)
+	if err != nil {
+		s.internalError(c, "Error al obtener la racha", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, StreakResponse{
+		Timezone:      loc.String(),
+		Current:       user.Streak.CurrentAt(now, loc),
+		Longest:       user.Streak.Longest,
+		LastActiveDay: user.Streak.LastActiveDay,
+		ActiveToday:   user.Streak.LastActiveDay == models.DayKey(now, loc),
+		Freezes:       user.Streak.Freezes,
+		Heatmap:       models.ActivityHeatmap(activity, user.Streak.FrozenDays, from, now, loc),
+	})
+}
+
+// UpdatePreferencesRequest actualiza las preferencias del usuario.
+// @Description Preferencias del usuario
+type UpdatePreferencesRequest struct {
+	// Timezone es una zona horaria IANA; vacía vuelve a UTC
+	Timezone *string `json:"timezone" example:"America/Guatemala"`
+	// BirthDate (AAAA-MM-DD) decide si el nombre público puede mostrarse
+	BirthDate *string `json:"birth_date" example:"2012-05-01"`
+	// DisplayName solo se muestra en público si el usuario es mayor de edad
+	DisplayName *string `json:"display_name" example:"Exploradora"`
+	// Avatar es uno de models.Avatars; vacío vuelve al asignado por defecto
+	Avatar              *string `json:"avatar" example:"cohete"`
+	HideFromLeaderboard *bool   `json:"hide_from_leaderboard" example:"false"`
+	// Locale es el idioma de las misiones (es, en o pt); vacío usa el de la
+	// cabecera Accept-Language
+	Locale *string `json:"locale" example:"en"`
+}
+
+const (
+	minDisplayNameLength = 3
+	maxDisplayNameLength = 30
+)
+
+// buildUserUpdate valida las preferencias recibidas y las convierte en los
+// cambios a guardar.
+func buildUserUpdate(input UpdatePreferencesRequest, now time.Time) (database.UserUpdate, error) {
+	update := database.UserUpdate{Timezone: input.Timezone, HideFromLeaderboard: input.HideFromLeaderboard}
+	if input.Timezone != nil && !validTimezone(*input.Timezone) {
+		return update, apierror.Field("timezone", apierror.InvalidTimezone)
+	}
+	if input.BirthDate != nil {
+		birthDate, err := parseBirthDate(*input.BirthDate, now)
+		if err != nil {
+			return update, err
+		}
+		update.BirthDate = &birthDate
+	}
+	if input.DisplayName != nil {
+		name := strings.Join(strings.Fields(*input.DisplayName), " ")
+		if n := utf8.RuneCountInString(name); n > 0 && (n < minDisplayNameLength || n > maxDisplayNameLength) {
+			return update, apierror.Field("display_name", apierror.InvalidLength).With("min", minDisplayNameLength).With("max", maxDisplayNameLength)
+		}
+		// Evita que se publique un email como nombre
+		if strings.Contains(name, "@") {
+			return update, apierror.Field("display_name", apierror.InvalidCharacter).With("character", "@")
+		}
+		update.DisplayName = &name
+	}
+	if input.Avatar != nil {
+		if *input.Avatar != "" && !models.ValidAvatar(*input.Avatar) {
+			return update, apierror.Field("avatar", apierror.UnknownValue).With("allowed", strings.Join(models.Avatars, ", "))
+		}
+		update.Avatar = input.Avatar
+	}
+	if input.Locale != nil {
+		if *input.Locale != "" && !models.IsSupportedLocale(*input.Locale) {
+			return update, unknownLocale("locale")
+		}
+		update.Locale = input.Locale
+	}
+	if update == (database.UserUpdate{}) {
+		return update, apierror.Field("", apierror.NoChanges)
+	}
+	return update, nil
+}
+
+// parseBirthDate lee una fecha de nacimiento con el formato AAAA-MM-DD.
+func parseBirthDate(value string, now time.Time) (time.Time, error) {
+	birthDate, err := time.Parse(models.DayLayout, value)
+	if err != nil {
+		return time.Time{}, apierror.Field("birth_date", apierror.InvalidDate).With("format", "YYYY-MM-DD")
+	}
+	if birthDate.After(now) || birthDate.Year() < 1900 {
+		return time.Time{}, apierror.Field("birth_date", apierror.OutOfRange).With("min", "1900-01-01").With("max", now.Format(models.DayLayout))
+	}
+	return birthDate, nil
+}
+
+// UpdatePreferences godoc
+// @Summary Actualiza las preferencias del usuario
+// @Description Modifica solo los campos enviados. La zona horaria define en qué día cuenta cada actividad para la racha. En los rankings públicos los menores de 18 años, o quienes no indicaron su fecha de nacimiento, aparecen con un seudónimo aunque elijan un nombre; con hide_from_leaderboard el usuario deja de aparecer en ellos. Con locale las misiones se muestran en ese idioma, sin importar Accept-Language.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body UpdatePreferencesRequest true "Preferencias a modificar"
+// @Success 200 {object} models.User
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 401 {object} apierror.Response "Usuario no autenticado"
+// @Failure 404 {object} apierror.Response "Usuario no encontrado"
+// @Failure 500 {object} apierror.Response "No se pudieron guardar las preferencias"
+// @Router /me/preferences [patch]
+func (s *Server) UpdatePreferences(c *gin.Context) {
+	userObjID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	update, err := buildUserUpdate(input, s.now())
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	user, err := s.users.UpdateUser(userObjID, update)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.UserNotFound))
+		} else {
+			s.internalError(c, "No se pudieron guardar las preferencias", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// validTimezone indica si tz es una zona horaria IANA conocida o está vacía.
+// Se rechaza "Local" porque dependería de la configuración del servidor.
+func validTimezone(tz string) bool {
+	if tz == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
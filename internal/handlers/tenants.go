@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTenantRequest representa los datos de una organización nueva.
+// @Description Estructura para dar de alta una organización
+type CreateTenantRequest struct {
+	// ID identifica a la organización en la cabecera X-Tenant-ID: de 2 a 40
+	// letras minúsculas, dígitos o guiones
+	ID   string `json:"id" binding:"required" example:"colegio-central"`
+	Name string `json:"name" binding:"required" example:"Colegio Central"`
+}
+
+// CreateTenant godoc
+// @Summary Da de alta una organización
+// @Description Registra una escuela u organización. Sus usuarios se registran e inician sesión enviando su ID en la cabecera X-Tenant-ID y no ven los datos de las demás organizaciones. Solo para administradores de la organización por defecto.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant body CreateTenantRequest true "Datos de la organización"
+// @Success 201 {object} models.Tenant
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador de la organización por defecto"
+// @Failure 409 {object} apierror.Response "Ya existe una organización con ese ID"
+// @Failure 500 {object} apierror.Response "No se pudo crear la organización"
+// @Router /admin/tenants [post]
+func (s *Server) CreateTenant(c *gin.Context) {
+	var input CreateTenantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	name := strings.TrimSpace(input.Name)
+	if !models.ValidTenantID(input.ID) {
+		// De 2 a 40 letras minúsculas, dígitos o guiones
+		fail(c, apierror.Invalid(apierror.Field("id", apierror.InvalidFormat)))
+		return
+	}
+	if name == "" {
+		fail(c, apierror.Invalid(apierror.Field("name", apierror.Required)))
+		return
+	}
+	if input.ID == models.DefaultTenantID {
+		fail(c, apierror.New(apierror.TenantExists))
+		return
+	}
+
+	tenant := models.Tenant{ID: input.ID, Name: name, CreatedAt: s.now()}
+	if err := s.tenants.InsertTenant(tenant); err != nil {
+		if errors.Is(err, database.ErrDuplicate) {
+			fail(c, apierror.New(apierror.TenantExists))
+		} else {
+			s.internalError(c, "No se pudo crear la organización", err)
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, tenant)
+}
+
+// tenantOrder es el orden de las organizaciones: por ID.
+var tenantOrder = listOrder[models.Tenant, string]{
+	key:  func(t models.Tenant) string { return t.ID },
+	item: func(id string) models.Tenant { return models.Tenant{ID: id} },
+}
+
+// ListTenants godoc
+// @Summary Lista las organizaciones
+// @Description Devuelve una página de las organizaciones dadas de alta por ID, sin incluir la organización por defecto. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link. Solo para administradores de la organización por defecto.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Resultados por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[models.Tenant]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Parámetros inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador de la organización por defecto"
+// @Failure 500 {object} apierror.Response "No se pudieron obtener las organizaciones"
+// @Router /admin/tenants [get]
+func (s *Server) ListTenants(c *gin.Context) {
+	page, ok := parsePage(c, listSpec(models.Tenant{}))
+	if !ok {
+		return
+	}
+	query, ok := tenantOrder.query(c, page)
+	if !ok {
+		return
+	}
+	tenants, err := s.tenants.GetTenants(query)
+	if err != nil {
+		s.internalError(c, "No se pudieron obtener las organizaciones", err)
+		return
+	}
+	tenants, next := tenantOrder.next(c, page, tenants)
+	respondPage(s, c, page, tenants, next)
+}
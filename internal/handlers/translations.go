@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/database"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StepTranslationInput traduce un paso de la misión, identificado por su id.
+// @Description Traducción de un paso
+type StepTranslationInput struct {
+	ID          string `json:"id" binding:"required" example:"60a7b97f5e41c42e7c2e30b7"`
+	Title       string `json:"title" example:"Land in the crater"`
+	Description string `json:"description" example:"Choose a safe landing spot"`
+}
+
+// MissionTranslationRequest es la traducción completa de una misión a un
+// idioma; reemplaza la que hubiera. Los textos vacíos se muestran en el
+// idioma original.
+// @Description Traducción de una misión
+type MissionTranslationRequest struct {
+	Title       string                 `json:"title" example:"Explore Mars"`
+	Description string                 `json:"description" example:"Travel across the Martian surface"`
+	Steps       []StepTranslationInput `json:"steps"`
+}
+
+// TranslationStatus es el estado de la traducción de una misión a un idioma.
+// Fields son los textos que faltan traducir, con los nombres de
+// MissionTranslationRequest: "title", "description", "steps.<id>.title" y
+// "steps.<id>.description"; si no falta ninguno, Complete es true.
+type TranslationStatus struct {
+	MissionID primitive.ObjectID `json:"missionId"`
+	Title     string             `json:"title" example:"Explorar Marte"`
+	Locale    string             `json:"locale" example:"en"`
+	Complete  bool               `json:"complete" example:"false"`
+	Fields    []string           `json:"fields" example:"title,description"`
+}
+
+// PutMissionTranslation godoc
+// @Summary Agrega o reemplaza la traducción de una misión
+// @Description Guarda la traducción del título, la descripción y los pasos de la misión a un idioma, reemplazando la anterior. Los textos que no se envían se muestran en el idioma original. No se puede traducir al idioma en que está escrita la misión.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param locale path string true "Idioma: es, en o pt"
+// @Param translation body MissionTranslationRequest true "Textos traducidos"
+// @Success 200 {object} models.Mission
+// @Failure 400 {object} apierror.Response "Datos inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Misión no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo guardar la traducción"
+// @Router /admin/missions/{id}/translations/{locale} [put]
+func (s *Server) PutMissionTranslation(c *gin.Context) {
+	mission, locale, ok := s.translatedMission(c, "No se pudo guardar la traducción")
+	if !ok {
+		return
+	}
+
+	var input MissionTranslationRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+	translation, err := buildTranslation(*mission, input)
+	if err != nil {
+		fail(c, apierror.Invalid(err))
+		return
+	}
+
+	s.applyTranslation(c, mission.ID, locale, &translation, "No se pudo guardar la traducción")
+}
+
+// DeleteMissionTranslation godoc
+// @Summary Elimina la traducción de una misión
+// @Description Elimina la traducción de la misión a un idioma; en ese idioma se vuelve a mostrar el texto original.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID de la misión"
+// @Param locale path string true "Idioma: es, en o pt"
+// @Success 200 {object} models.Mission
+// @Failure 400 {object} apierror.Response "ID de misión o idioma inválido"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 404 {object} apierror.Response "Misión o traducción no encontrada"
+// @Failure 500 {object} apierror.Response "No se pudo eliminar la traducción"
+// @Router /admin/missions/{id}/translations/{locale} [delete]
+func (s *Server) DeleteMissionTranslation(c *gin.Context) {
+	mission, locale, ok := s.translatedMission(c, "No se pudo eliminar la traducción")
+	if !ok {
+		return
+	}
+	if _, exists := mission.Translations[locale]; !exists {
+		fail(c, apierror.New(apierror.TranslationNotFound))
+		return
+	}
+	s.applyTranslation(c, mission.ID, locale, nil, "No se pudo eliminar la traducción")
+}
+
+// GetMissingTranslations godoc
+// @Summary Informa las traducciones pendientes
+// @Description Revisa una página de las misiones no archivadas de la organización, por fecha de creación, y devuelve para cada misión e idioma si está completa y, si no, los textos que faltan traducir. limit cuenta misiones: cada una aparece una vez por idioma revisado. Las misiones globales solo se revisan en la organización por defecto, que es la que puede traducirlas. Los resultados vienen en items y, si hay más, nextCursor trae el cursor de la página siguiente, que también se envía en las cabeceras X-Next-Cursor y Link.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param locale query string false "Revisa solo este idioma"
+// @Param limit query int false "Misiones por página, hasta 100" default(50)
+// @Param cursor query string false "Cursor devuelto en nextCursor"
+// @Param fields query string false "Campos de cada resultado, separados por comas"
+// @Success 200 {object} Page[TranslationStatus]
+// @Header 200 {string} X-Next-Cursor "Cursor de la página siguiente"
+// @Header 200 {string} Link "URL de la página siguiente, con rel=next"
+// @Failure 400 {object} apierror.Response "Idioma o parámetros inválidos"
+// @Failure 403 {object} apierror.Response "Se requiere rol de administrador"
+// @Failure 500 {object} apierror.Response "No se pudo armar el informe"
+// @Router /admin/translations/missing [get]
+func (s *Server) GetMissingTranslations(c *gin.Context) {
+	locales := models.SupportedLocales
+	if locale := c.Query("locale"); locale != "" {
+		if !models.IsSupportedLocale(locale) {
+			fail(c, apierror.InvalidQuery(unknownLocale("locale")))
+			return
+		}
+		locales = []string{locale}
+	}
+	page, ok := parsePage(c, listSpec(TranslationStatus{}))
+	if !ok {
+		return
+	}
+	query, ok := missionOrder.query(c, page)
+	if !ok {
+		return
+	}
+	// Las misiones globales de otra organización no se pueden traducir acá
+	missions, err := s.missions.ListMissions(database.MissionList{
+		OwnOnly: true,
+		After:   query.After,
+		Limit:   query.Limit,
+	})
+	if err != nil {
+		s.internalError(c, "No se pudo armar el informe", err)
+		return
+	}
+	missions, next := missionOrder.next(c, page, missions)
+
+	statuses := make([]TranslationStatus, 0, len(missions)*len(locales))
+	for _, mission := range missions {
+		for _, locale := range locales {
+			fields := mission.MissingTranslations(locale)
+			if fields == nil {
+				fields = []string{}
+			}
+			statuses = append(statuses, TranslationStatus{
+				MissionID: mission.ID,
+				Title:     mission.Title,
+				Locale:    locale,
+				Complete:  len(fields) == 0,
+				Fields:    fields,
+			})
+		}
+	}
+	respondPage(s, c, page, statuses, next)
+}
+
+// translatedMission lee la misión y el idioma de la ruta. Si no son válidos,
+// o el idioma es el original de la misión, responde con el error
+// correspondiente y devuelve false.
+func (s *Server) translatedMission(c *gin.Context, message string) (*models.Mission, string, bool) {
+	missionObjID, ok := missionIDParam(c)
+	if !ok {
+		return nil, "", false
+	}
+	locale := c.Param("locale")
+	if !models.IsSupportedLocale(locale) {
+		fail(c, apierror.New(apierror.UnsupportedLocale).With("allowed", strings.Join(models.SupportedLocales, ", ")))
+		return nil, "", false
+	}
+	mission, err := s.missions.GetMissionByID(missionObjID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, message, err)
+		}
+		return nil, "", false
+	}
+	if locale == mission.SourceLocale() {
+		fail(c, apierror.New(apierror.SourceLocale))
+		return nil, "", false
+	}
+	return mission, locale, true
+}
+
+// applyTranslation guarda, o elimina si es nil, la traducción de la misión y
+// responde con la misión actualizada.
+func (s *Server) applyTranslation(c *gin.Context, missionObjID primitive.ObjectID, locale string, translation *models.MissionTranslation, message string) {
+	update := database.MissionUpdate{Translations: map[string]*models.MissionTranslation{locale: translation}}
+	mission, err := s.missions.UpdateMission(missionObjID, update, s.now())
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			fail(c, apierror.New(apierror.MissionNotFound))
+		} else {
+			s.internalError(c, message, err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, mission)
+}
+
+// buildTranslation valida la traducción recibida contra los pasos de la
+// misión y la convierte en la traducción a guardar.
+func buildTranslation(mission models.Mission, input MissionTranslationRequest) (models.MissionTranslation, error) {
+	translation := models.MissionTranslation{
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+	}
+	seen := make(map[primitive.ObjectID]bool, len(input.Steps))
+	for i, stepInput := range input.Steps {
+		id, err := primitive.ObjectIDFromHex(stepInput.ID)
+		if err != nil {
+			return translation, apierror.Field(fmt.Sprintf("steps[%d].id", i), apierror.InvalidObjectID)
+		}
+		if _, exists := mission.Step(id); !exists {
+			return translation, apierror.Field(fmt.Sprintf("steps[%d].id", i), apierror.NotFound)
+		}
+		if seen[id] {
+			return translation, apierror.Field(fmt.Sprintf("steps[%d].id", i), apierror.Duplicate)
+		}
+		seen[id] = true
+		step := models.StepTranslation{
+			ID:          id,
+			Title:       strings.TrimSpace(stepInput.Title),
+			Description: strings.TrimSpace(stepInput.Description),
+		}
+		if step.Title != "" || step.Description != "" {
+			translation.Steps = append(translation.Steps, step)
+		}
+	}
+	if translation.Title == "" && translation.Description == "" && len(translation.Steps) == 0 {
+		return translation, apierror.Field("", apierror.NoText)
+	}
+	return translation, nil
+}
@@ -0,0 +1,66 @@
+// Package i18n elige el idioma de cada respuesta entre los que admite la API
+// y arma los textos traducidos.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// ContextKey guarda en el contexto de gin el idioma ya resuelto de la
+// petición, por ejemplo el preferido por el usuario autenticado.
+const ContextKey = "locale"
+
+// matcher elige entre models.SupportedLocales; ante la duda prefiere el
+// primero, models.DefaultLocale.
+var matcher = language.NewMatcher(supportedTags())
+
+func supportedTags() []language.Tag {
+	tags := make([]language.Tag, len(models.SupportedLocales))
+	for i, locale := range models.SupportedLocales {
+		tags[i] = language.MustParse(locale)
+	}
+	return tags
+}
+
+// Negotiate elige el idioma soportado que mejor se ajusta a una cabecera
+// Accept-Language: "en-US,en;q=0.9" elige "en". Sin cabecera, o si ninguno
+// coincide, devuelve models.DefaultLocale.
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return models.DefaultLocale
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return models.DefaultLocale
+	}
+	return models.SupportedLocales[index]
+}
+
+// Locale devuelve el idioma ya resuelto para la petición o, si todavía no se
+// resolvió, el negociado con Accept-Language.
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(ContextKey); models.IsSupportedLocale(locale) {
+		return locale
+	}
+	return Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// Format reemplaza cada {nombre} de template por el valor de params con ese
+// nombre; los que no están en params se dejan como están.
+func Format(template string, params map[string]interface{}) string {
+	if len(params) == 0 {
+		return template
+	}
+	replacements := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
@@ -2,58 +2,70 @@ package middleware
 
 import (
 	"fmt"
-	"net/http"
-	"os"
+	"log"
 	"strings"
 
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/utils"
+
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// JWTAuthMiddleware valida el token JWT en el header de la petición.
-func JWTAuthMiddleware() gin.HandlerFunc {
+// SessionChecker informa si la sesión a la que pertenece un token, en la
+// organización tenantID, sigue activa.
+type SessionChecker func(tenantID string, sessionID primitive.ObjectID) (bool, error)
+
+// JWTAuthMiddleware valida el token JWT en el header de la petición y rechaza
+// los tokens cuya sesión haya sido revocada o haya expirado. La organización
+// de la petición pasa a ser la del token; si TenantMiddleware ya resolvió otra
+// desde la cabecera, responde 403.
+func JWTAuthMiddleware(tokens utils.TokenSigner, checkSession SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "No se proporcionó token"})
+			apierror.Respond(c, apierror.New(apierror.TokenMissing))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Formato de token inválido"})
+			apierror.Respond(c, apierror.New(apierror.TokenInvalid))
 			return
 		}
 
-		tokenString := parts[1]
-		secret := os.Getenv("JWT_SECRET")
-
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+		claims, err := tokens.ParseAccessToken(parts[1])
+		if err != nil {
+			apierror.Respond(c, apierror.New(apierror.TokenInvalid))
 			return
 		}
 
-		// Extraer el user_id del token y almacenarlo en el contexto de la petición
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			userID, exists := claims["user_id"]
-			if !exists {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token no contiene user_id"})
-				return
-			}
-
-			userIDStr, ok := userID.(string)
-			if !ok {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Formato incorrecto en user_id"})
-				return
-			}
-
-			fmt.Println("✅ Token válido para usuario:", userIDStr)
-			c.Set("user_id", userIDStr)
+		sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+		if err != nil {
+			apierror.Respond(c, apierror.New(apierror.TokenInvalid))
+			return
+		}
+		if tenantID, ok := c.Get("tenant_id"); ok && tenantID != claims.TenantID {
+			apierror.Respond(c, apierror.New(apierror.TenantMismatch))
+			return
+		}
+		active, err := checkSession(claims.TenantID, sessionID)
+		if err != nil {
+			log.Printf("%s: error al verificar la sesión: %v", c.GetString("request_id"), err)
+			apierror.Respond(c, apierror.New(apierror.InternalError))
+			return
 		}
+		if !active {
+			apierror.Respond(c, apierror.New(apierror.SessionExpired))
+			return
+		}
+
+		// Almacenar los datos del token en el contexto de la petición
+		fmt.Println("✅ Token válido para usuario:", claims.UserID)
+		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("tenant_id", claims.TenantID)
 
 		c.Next()
 	}
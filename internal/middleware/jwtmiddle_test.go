@@ -3,32 +3,57 @@ package middleware
 import (
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
+	"explorax-backend/internal/utils"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TestSecret is used for testing purposes
 const TestSecret = "test-secret-key"
 
-func init() {
-	os.Setenv("JWT_SECRET", TestSecret)
-}
+// testSigner validates tokens signed with TestSecret
+var testSigner = utils.NewHMACSigner(TestSecret, nil)
 
 // GenerateTestToken creates a JWT token for testing
 func GenerateTestToken(userID string) string {
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["user_id"] = userID
+	claims["sid"] = primitive.NewObjectID().Hex()
+	claims["exp"] = time.Now().Add(24 * time.Hour).Unix()
+
+	tokenString, _ := token.SignedString([]byte(TestSecret))
+	return tokenString
+}
+
+// GenerateTestTokenWithRole creates a JWT token carrying a role claim for testing
+func GenerateTestTokenWithRole(userID, role string) string {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["user_id"] = userID
+	claims["role"] = role
+	claims["sid"] = primitive.NewObjectID().Hex()
 	claims["exp"] = time.Now().Add(24 * time.Hour).Unix()
 
 	tokenString, _ := token.SignedString([]byte(TestSecret))
 	return tokenString
 }
 
+// activeSession is a SessionChecker that accepts every session
+func activeSession(string, primitive.ObjectID) (bool, error) {
+	return true, nil
+}
+
+// revokedSession is a SessionChecker that rejects every session
+func revokedSession(string, primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
 // MockJWTMiddleware returns a simplified middleware for testing
 func MockJWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -56,7 +81,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		c.Request = httptest.NewRequest("GET", "/", nil)
 		c.Request.Header.Set("Authorization", "Bearer "+token)
 
-		JWTAuthMiddleware()(c)
+		JWTAuthMiddleware(testSigner, activeSession)(c)
 
 		if c.Errors != nil {
 			t.Errorf("Expected no errors, got %v", c.Errors)
@@ -71,6 +96,41 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("Role claim", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		token := GenerateTestTokenWithRole("test-user", "teacher")
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+
+		JWTAuthMiddleware(testSigner, activeSession)(c)
+
+		role, exists := c.Get("role")
+		if !exists {
+			t.Error("Expected role to be set")
+		}
+		if role != "teacher" {
+			t.Errorf("Expected role to be 'teacher', got %v", role)
+		}
+	})
+
+	t.Run("Missing role claim defaults to student", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		token := GenerateTestToken("test-user")
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+
+		JWTAuthMiddleware(testSigner, activeSession)(c)
+
+		role, _ := c.Get("role")
+		if role != "student" {
+			t.Errorf("Expected role to be 'student', got %v", role)
+		}
+	})
+
 	t.Run("Invalid token", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -78,7 +138,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		c.Request = httptest.NewRequest("GET", "/", nil)
 		c.Request.Header.Set("Authorization", "Bearer invalid-token")
 
-		JWTAuthMiddleware()(c)
+		JWTAuthMiddleware(testSigner, activeSession)(c)
 
 		if w.Code != http.StatusUnauthorized {
 			t.Errorf("Expected status 401, got %v", w.Code)
@@ -91,7 +151,45 @@ func TestJWTAuthMiddleware(t *testing.T) {
 
 		c.Request = httptest.NewRequest("GET", "/", nil)
 
-		JWTAuthMiddleware()(c)
+		JWTAuthMiddleware(testSigner, activeSession)(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %v", w.Code)
+		}
+	})
+
+	t.Run("Revoked session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		token := GenerateTestToken("test-user")
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+
+		JWTAuthMiddleware(testSigner, revokedSession)(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %v", w.Code)
+		}
+		if _, exists := c.Get("user_id"); exists {
+			t.Error("Expected user_id not to be set")
+		}
+	})
+
+	t.Run("Token without session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["user_id"] = "test-user"
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		tokenString, _ := token.SignedString([]byte(TestSecret))
+
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+
+		JWTAuthMiddleware(testSigner, activeSession)(c)
 
 		if w.Code != http.StatusUnauthorized {
 			t.Errorf("Expected status 401, got %v", w.Code)
@@ -112,7 +210,7 @@ func TestJWTAuthMiddleware(t *testing.T) {
 		c.Request = httptest.NewRequest("GET", "/", nil)
 		c.Request.Header.Set("Authorization", "Bearer "+tokenString)
 
-		JWTAuthMiddleware()(c)
+		JWTAuthMiddleware(testSigner, activeSession)(c)
 
 		if w.Code != http.StatusUnauthorized {
 			t.Errorf("Expected status 401, got %v", w.Code)
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader es la cabecera con el ID de la petición.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limita los IDs que se aceptan del cliente, ya que terminan en
+// los logs y en las respuestas.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID identifica cada petición con el ID que envía el cliente en
+// X-Request-ID o, si no envía uno válido, con uno aleatorio. Lo guarda en el
+// contexto como "request_id" y lo devuelve en la misma cabecera, para
+// relacionar las respuestas de error con los logs. Debe registrarse primero.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "sin-id"
+	}
+	return hex.EncodeToString(b)
+}
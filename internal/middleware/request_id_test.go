@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupRequestIDRouter builds a router that echoes the request ID
+func setupRequestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("request_id"))
+	})
+	return r
+}
+
+func TestRequestID(t *testing.T) {
+	t.Run("Generated when missing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupRequestIDRouter().ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		id := w.Header().Get(RequestIDHeader)
+		if len(id) != 16 || w.Body.String() != id {
+			t.Errorf("Expected a generated ID in header and context, got %q and %q", id, w.Body.String())
+		}
+	})
+
+	t.Run("Client ID is kept", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, "app-7f3a.1")
+		setupRequestIDRouter().ServeHTTP(w, req)
+
+		if w.Header().Get(RequestIDHeader) != "app-7f3a.1" || w.Body.String() != "app-7f3a.1" {
+			t.Errorf("Expected client ID, got %q", w.Header().Get(RequestIDHeader))
+		}
+	})
+
+	t.Run("Unsafe client ID is replaced", func(t *testing.T) {
+		for _, id := range []string{"bad id\n", strings.Repeat("a", 65)} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(RequestIDHeader, id)
+			setupRequestIDRouter().ServeHTTP(w, req)
+
+			if got := w.Header().Get(RequestIDHeader); got == id || len(got) != 16 {
+				t.Errorf("Expected %q to be replaced, got %q", id, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole permite el acceso solo a usuarios cuyo rol esté entre los indicados.
+// Debe registrarse después de JWTAuthMiddleware, que es quien coloca el rol en el contexto.
+func RequireRole(roles ...models.Role) gin.HandlerFunc {
+	allowed := make(map[models.Role]struct{}, len(roles))
+	for _, r := range roles {
+		allowed[r] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		roleVal, exists := c.Get("role")
+		if !exists {
+			apierror.Respond(c, apierror.New(apierror.Unauthenticated))
+			return
+		}
+
+		role, ok := roleVal.(string)
+		if !ok {
+			apierror.Respond(c, apierror.New(apierror.Forbidden))
+			return
+		}
+
+		if _, ok := allowed[models.Role(role)]; !ok {
+			apierror.Respond(c, apierror.New(apierror.Forbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
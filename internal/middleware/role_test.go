@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupRoleRouter builds a router protected by JWT and the given roles
+func setupRoleRouter(roles ...models.Role) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", JWTAuthMiddleware(testSigner, activeSession), RequireRole(roles...), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequireRole(t *testing.T) {
+	t.Run("Allowed role", func(t *testing.T) {
+		r := setupRoleRouter(models.RoleAdmin)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithRole("test-user", "admin"))
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %v", w.Code)
+		}
+	})
+
+	t.Run("One of several roles", func(t *testing.T) {
+		r := setupRoleRouter(models.RoleTeacher, models.RoleAdmin)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithRole("test-user", "teacher"))
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %v", w.Code)
+		}
+	})
+
+	t.Run("Forbidden role", func(t *testing.T) {
+		r := setupRoleRouter(models.RoleAdmin)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithRole("test-user", "student"))
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %v", w.Code)
+		}
+	})
+
+	t.Run("Token without role is a student", func(t *testing.T) {
+		r := setupRoleRouter(models.RoleAdmin)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestToken("test-user"))
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %v", w.Code)
+		}
+	})
+
+	t.Run("Missing authentication", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/admin", nil)
+
+		RequireRole(models.RoleAdmin)(c)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %v", w.Code)
+		}
+	})
+}
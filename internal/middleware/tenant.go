@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"log"
+
+	"explorax-backend/internal/apierror"
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader es la cabecera con que los clientes eligen su organización en
+// los endpoints que no llevan token, como el registro, el login y el ranking.
+const TenantHeader = "X-Tenant-ID"
+
+// TenantChecker informa si una organización está registrada.
+type TenantChecker func(tenantID string) (bool, error)
+
+// TenantMiddleware resuelve la organización indicada en la cabecera
+// X-Tenant-ID y la guarda en el contexto. Sin cabecera no hace nada: la
+// organización la define el token o, en los endpoints públicos,
+// models.DefaultTenantID. Debe registrarse antes de JWTAuthMiddleware.
+func TenantMiddleware(exists TenantChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetHeader(TenantHeader)
+		if tenantID == "" {
+			c.Next()
+			return
+		}
+
+		if !models.ValidTenantID(tenantID) {
+			apierror.Respond(c, apierror.New(apierror.TenantNotFound))
+			return
+		}
+		ok, err := exists(tenantID)
+		if err != nil {
+			log.Printf("%s: error al verificar la organización: %v", c.GetString("request_id"), err)
+			apierror.Respond(c, apierror.New(apierror.InternalError))
+			return
+		}
+		if !ok {
+			apierror.Respond(c, apierror.New(apierror.TenantNotFound))
+			return
+		}
+
+		c.Set("tenant_id", tenantID)
+		c.Next()
+	}
+}
+
+// CurrentTenant devuelve la organización de la petición que resolvieron
+// TenantMiddleware o JWTAuthMiddleware, o models.DefaultTenantID si ninguno lo hizo.
+func CurrentTenant(c *gin.Context) string {
+	if tenantID := c.GetString("tenant_id"); tenantID != "" {
+		return tenantID
+	}
+	return models.DefaultTenantID
+}
+
+// RequireTenant permite el acceso solo a usuarios de alguna de las
+// organizaciones indicadas. Debe registrarse después de JWTAuthMiddleware.
+func RequireTenant(tenantIDs ...string) gin.HandlerFunc {
+	allowed := make(map[string]struct{}, len(tenantIDs))
+	for _, id := range tenantIDs {
+		allowed[id] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := allowed[CurrentTenant(c)]; !ok {
+			apierror.Respond(c, apierror.New(apierror.Forbidden))
+			return
+		}
+		c.Next()
+	}
+}
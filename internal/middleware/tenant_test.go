@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"explorax-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GenerateTestTokenWithTenant creates a JWT token carrying a tenant claim for testing
+func GenerateTestTokenWithTenant(userID, tenantID string) string {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["user_id"] = userID
+	claims["tenant"] = tenantID
+	claims["sid"] = primitive.NewObjectID().Hex()
+	claims["exp"] = time.Now().Add(24 * time.Hour).Unix()
+
+	tokenString, _ := token.SignedString([]byte(TestSecret))
+	return tokenString
+}
+
+// knownTenants is a TenantChecker that accepts only "colegio-norte"
+func knownTenants(tenantID string) (bool, error) {
+	return tenantID == "colegio-norte", nil
+}
+
+// setupTenantRouter builds a router that echoes the resolved tenant
+func setupTenantRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(TenantMiddleware(knownTenants))
+	handlers = append(handlers, func(c *gin.Context) {
+		c.String(http.StatusOK, CurrentTenant(c))
+	})
+	r.GET("/", handlers...)
+	return r
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	t.Run("Default tenant without header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		setupTenantRouter().ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != models.DefaultTenantID {
+			t.Errorf("Expected default tenant, got %v %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Tenant from header", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TenantHeader, "colegio-norte")
+		setupTenantRouter().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "colegio-norte" {
+			t.Errorf("Expected colegio-norte, got %v %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Unknown tenant", func(t *testing.T) {
+		for _, tenantID := range []string{"colegio-sur", "Colegio Norte"} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(TenantHeader, tenantID)
+			setupTenantRouter().ServeHTTP(w, req)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("Expected status 404 for %q, got %v", tenantID, w.Code)
+			}
+		}
+	})
+
+	t.Run("Tenant from token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithTenant("test-user", "colegio-norte"))
+		setupTenantRouter(JWTAuthMiddleware(testSigner, activeSession)).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "colegio-norte" {
+			t.Errorf("Expected colegio-norte, got %v %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Token from another tenant", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(TenantHeader, "colegio-norte")
+		req.Header.Set("Authorization", "Bearer "+GenerateTestToken("test-user"))
+		setupTenantRouter(JWTAuthMiddleware(testSigner, activeSession)).ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %v", w.Code)
+		}
+	})
+
+	t.Run("Session checked in the token tenant", func(t *testing.T) {
+		var checked string
+		checker := func(tenantID string, _ primitive.ObjectID) (bool, error) {
+			checked = tenantID
+			return true, nil
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithTenant("test-user", "colegio-norte"))
+		setupTenantRouter(JWTAuthMiddleware(testSigner, checker)).ServeHTTP(w, req)
+
+		if checked != "colegio-norte" {
+			t.Errorf("Expected session checked in colegio-norte, got %q", checked)
+		}
+	})
+}
+
+func TestRequireTenant(t *testing.T) {
+	auth := JWTAuthMiddleware(testSigner, activeSession)
+
+	t.Run("Allowed tenant", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestToken("test-user"))
+		setupTenantRouter(auth, RequireTenant(models.DefaultTenantID)).ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %v", w.Code)
+		}
+	})
+
+	t.Run("Forbidden tenant", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+GenerateTestTokenWithTenant("test-user", "colegio-norte"))
+		setupTenantRouter(auth, RequireTenant(models.DefaultTenantID)).ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %v", w.Code)
+		}
+	})
+}
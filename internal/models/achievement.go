@@ -0,0 +1,132 @@
+// /internal/models/achievement.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RuleType es la condición que evalúa una regla de logro.
+type RuleType string
+
+const (
+	// RuleMissionsCompleted se cumple al completar Threshold misiones.
+	RuleMissionsCompleted RuleType = "missions_completed"
+	// RuleMissionsStarted se cumple al iniciar Threshold misiones.
+	RuleMissionsStarted RuleType = "missions_started"
+	// RuleFastCompletion se cumple al completar una misión en Threshold minutos
+	// de tiempo activo o menos.
+	RuleFastCompletion RuleType = "fast_completion"
+	// RuleStreakDays se cumple al completar misiones Threshold días seguidos.
+	RuleStreakDays RuleType = "streak_days"
+	// RuleXPTotal se cumple al acumular Threshold XP.
+	RuleXPTotal RuleType = "xp_total"
+)
+
+// RuleTypes enumera los tipos de regla soportados.
+var RuleTypes = []RuleType{RuleMissionsCompleted, RuleMissionsStarted, RuleFastCompletion, RuleStreakDays, RuleXPTotal}
+
+// AchievementRule es la condición declarativa de un logro.
+type AchievementRule struct {
+	Type      RuleType `bson:"type" json:"type" example:"missions_completed"`
+	Threshold int      `bson:"threshold" json:"threshold" example:"5"`
+}
+
+// Achievement es la definición de un logro. Los administradores las crean
+// por la API, sin desplegar código; las inactivas no se otorgan.
+type Achievement struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID    string             `json:"-" bson:"tenantId"`
+	Code        string             `bson:"code" json:"code"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	Rule        AchievementRule    `bson:"rule" json:"rule"`
+	Active      bool               `bson:"active" json:"active"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// UserAchievement es un logro otorgado a un usuario. Guarda el nombre y la
+// descripción vigentes al otorgarlo.
+type UserAchievement struct {
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID      string             `json:"-" bson:"tenantId"`
+	UserID        primitive.ObjectID `bson:"userId" json:"userId"`
+	AchievementID primitive.ObjectID `bson:"achievementId" json:"achievementId"`
+	Code          string             `bson:"code" json:"code"`
+	Name          string             `bson:"name" json:"name"`
+	Description   string             `bson:"description" json:"description"`
+	AwardedAt     time.Time          `bson:"awardedAt" json:"awardedAt"`
+}
+
+// AchievementFacts son los datos de un usuario contra los que se evalúan las
+// reglas. Se calculan a partir de todo su historial, de modo que una regla
+// nueva también reconoce lo logrado antes de crearla.
+type AchievementFacts struct {
+	MissionsStarted   int
+	MissionsCompleted int
+	// FastestCompletion solo es válido si MissionsCompleted > 0.
+	FastestCompletion time.Duration
+	StreakDays        int
+	XP                int
+}
+
+// Valid indica si la regla tiene un tipo conocido y un umbral positivo.
+func (r AchievementRule) Valid() bool {
+	if r.Threshold <= 0 {
+		return false
+	}
+	for _, t := range RuleTypes {
+		if r.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfied indica si los datos del usuario cumplen la regla.
+func (r AchievementRule) Satisfied(f AchievementFacts) bool {
+	switch r.Type {
+	case RuleMissionsCompleted:
+		return f.MissionsCompleted >= r.Threshold
+	case RuleMissionsStarted:
+		return f.MissionsStarted >= r.Threshold
+	case RuleFastCompletion:
+		return f.MissionsCompleted > 0 && f.FastestCompletion <= time.Duration(r.Threshold)*time.Minute
+	case RuleStreakDays:
+		return f.StreakDays >= r.Threshold
+	case RuleXPTotal:
+		return f.XP >= r.Threshold
+	}
+	return false
+}
+
+// StreakDays cuenta los días consecutivos con actividad en completionsByDay,
+// cuyas claves son días DayKey en loc, terminando hoy o ayer. Si el último
+// día con actividad es anterior a ayer, la racha está rota y vale 0.
+func StreakDays(completionsByDay map[string]int, now time.Time, loc *time.Location) int {
+	if loc == nil {
+		loc = time.UTC
+	}
+	active := func(day time.Time) bool {
+		return completionsByDay[day.Format(DayLayout)] > 0
+	}
+
+	day := dayOf(now, loc)
+	if !active(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for active(day) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// dayOf devuelve la medianoche del día de t en la zona horaria loc.
+func dayOf(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,70 @@
+// /internal/models/assignment.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssignmentStatus es el avance de un estudiante en una misión asignada.
+type AssignmentStatus string
+
+const (
+	AssignmentNotStarted AssignmentStatus = "not_started"
+	AssignmentInProgress AssignmentStatus = "in_progress"
+	AssignmentCompleted  AssignmentStatus = "completed"
+)
+
+// Assignment es una misión que un docente asigna a una clase completa o a un
+// solo estudiante. Tiene exactamente uno de ClassroomID o StudentID.
+type Assignment struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID    string             `json:"-" bson:"tenantId"`
+	MissionID   primitive.ObjectID `json:"missionId" bson:"missionId"`
+	TeacherID   primitive.ObjectID `json:"teacherId" bson:"teacherId"`
+	ClassroomID primitive.ObjectID `json:"classroomId,omitempty" bson:"classroomId,omitempty"`
+	StudentID   primitive.ObjectID `json:"studentId,omitempty" bson:"studentId,omitempty"`
+	// OpensAt es desde cuándo los estudiantes ven la misión asignada
+	OpensAt   time.Time `json:"opensAt" bson:"opensAt"`
+	DueAt     time.Time `json:"dueAt" bson:"dueAt"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// IsOpen indica si la asignación ya está abierta en now.
+func (a Assignment) IsOpen(now time.Time) bool {
+	return !now.Before(a.OpensAt)
+}
+
+// AssignmentState es el avance de un estudiante en una asignación.
+type AssignmentState struct {
+	Status      AssignmentStatus `json:"status" example:"completed"`
+	CompletedAt *time.Time       `json:"completedAt,omitempty"`
+	// OnTime indica que la misión se completó hasta la fecha de entrega
+	OnTime bool `json:"onTime" example:"true"`
+	// Late indica que se completó después de la fecha de entrega o que, sin
+	// completarse, la fecha ya pasó
+	Late bool `json:"late" example:"false"`
+}
+
+// State calcula el avance en la asignación a partir del progreso del
+// estudiante en la misión, que puede ser nil si nunca la inició. Una misión
+// abandonada cuenta como no iniciada, porque se vuelve a empezar desde cero.
+func (a Assignment) State(progress *MissionProgress, now time.Time) AssignmentState {
+	state := AssignmentState{Status: AssignmentNotStarted}
+	if progress != nil {
+		switch progress.Status {
+		case ProgressStarted, ProgressPaused:
+			state.Status = AssignmentInProgress
+		case ProgressCompleted:
+			endDate := progress.EndDate
+			state.Status = AssignmentCompleted
+			state.CompletedAt = &endDate
+			state.OnTime = !endDate.After(a.DueAt)
+			state.Late = !state.OnTime
+			return state
+		}
+	}
+	state.Late = now.After(a.DueAt)
+	return state
+}
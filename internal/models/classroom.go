@@ -0,0 +1,66 @@
+// /internal/models/classroom.go
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// JoinCodeLength es la cantidad de caracteres de un código de clase.
+const JoinCodeLength = 8
+
+// joinCodeAlphabet omite los caracteres que se confunden al dictarlos o
+// copiarlos a mano: 0/O, 1/I/L.
+const joinCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// Classroom es una clase a cargo de un docente. Los estudiantes se inscriben
+// con su código; solo el docente ve el código y el avance de sus estudiantes.
+type Classroom struct {
+	ID         primitive.ObjectID   `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID   string               `json:"-" bson:"tenantId"`
+	Name       string               `json:"name" bson:"name"`
+	TeacherID  primitive.ObjectID   `json:"teacherId" bson:"teacherId"`
+	JoinCode   string               `json:"joinCode" bson:"joinCode"`
+	StudentIDs []primitive.ObjectID `json:"studentIds" bson:"studentIds"`
+	CreatedAt  time.Time            `json:"createdAt" bson:"createdAt"`
+}
+
+// HasStudent indica si el usuario está inscrito en la clase.
+func (c Classroom) HasStudent(userID primitive.ObjectID) bool {
+	for _, id := range c.StudentIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// NewJoinCode genera un código de clase aleatorio. No es único por sí mismo:
+// el almacenamiento lo garantiza con un índice.
+func NewJoinCode() (string, error) {
+	var code strings.Builder
+	max := big.NewInt(int64(len(joinCodeAlphabet)))
+	for i := 0; i < JoinCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(joinCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+// NormalizeJoinCode lleva a mayúsculas el código ingresado por un estudiante
+// y quita los espacios y guiones con que suele agruparse.
+func NormalizeJoinCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
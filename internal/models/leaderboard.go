@@ -0,0 +1,69 @@
+// /internal/models/leaderboard.go
+package models
+
+import (
+	"bytes"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Periodos de los rankings. Las semanas empiezan el lunes y, como los meses,
+// se cuentan en UTC.
+const (
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+	PeriodAllTime = "all_time"
+)
+
+// LeaderboardPeriods enumera los periodos de los rankings.
+var LeaderboardPeriods = []string{PeriodWeekly, PeriodMonthly, PeriodAllTime}
+
+// LeaderboardBucket devuelve la clave del ranking del periodo que contiene a
+// at: "all_time", "weekly:2025-03-10" (el lunes de la semana) o
+// "monthly:2025-03". Devuelve false si el periodo no existe.
+func LeaderboardBucket(period string, at time.Time) (string, bool) {
+	y, m, d := at.UTC().Date()
+	switch period {
+	case PeriodWeekly:
+		today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return PeriodWeekly + ":" + monday.Format(DayLayout), true
+	case PeriodMonthly:
+		return PeriodMonthly + ":" + time.Date(y, m, 1, 0, 0, 0, 0, time.UTC).Format("2006-01"), true
+	case PeriodAllTime:
+		return PeriodAllTime, true
+	}
+	return "", false
+}
+
+// LeaderboardEntry es la posición de un usuario en un ranking calculado a
+// partir de las misiones que completó dentro de un periodo.
+type LeaderboardEntry struct {
+	UserID primitive.ObjectID `bson:"_id" json:"userId"`
+	// XP y CompletedCount cuentan solo las misiones completadas en el periodo
+	XP             int `bson:"xp" json:"xp"`
+	CompletedCount int `bson:"completedCount" json:"completedCount"`
+	// ReachedAt es la fecha de la última misión completada en el periodo, es
+	// decir, el momento en que el usuario alcanzó su puntaje
+	ReachedAt time.Time `bson:"reachedAt" json:"reachedAt"`
+	// User trae solo los campos del perfil público y la XP total del usuario,
+	// con la que se calcula su nivel
+	User User `bson:"user" json:"user"`
+}
+
+// Precedes indica si e va antes que o en el ranking: primero la mayor XP,
+// luego más misiones completadas y, a igual puntaje, quien lo alcanzó
+// primero. El ID del usuario desempata el resto para que el orden sea estable.
+func (e LeaderboardEntry) Precedes(o LeaderboardEntry) bool {
+	if e.XP != o.XP {
+		return e.XP > o.XP
+	}
+	if e.CompletedCount != o.CompletedCount {
+		return e.CompletedCount > o.CompletedCount
+	}
+	if !e.ReachedAt.Equal(o.ReachedAt) {
+		return e.ReachedAt.Before(o.ReachedAt)
+	}
+	return bytes.Compare(e.UserID[:], o.UserID[:]) < 0
+}
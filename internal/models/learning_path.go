@@ -0,0 +1,125 @@
+// /internal/models/learning_path.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LearningPath es una ruta de aprendizaje: misiones agrupadas en capítulos
+// que se recorren en orden. El orden es una guía; lo que bloquea una misión
+// son sus prerrequisitos.
+type LearningPath struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID    string             `json:"-" bson:"tenantId"`
+	Title       string             `json:"title" bson:"title" example:"Sistema solar"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Chapters    []PathChapter      `json:"chapters" bson:"chapters"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// PathChapter es un capítulo de una ruta, con sus misiones en orden.
+type PathChapter struct {
+	Title      string               `json:"title" bson:"title" example:"Los planetas interiores"`
+	MissionIDs []primitive.ObjectID `json:"missionIds" bson:"missionIds"`
+}
+
+// MissionIDs devuelve las misiones de la ruta en el orden en que se recorren.
+func (p LearningPath) MissionIDs() []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, chapter := range p.Chapters {
+		ids = append(ids, chapter.MissionIDs...)
+	}
+	return ids
+}
+
+// PathNodeState es el estado de una misión de una ruta para un usuario.
+type PathNodeState string
+
+const (
+	// NodeLocked indica que falta completar algún prerrequisito
+	NodeLocked    PathNodeState = "locked"
+	NodeUnlocked  PathNodeState = "unlocked"
+	NodeCompleted PathNodeState = "completed"
+)
+
+// MissingPrerequisites devuelve los prerrequisitos de la misión que el
+// usuario no ha completado. Solo cuentan los que siguen disponibles
+// (available): una misión archivada o eliminada no bloquea a las que dependen
+// de ella, porque ya no se puede iniciar.
+func (m Mission) MissingPrerequisites(completed, available map[primitive.ObjectID]bool) []primitive.ObjectID {
+	var missing []primitive.ObjectID
+	for _, id := range m.Prerequisites {
+		if available[id] && !completed[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// NodeState calcula el estado de la misión en una ruta a partir de las
+// misiones completadas y disponibles del usuario.
+func (m Mission) NodeState(completed, available map[primitive.ObjectID]bool) PathNodeState {
+	switch {
+	case completed[m.ID]:
+		return NodeCompleted
+	case len(m.MissingPrerequisites(completed, available)) > 0:
+		return NodeLocked
+	}
+	return NodeUnlocked
+}
+
+// PrerequisiteCycle busca un ciclo en el grafo de prerrequisitos de missions.
+// Si lo encuentra devuelve sus misiones en orden, empezando y terminando por
+// la misma; si no, nil. Los prerrequisitos que no están en missions se ignoran.
+func PrerequisiteCycle(missions []Mission) []primitive.ObjectID {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	graph := make(map[primitive.ObjectID][]primitive.ObjectID, len(missions))
+	for _, m := range missions {
+		graph[m.ID] = m.Prerequisites
+	}
+	state := make(map[primitive.ObjectID]int, len(missions))
+	var stack []primitive.ObjectID
+
+	var visit func(id primitive.ObjectID) []primitive.ObjectID
+	visit = func(id primitive.ObjectID) []primitive.ObjectID {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, next := range graph[id] {
+			if _, known := graph[next]; !known {
+				continue
+			}
+			switch state[next] {
+			case visiting:
+				// El ciclo es el tramo de la pila desde next
+				for i, v := range stack {
+					if v == next {
+						return append(append([]primitive.ObjectID{}, stack[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return nil
+	}
+
+	for _, m := range missions {
+		if state[m.ID] == unvisited {
+			if cycle := visit(m.ID); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// /internal/models/level.go
+package models
+
+import "math"
+
+// LevelCurve define cuánta XP hace falta para subir de nivel. Todos empiezan
+// en el nivel 1 y pasar del nivel n al n+1 cuesta BaseXP * Growth^(n-1) XP,
+// redondeado.
+type LevelCurve struct {
+	BaseXP int
+	Growth float64
+}
+
+// DefaultLevelCurve es la curva que se usa si no se configura otra.
+var DefaultLevelCurve = LevelCurve{BaseXP: 100, Growth: 1.5}
+
+// maxLevel acota el cálculo para curvas con crecimiento muy bajo.
+const maxLevel = 1000
+
+// LevelProgress resume el nivel alcanzado con una cantidad de XP.
+type LevelProgress struct {
+	Level         int `json:"level" example:"3"`
+	XP            int `json:"xp" example:"270"`
+	XPToNextLevel int `json:"xpToNextLevel" example:"70"`
+}
+
+// Valid indica si la curva puede usarse: BaseXP positivo y Growth de al menos 1.
+func (c LevelCurve) Valid() bool {
+	return c.BaseXP > 0 && c.Growth >= 1
+}
+
+// XPForLevel devuelve la XP necesaria para pasar del nivel dado al siguiente.
+func (c LevelCurve) XPForLevel(level int) int {
+	return int(math.Round(float64(c.BaseXP) * math.Pow(c.Growth, float64(level-1))))
+}
+
+// Progress calcula el nivel correspondiente a la XP acumulada y cuánta XP
+// falta para el siguiente nivel.
+func (c LevelCurve) Progress(xp int) LevelProgress {
+	if !c.Valid() {
+		c = DefaultLevelCurve
+	}
+	level, remaining := 1, xp
+	for level < maxLevel {
+		needed := c.XPForLevel(level)
+		if remaining < needed {
+			return LevelProgress{Level: level, XP: xp, XPToNextLevel: needed - remaining}
+		}
+		remaining -= needed
+		level++
+	}
+	return LevelProgress{Level: level, XP: xp}
+}
@@ -0,0 +1,120 @@
+// /internal/models/locale.go
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultLocale es el idioma de las misiones que no indican otro y el que se
+// usa cuando no se puede negociar uno con el usuario.
+const DefaultLocale = "es"
+
+// SupportedLocales son los idiomas en que se pueden traducir las misiones.
+// El primero es DefaultLocale.
+var SupportedLocales = []string{DefaultLocale, "en", "pt"}
+
+// IsSupportedLocale indica si locale es uno de SupportedLocales.
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// MissionTranslation es la traducción de una misión a un idioma. Los campos
+// vacíos, y los pasos que no aparecen, se muestran en el idioma original.
+type MissionTranslation struct {
+	Title       string            `json:"title,omitempty" bson:"title,omitempty" example:"Explore Mars"`
+	Description string            `json:"description,omitempty" bson:"description,omitempty"`
+	Steps       []StepTranslation `json:"steps,omitempty" bson:"steps,omitempty"`
+}
+
+// StepTranslation es la traducción de un paso de una misión.
+type StepTranslation struct {
+	ID          primitive.ObjectID `json:"id" bson:"id"`
+	Title       string             `json:"title,omitempty" bson:"title,omitempty"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+}
+
+// SourceLocale devuelve el idioma en que está escrita la misión.
+func (m Mission) SourceLocale() string {
+	if m.Locale == "" {
+		return DefaultLocale
+	}
+	return m.Locale
+}
+
+// Localize devuelve una copia de la misión con el título, la descripción y
+// los pasos traducidos a locale, sin el resto de las traducciones. Lo que no
+// está traducido queda en el idioma original. Locale indica el idioma de la
+// copia: el pedido si la misión tiene su traducción, o el original si no.
+func (m Mission) Localize(locale string) Mission {
+	translation, ok := m.Translations[locale]
+	m.Translations = nil
+	if !ok || locale == m.SourceLocale() {
+		m.Locale = m.SourceLocale()
+		return m
+	}
+	m.Locale = locale
+	if translation.Title != "" {
+		m.Title = translation.Title
+	}
+	if translation.Description != "" {
+		m.Description = translation.Description
+	}
+	if len(translation.Steps) > 0 {
+		steps := make([]MissionStep, len(m.Steps))
+		for i, step := range m.Steps {
+			for _, t := range translation.Steps {
+				if t.ID != step.ID {
+					continue
+				}
+				if t.Title != "" {
+					step.Title = t.Title
+				}
+				if t.Description != "" {
+					step.Description = t.Description
+				}
+			}
+			steps[i] = step
+		}
+		m.Steps = steps
+	}
+	return m
+}
+
+// MissingTranslations devuelve los textos de la misión que no están
+// traducidos a locale: "title", "description", "steps.<id>.title" y
+// "steps.<id>.description". Solo cuentan los que tienen texto en el idioma
+// original; en el idioma original no falta nada.
+func (m Mission) MissingTranslations(locale string) []string {
+	if locale == m.SourceLocale() {
+		return nil
+	}
+	translation := m.Translations[locale]
+	var missing []string
+	if m.Title != "" && translation.Title == "" {
+		missing = append(missing, "title")
+	}
+	if m.Description != "" && translation.Description == "" {
+		missing = append(missing, "description")
+	}
+	for _, step := range m.Steps {
+		var t StepTranslation
+		for _, candidate := range translation.Steps {
+			if candidate.ID == step.ID {
+				t = candidate
+			}
+		}
+		prefix := "steps." + step.ID.Hex() + "."
+		if step.Title != "" && t.Title == "" {
+			missing = append(missing, prefix+"title")
+		}
+		if step.Description != "" && t.Description == "" {
+			missing = append(missing, prefix+"description")
+		}
+	}
+	return missing
+}
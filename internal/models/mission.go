@@ -2,15 +2,137 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Mission representa la estructura de una misión en la base de datos.
+// Las misiones archivadas se conservan para el historial de los usuarios,
+// pero no se listan ni cuentan para el porcentaje de avance.
+// Una misión sin pasos se completa de una vez; con pasos, se completa al
+// terminar todos los pasos obligatorios, y con cuestionario, al aprobarlo.
+// Cada misión pertenece a una organización; las globales, que solo crea la
+// organización DefaultTenantID, se ofrecen además en todas las demás.
+// Las misiones con prerrequisitos quedan bloqueadas hasta completarlos.
+// Los textos pueden traducirse a otros idiomas; ver Localize.
 type Mission struct {
 	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID    string             `json:"-" bson:"tenantId"`
 	Title       string             `bson:"title" json:"title"`
 	Description string             `bson:"description" json:"description"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	// Locale es el idioma del título, la descripción y los pasos; vacío
+	// equivale a DefaultLocale. Translations los traduce a otros idiomas.
+	Locale       string                        `bson:"locale,omitempty" json:"locale,omitempty" example:"es"`
+	Translations map[string]MissionTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	Global       bool                          `bson:"global,omitempty" json:"global"`
+	// Prerequisites son las misiones que hay que completar antes de iniciarla
+	Prerequisites []primitive.ObjectID `bson:"prerequisites,omitempty" json:"prerequisites,omitempty"`
+	// Category, Tags, Difficulty, GradeLevel y EstimatedMinutes clasifican la
+	// misión en el catálogo; en cero no se indicaron
+	Category         string        `bson:"category,omitempty" json:"category,omitempty" example:"astronomia"`
+	Tags             []string      `bson:"tags,omitempty" json:"tags,omitempty" example:"planetas,marte"`
+	Difficulty       int           `bson:"difficulty,omitempty" json:"difficulty,omitempty" example:"2"`
+	GradeLevel       int           `bson:"gradeLevel,omitempty" json:"gradeLevel,omitempty" example:"5"`
+	EstimatedMinutes int           `bson:"estimatedMinutes,omitempty" json:"estimatedMinutes,omitempty" example:"20"`
+	Steps            []MissionStep `bson:"steps,omitempty" json:"steps,omitempty"`
+	Quiz             *Quiz         `bson:"quiz,omitempty" json:"quiz,omitempty"`
+	// XPReward es la XP base de la misión; DifficultyMultiplier la escala.
+	XPReward             int        `bson:"xpReward,omitempty" json:"xpReward,omitempty"`
+	DifficultyMultiplier float64    `bson:"difficultyMultiplier,omitempty" json:"difficultyMultiplier,omitempty"`
+	Archived             bool       `bson:"archived" json:"archived"`
+	ArchivedAt           *time.Time `bson:"archivedAt,omitempty" json:"archivedAt,omitempty"`
+	CreatedAt            time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt            time.Time  `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// Límites de la clasificación de las misiones.
+const (
+	MaxDifficulty = 5
+	MaxGradeLevel = 12
+	MaxTags       = 10
+)
+
+// MissionFacets cuenta las misiones de un listado por categoría, etiqueta,
+// dificultad y grado, para armar los filtros del catálogo. Las misiones sin
+// el dato no se cuentan.
+type MissionFacets struct {
+	Categories   map[string]int `json:"categories"`
+	Tags         map[string]int `json:"tags"`
+	Difficulties map[int]int    `json:"difficulties"`
+	GradeLevels  map[int]int    `json:"gradeLevels"`
+}
+
+// NewMissionFacets devuelve facetas sin misiones.
+func NewMissionFacets() MissionFacets {
+	return MissionFacets{
+		Categories:   map[string]int{},
+		Tags:         map[string]int{},
+		Difficulties: map[int]int{},
+		GradeLevels:  map[int]int{},
+	}
+}
+
+// Add cuenta la misión en las facetas.
+func (f MissionFacets) Add(m Mission) {
+	if m.Category != "" {
+		f.Categories[m.Category]++
+	}
+	for _, tag := range m.Tags {
+		f.Tags[tag]++
+	}
+	if m.Difficulty > 0 {
+		f.Difficulties[m.Difficulty]++
+	}
+	if m.GradeLevel > 0 {
+		f.GradeLevels[m.GradeLevel]++
+	}
+}
+
+// DefaultXPReward es la XP que otorgan las misiones sin recompensa configurada.
+const DefaultXPReward = 100
+
+// XP devuelve la XP que se acredita al completar la misión: la recompensa
+// base, o DefaultXPReward si no tiene, multiplicada por la dificultad.
+func (m Mission) XP() int {
+	reward := m.XPReward
+	if reward <= 0 {
+		reward = DefaultXPReward
+	}
+	multiplier := m.DifficultyMultiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	return int(math.Round(float64(reward) * multiplier))
+}
+
+// MissionStep es un objetivo de una misión. Los pasos se muestran en el orden
+// en que están guardados; los opcionales no son necesarios para completarla.
+type MissionStep struct {
+	ID          primitive.ObjectID `bson:"id" json:"id"`
+	Title       string             `bson:"title" json:"title"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Optional    bool               `bson:"optional,omitempty" json:"optional,omitempty"`
+}
+
+// Step busca un paso de la misión por su ID.
+func (m Mission) Step(id primitive.ObjectID) (MissionStep, bool) {
+	for _, step := range m.Steps {
+		if step.ID == id {
+			return step, true
+		}
+	}
+	return MissionStep{}, false
+}
+
+// RequiredSteps devuelve los pasos obligatorios de la misión.
+func (m Mission) RequiredSteps() []MissionStep {
+	var required []MissionStep
+	for _, step := range m.Steps {
+		if !step.Optional {
+			required = append(required, step)
+		}
+	}
+	return required
 }
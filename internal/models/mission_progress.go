@@ -2,17 +2,236 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ProgressStatus es el estado del progreso de un usuario en una misión.
+type ProgressStatus string
+
+const (
+	ProgressStarted   ProgressStatus = "iniciada"
+	ProgressPaused    ProgressStatus = "pausada"
+	ProgressCompleted ProgressStatus = "completada"
+	ProgressAbandoned ProgressStatus = "abandonada"
+)
+
+// ProgressAction es una acción del usuario que cambia el estado de su progreso.
+type ProgressAction string
+
+const (
+	ActionStart    ProgressAction = "start"
+	ActionPause    ProgressAction = "pause"
+	ActionResume   ProgressAction = "resume"
+	ActionComplete ProgressAction = "complete"
+	ActionAbandon  ProgressAction = "abandon"
+)
+
+// progressTransitions define, para cada estado, a qué estado lleva cada acción
+// permitida. Las combinaciones ausentes no están permitidas. Iniciar una misión
+// abandonada la reinicia desde cero.
+var progressTransitions = map[ProgressStatus]map[ProgressAction]ProgressStatus{
+	ProgressStarted: {
+		ActionPause:    ProgressPaused,
+		ActionComplete: ProgressCompleted,
+		ActionAbandon:  ProgressAbandoned,
+	},
+	ProgressPaused: {
+		ActionResume:  ProgressStarted,
+		ActionAbandon: ProgressAbandoned,
+	},
+	ProgressAbandoned: {
+		ActionStart: ProgressStarted,
+	},
+	ProgressCompleted: {},
+}
+
+// ErrInvalidTransition se devuelve cuando la acción no está permitida en el
+// estado actual del progreso.
+var ErrInvalidTransition = errors.New("transición de estado no permitida")
+
 // MissionProgress almacena el estado de una misión para un usuario específico.
+// El tiempo activo acumula solo los tramos en estado "iniciada", de modo que
+// las pausas no cuentan para la duración de la misión.
 type MissionProgress struct {
-	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
-	MissionID primitive.ObjectID `bson:"missionId" json:"missionId"`
-	Status    string             `bson:"status" json:"status"` // "iniciada" o "completada"
-	StartDate time.Time          `bson:"startDate" json:"startDate"`
-	EndDate   time.Time          `bson:"endDate,omitempty" json:"endDate,omitempty"`
+	ID               primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID         string             `json:"-" bson:"tenantId"`
+	UserID           primitive.ObjectID `bson:"userId" json:"userId"`
+	MissionID        primitive.ObjectID `bson:"missionId" json:"missionId"`
+	Status           ProgressStatus     `bson:"status" json:"status"`
+	StartDate        time.Time          `bson:"startDate" json:"startDate"`
+	EndDate          time.Time          `bson:"endDate,omitempty" json:"endDate,omitempty"`
+	LastResumedAt    time.Time          `bson:"lastResumedAt,omitempty" json:"lastResumedAt,omitempty"`
+	PausedAt         *time.Time         `bson:"pausedAt,omitempty" json:"pausedAt,omitempty"`
+	AbandonedAt      *time.Time         `bson:"abandonedAt,omitempty" json:"abandonedAt,omitempty"`
+	ActiveDurationMs int64              `bson:"activeDurationMs,omitempty" json:"activeDurationMs,omitempty"`
+	Steps            []StepProgress     `bson:"steps,omitempty" json:"steps,omitempty"`
+	XPAwarded        int                `bson:"xpAwarded,omitempty" json:"xpAwarded,omitempty"`
+	// XPPending indica que XPAwarded todavía no se sumó a la experiencia del
+	// usuario; se desmarca al acreditarla (ver database.ProgressStore.CreditPendingXP)
+	XPPending bool `bson:"xpPending,omitempty" json:"-"`
+}
+
+// StepProgress registra un paso completado de una misión con pasos.
+type StepProgress struct {
+	StepID      primitive.ObjectID `bson:"stepId" json:"stepId"`
+	CompletedAt time.Time          `bson:"completedAt" json:"completedAt"`
+}
+
+// NewMissionProgress crea el progreso de una misión recién iniciada.
+func NewMissionProgress(userID, missionID primitive.ObjectID, now time.Time) MissionProgress {
+	return MissionProgress{
+		ID:            primitive.NewObjectID(),
+		UserID:        userID,
+		MissionID:     missionID,
+		Status:        ProgressStarted,
+		StartDate:     now,
+		LastResumedAt: now,
+	}
+}
+
+// CanApply indica si la acción está permitida en el estado actual.
+func (p MissionProgress) CanApply(action ProgressAction) bool {
+	_, ok := progressTransitions[p.Status][action]
+	return ok
+}
+
+// Apply ejecuta la acción sobre el progreso en el instante now, actualizando
+// el estado, las fechas y el tiempo activo acumulado.
+func (p *MissionProgress) Apply(action ProgressAction, now time.Time) error {
+	next, ok := progressTransitions[p.Status][action]
+	if !ok {
+		return ErrInvalidTransition
+	}
+
+	switch action {
+	case ActionStart:
+		// Reiniciar una misión abandonada descarta el intento anterior
+		p.StartDate = now
+		p.LastResumedAt = now
+		p.EndDate = time.Time{}
+		p.PausedAt = nil
+		p.AbandonedAt = nil
+		p.ActiveDurationMs = 0
+		p.Steps = nil
+		p.XPAwarded = 0
+	case ActionPause:
+		p.closeActiveSegment(now)
+		p.PausedAt = &now
+	case ActionResume:
+		p.LastResumedAt = now
+		p.PausedAt = nil
+	case ActionComplete:
+		p.closeActiveSegment(now)
+		p.EndDate = now
+	case ActionAbandon:
+		if p.Status == ProgressStarted {
+			p.closeActiveSegment(now)
+		}
+		p.PausedAt = nil
+		p.AbandonedAt = &now
+	}
+
+	p.Status = next
+	return nil
+}
+
+// Complete completa la misión y registra la XP que le corresponde al usuario,
+// pendiente de acreditar.
+func (p *MissionProgress) Complete(m Mission, now time.Time) error {
+	if err := p.Apply(ActionComplete, now); err != nil {
+		return err
+	}
+	p.XPAwarded = m.XP()
+	p.XPPending = p.XPAwarded > 0
+	return nil
+}
+
+// ActiveDuration devuelve el tiempo activo acumulado hasta now, incluido el
+// tramo en curso si la misión está iniciada.
+func (p MissionProgress) ActiveDuration(now time.Time) time.Duration {
+	active := time.Duration(p.ActiveDurationMs) * time.Millisecond
+	if p.Status == ProgressStarted {
+		active += now.Sub(p.segmentStart())
+	}
+	return active
+}
+
+// Elapsed devuelve el tiempo activo de la misión hasta now: su duración si
+// está completada y, si no, el tiempo acumulado más el tramo en curso.
+func (p MissionProgress) Elapsed(now time.Time) time.Duration {
+	if p.Status == ProgressCompleted {
+		return p.CompletedDuration()
+	}
+	return p.ActiveDuration(now)
+}
+
+// HasCompletedStep indica si el usuario ya completó el paso.
+func (p MissionProgress) HasCompletedStep(stepID primitive.ObjectID) bool {
+	for _, step := range p.Steps {
+		if step.StepID == stepID {
+			return true
+		}
+	}
+	return false
+}
+
+// StepsCompleted devuelve cuántos pasos obligatorios de la misión completó
+// el usuario y cuántos pasos obligatorios tiene la misión.
+func (p MissionProgress) StepsCompleted(m Mission) (done, required int) {
+	for _, step := range m.RequiredSteps() {
+		required++
+		if p.HasCompletedStep(step.ID) {
+			done++
+		}
+	}
+	return done, required
+}
+
+// RequiredStepsDone indica si el usuario completó todos los pasos
+// obligatorios. Una misión sin pasos obligatorios no exige ninguno.
+func (p MissionProgress) RequiredStepsDone(m Mission) bool {
+	done, required := p.StepsCompleted(m)
+	return done == required
+}
+
+// CompletionPercentage devuelve el avance del usuario en la misión, de 0 a
+// 100. Sin pasos obligatorios el avance es 0 hasta completarla.
+func (p MissionProgress) CompletionPercentage(m Mission) float64 {
+	if p.Status == ProgressCompleted {
+		return 100
+	}
+	done, required := p.StepsCompleted(m)
+	if required == 0 {
+		return 0
+	}
+	return float64(done) * 100 / float64(required)
+}
+
+// CompletedDuration devuelve la duración de una misión completada: el tiempo
+// activo acumulado o, en progresos anteriores a las pausas, el tiempo entre
+// inicio y fin.
+func (p MissionProgress) CompletedDuration() time.Duration {
+	if p.ActiveDurationMs != 0 {
+		return time.Duration(p.ActiveDurationMs) * time.Millisecond
+	}
+	return p.EndDate.Sub(p.StartDate)
+}
+
+// closeActiveSegment suma al tiempo activo el tramo iniciado en LastResumedAt.
+func (p *MissionProgress) closeActiveSegment(now time.Time) {
+	if elapsed := now.Sub(p.segmentStart()); elapsed > 0 {
+		p.ActiveDurationMs += elapsed.Milliseconds()
+	}
+}
+
+// segmentStart devuelve el inicio del tramo activo actual. Los progresos
+// creados antes de existir las pausas no tienen LastResumedAt.
+func (p MissionProgress) segmentStart() time.Time {
+	if p.LastResumedAt.IsZero() {
+		return p.StartDate
+	}
+	return p.LastResumedAt
 }
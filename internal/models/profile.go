@@ -0,0 +1,91 @@
+// /internal/models/profile.go
+package models
+
+import (
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdultAge es la edad desde la que un usuario puede mostrar en público el
+// nombre que elija.
+const AdultAge = 18
+
+// Avatars son los avatares que se pueden elegir. Son ilustraciones
+// predefinidas: los usuarios no suben imágenes.
+var Avatars = []string{
+	"astronauta", "cohete", "planeta", "cometa", "telescopio", "satelite",
+	"robot", "volcan", "quetzal", "jaguar", "delfin", "colibri",
+}
+
+var pseudonymNouns = []string{
+	"Cometa", "Meteoro", "Planeta", "Cohete", "Satélite", "Asteroide",
+	"Telescopio", "Quásar", "Púlsar", "Eclipse", "Volcán", "Jaguar",
+	"Quetzal", "Colibrí", "Delfín", "Cráter",
+}
+
+var pseudonymAdjectives = []string{
+	"Veloz", "Brillante", "Curioso", "Valiente", "Audaz", "Sabio",
+	"Alegre", "Intrépido", "Luminoso", "Astuto", "Tenaz", "Sereno",
+	"Ágil", "Estelar", "Cósmico", "Paciente",
+}
+
+// ValidAvatar indica si avatar es uno de los avatares disponibles.
+func ValidAvatar(avatar string) bool {
+	for _, a := range Avatars {
+		if a == avatar {
+			return true
+		}
+	}
+	return false
+}
+
+// Pseudonym genera un nombre público estable para el usuario id, como
+// "Cometa Veloz 42". No contiene datos del usuario ni permite deducir su ID.
+func Pseudonym(id primitive.ObjectID) string {
+	n := pseudonymSeed(id)
+	noun := pseudonymNouns[n%uint64(len(pseudonymNouns))]
+	n /= uint64(len(pseudonymNouns))
+	adjective := pseudonymAdjectives[n%uint64(len(pseudonymAdjectives))]
+	n /= uint64(len(pseudonymAdjectives))
+	return noun + " " + adjective + " " + strconv.FormatUint(n%100, 10)
+}
+
+func pseudonymSeed(id primitive.ObjectID) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("explorax-pseudonym:"))
+	h.Write(id[:])
+	return h.Sum64()
+}
+
+// IsAdult indica si el usuario tenía AdultAge años cumplidos en now. Sin
+// fecha de nacimiento se considera menor.
+func (u User) IsAdult(now time.Time) bool {
+	if u.BirthDate == nil {
+		return false
+	}
+	birth := u.BirthDate.UTC()
+	adulthood := time.Date(birth.Year()+AdultAge, birth.Month(), birth.Day(), 0, 0, 0, 0, time.UTC)
+	return !now.UTC().Before(adulthood)
+}
+
+// PublicName es el nombre con el que el usuario aparece en público. Solo los
+// adultos muestran el nombre que eligieron; los menores, y quien no lo eligió,
+// aparecen con un seudónimo.
+func (u User) PublicName(now time.Time) string {
+	if u.DisplayName != "" && u.IsAdult(now) {
+		return u.DisplayName
+	}
+	return Pseudonym(u.ID)
+}
+
+// PublicAvatar es el avatar elegido por el usuario o, si no eligió ninguno,
+// uno asignado de forma estable.
+func (u User) PublicAvatar() string {
+	if ValidAvatar(u.Avatar) {
+		return u.Avatar
+	}
+	return Avatars[pseudonymSeed(u.ID)%uint64(len(Avatars))]
+}
@@ -0,0 +1,171 @@
+// /internal/models/quiz.go
+package models
+
+import (
+	"math"
+	"time"
+
+	"explorax-backend/internal/textnorm"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultPassingScore es el puntaje mínimo, sobre 100, para aprobar un
+// cuestionario que no define el suyo.
+const DefaultPassingScore = 70
+
+// DefaultMaxAttempts es la cantidad de intentos de un cuestionario que no
+// define la suya.
+const DefaultMaxAttempts = 3
+
+// QuestionType es el tipo de respuesta que espera una pregunta.
+type QuestionType string
+
+const (
+	QuestionMultipleChoice QuestionType = "multiple_choice"
+	QuestionNumeric        QuestionType = "numeric"
+	QuestionShortText      QuestionType = "short_text"
+)
+
+// Quiz es el cuestionario de una misión. La misión se completa al enviar
+// respuestas con un puntaje igual o superior a PassingScore, en a lo sumo
+// MaxAttempts intentos.
+type Quiz struct {
+	PassingScore float64        `bson:"passingScore" json:"passingScore"`
+	MaxAttempts  int            `bson:"maxAttempts,omitempty" json:"maxAttempts"`
+	Questions    []QuizQuestion `bson:"questions" json:"questions"`
+}
+
+// QuizQuestion es una pregunta del cuestionario. Solo se usa el campo de
+// respuesta correspondiente a su tipo: CorrectOption para opción múltiple,
+// NumericAnswer y Tolerance para numéricas y AcceptedAnswers para texto corto.
+// Las respuestas nunca se envían a los estudiantes; ver Mission.Public.
+type QuizQuestion struct {
+	ID              primitive.ObjectID `bson:"id" json:"id"`
+	Type            QuestionType       `bson:"type" json:"type"`
+	Prompt          string             `bson:"prompt" json:"prompt"`
+	Options         []string           `bson:"options,omitempty" json:"options,omitempty"`
+	CorrectOption   *int               `bson:"correctOption,omitempty" json:"correctOption,omitempty"`
+	NumericAnswer   *float64           `bson:"numericAnswer,omitempty" json:"numericAnswer,omitempty"`
+	Tolerance       float64            `bson:"tolerance,omitempty" json:"tolerance,omitempty"`
+	AcceptedAnswers []string           `bson:"acceptedAnswers,omitempty" json:"acceptedAnswers,omitempty"`
+}
+
+// QuizAnswer es la respuesta de un usuario a una pregunta. Según el tipo de
+// pregunta se usa Option, Number o Text.
+type QuizAnswer struct {
+	QuestionID primitive.ObjectID `bson:"questionId" json:"questionId"`
+	Option     *int               `bson:"option,omitempty" json:"option,omitempty"`
+	Number     *float64           `bson:"number,omitempty" json:"number,omitempty"`
+	Text       string             `bson:"text,omitempty" json:"text,omitempty"`
+	Correct    bool               `bson:"correct" json:"correct"`
+}
+
+// QuizAttempt es un envío de respuestas calificado. Se guardan todos los
+// intentos, aprobados o no. Number es el orden del intento, desde 1, y no se
+// repite para un mismo usuario y misión.
+type QuizAttempt struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID    string             `json:"-" bson:"tenantId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	MissionID   primitive.ObjectID `bson:"missionId" json:"missionId"`
+	Number      int                `bson:"number,omitempty" json:"number"`
+	Answers     []QuizAnswer       `bson:"answers" json:"answers,omitempty"`
+	Correct     int                `bson:"correct" json:"correct"`
+	Total       int                `bson:"total" json:"total"`
+	Score       float64            `bson:"score" json:"score"`
+	Passed      bool               `bson:"passed" json:"passed"`
+	SubmittedAt time.Time          `bson:"submittedAt" json:"submittedAt"`
+}
+
+// Grade califica las respuestas enviadas. Cada pregunta vale lo mismo y las
+// preguntas sin responder cuentan como incorrectas; las respuestas a preguntas
+// que no existen se descartan.
+func (q Quiz) Grade(answers []QuizAnswer) (graded []QuizAnswer, correct int, score float64) {
+	byQuestion := make(map[primitive.ObjectID]QuizAnswer, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	graded = make([]QuizAnswer, 0, len(q.Questions))
+	for _, question := range q.Questions {
+		answer, ok := byQuestion[question.ID]
+		if !ok {
+			answer = QuizAnswer{QuestionID: question.ID}
+		}
+		answer.Correct = ok && question.IsCorrect(answer)
+		if answer.Correct {
+			correct++
+		}
+		graded = append(graded, answer)
+	}
+
+	if len(q.Questions) > 0 {
+		score = float64(correct) * 100 / float64(len(q.Questions))
+	}
+	return graded, correct, score
+}
+
+// Passed indica si el puntaje alcanza el mínimo para aprobar.
+func (q Quiz) Passed(score float64) bool {
+	return score >= q.PassingScore
+}
+
+// AttemptLimit es la cantidad de intentos permitidos; los cuestionarios
+// guardados antes de existir MaxAttempts usan DefaultMaxAttempts.
+func (q Quiz) AttemptLimit() int {
+	if q.MaxAttempts > 0 {
+		return q.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+// Summary devuelve el intento sin las respuestas calificadas: solo el puntaje
+// y la cantidad de aciertos, para no revelar qué preguntas eran correctas
+// mientras el estudiante todavía puede volver a intentarlo.
+func (a QuizAttempt) Summary() QuizAttempt {
+	a.Answers = nil
+	return a
+}
+
+// IsCorrect indica si la respuesta es correcta para la pregunta. Las
+// respuestas de texto se comparan sin distinguir mayúsculas ni acentos.
+func (question QuizQuestion) IsCorrect(answer QuizAnswer) bool {
+	switch question.Type {
+	case QuestionMultipleChoice:
+		return answer.Option != nil && question.CorrectOption != nil && *answer.Option == *question.CorrectOption
+	case QuestionNumeric:
+		return answer.Number != nil && question.NumericAnswer != nil &&
+			math.Abs(*answer.Number-*question.NumericAnswer) <= question.Tolerance
+	case QuestionShortText:
+		given := textnorm.Fold(answer.Text)
+		if given == "" {
+			return false
+		}
+		for _, accepted := range question.AcceptedAnswers {
+			if textnorm.Fold(accepted) == given {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Public devuelve una copia de la misión sin las respuestas del cuestionario,
+// apta para enviarse a los estudiantes.
+func (m Mission) Public() Mission {
+	if m.Quiz == nil {
+		return m
+	}
+	quiz := Quiz{PassingScore: m.Quiz.PassingScore, MaxAttempts: m.Quiz.AttemptLimit(), Questions: make([]QuizQuestion, len(m.Quiz.Questions))}
+	for i, question := range m.Quiz.Questions {
+		quiz.Questions[i] = QuizQuestion{
+			ID:      question.ID,
+			Type:    question.Type,
+			Prompt:  question.Prompt,
+			Options: question.Options,
+		}
+	}
+	m.Quiz = &quiz
+	return m
+}
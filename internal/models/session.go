@@ -0,0 +1,29 @@
+// /internal/models/session.go
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session representa un inicio de sesión de un usuario en un dispositivo.
+// Solo se guarda el hash del refresh token vigente y del inmediatamente anterior,
+// este último para detectar la reutilización de un token ya rotado.
+type Session struct {
+	ID                       primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID                 string             `json:"-" bson:"tenantId"`
+	UserID                   primitive.ObjectID `bson:"userId" json:"userId"`
+	RefreshTokenHash         string             `bson:"refreshTokenHash" json:"-"`
+	PreviousRefreshTokenHash string             `bson:"previousRefreshTokenHash,omitempty" json:"-"`
+	UserAgent                string             `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	CreatedAt                time.Time          `bson:"createdAt" json:"createdAt"`
+	LastUsedAt               time.Time          `bson:"lastUsedAt" json:"lastUsedAt"`
+	ExpiresAt                time.Time          `bson:"expiresAt" json:"expiresAt"`
+	RevokedAt                *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
+
+// Active indica si la sesión puede seguir usándose en el instante dado.
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
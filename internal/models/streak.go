@@ -0,0 +1,124 @@
+// /internal/models/streak.go
+package models
+
+import "time"
+
+const (
+	// DayLayout es el formato de los días del calendario de rachas.
+	DayLayout = "2006-01-02"
+	// StreakFreezeInterval es la cantidad de días seguidos con la que se gana
+	// una protección de racha.
+	StreakFreezeInterval = 7
+	// MaxStreakFreezes es el máximo de protecciones acumuladas.
+	MaxStreakFreezes = 2
+)
+
+// Streak es la racha diaria de un usuario. Los días se expresan en la zona
+// horaria del usuario con el formato DayLayout.
+//
+// Las protecciones (Freezes) cubren días sin actividad: si el usuario vuelve
+// tras faltar tantos días como protecciones tenga, la racha continúa y los
+// días cubiertos se registran en FrozenDays.
+type Streak struct {
+	Current       int      `bson:"current" json:"current"`
+	Longest       int      `bson:"longest" json:"longest"`
+	LastActiveDay string   `bson:"lastActiveDay,omitempty" json:"lastActiveDay,omitempty" example:"2025-03-14"`
+	Freezes       int      `bson:"freezes" json:"freezes"`
+	FrozenDays    []string `bson:"frozenDays,omitempty" json:"frozenDays,omitempty"`
+}
+
+// HeatmapDay es un día del calendario de actividad.
+type HeatmapDay struct {
+	Date   string `json:"date" example:"2025-03-14"`
+	Count  int    `json:"count" example:"2"`
+	Frozen bool   `json:"frozen,omitempty"`
+}
+
+// DayKey devuelve el día de t en la zona horaria loc con el formato DayLayout.
+func DayKey(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(DayLayout)
+}
+
+// daysBetween devuelve cuántos días hay del día from al día to, ambos con el
+// formato DayLayout. Devuelve false si alguno no es válido.
+func daysBetween(from, to string) (int, bool) {
+	a, err := time.Parse(DayLayout, from)
+	if err != nil {
+		return 0, false
+	}
+	b, err := time.Parse(DayLayout, to)
+	if err != nil {
+		return 0, false
+	}
+	return int(b.Sub(a).Hours() / 24), true
+}
+
+// Record registra actividad en el instante at y devuelve la racha
+// actualizada. Varias actividades el mismo día cuentan una sola vez, y una
+// actividad anterior al último día activo no cambia nada.
+func (s Streak) Record(at time.Time, loc *time.Location) Streak {
+	day := DayKey(at, loc)
+	gap, ok := daysBetween(s.LastActiveDay, day)
+	switch {
+	case !ok || s.Current == 0:
+		s.Current = 1
+	case gap <= 0:
+		return s
+	case gap == 1:
+		s.Current++
+	case gap-1 <= s.Freezes:
+		last, _ := time.Parse(DayLayout, s.LastActiveDay)
+		for i := 1; i < gap; i++ {
+			s.FrozenDays = append(s.FrozenDays, last.AddDate(0, 0, i).Format(DayLayout))
+		}
+		s.Freezes -= gap - 1
+		s.Current++
+	default:
+		s.Current = 1
+	}
+
+	s.LastActiveDay = day
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	if s.Current%StreakFreezeInterval == 0 && s.Freezes < MaxStreakFreezes {
+		s.Freezes++
+	}
+	return s
+}
+
+// CurrentAt devuelve la racha vigente en el instante now. La racha sigue viva
+// mientras los días sin actividad desde el último día activo, sin contar hoy,
+// puedan cubrirse con las protecciones disponibles.
+func (s Streak) CurrentAt(now time.Time, loc *time.Location) int {
+	gap, ok := daysBetween(s.LastActiveDay, DayKey(now, loc))
+	if !ok || gap-1 > s.Freezes {
+		return 0
+	}
+	return s.Current
+}
+
+// ActivityHeatmap arma el calendario de cada día entre from y to, ambos
+// incluidos, según el calendario de loc, con las actividades de counts, cuyas
+// claves son días DayKey en loc. Los días de frozen se marcan como cubiertos
+// por una protección.
+func ActivityHeatmap(counts map[string]int, frozen []string, from, to time.Time, loc *time.Location) []HeatmapDay {
+	if loc == nil {
+		loc = time.UTC
+	}
+	isFrozen := make(map[string]bool, len(frozen))
+	for _, day := range frozen {
+		isFrozen[day] = true
+	}
+
+	heatmap := []HeatmapDay{}
+	last := dayOf(to, loc)
+	for day := dayOf(from, loc); !day.After(last); day = day.AddDate(0, 0, 1) {
+		key := day.Format(DayLayout)
+		heatmap = append(heatmap, HeatmapDay{Date: key, Count: counts[key], Frozen: isFrozen[key]})
+	}
+	return heatmap
+}
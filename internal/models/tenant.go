@@ -0,0 +1,29 @@
+// /internal/models/tenant.go
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// DefaultTenantID es la organización de los usuarios y datos anteriores a las
+// organizaciones y de los clientes que no indican una. Sus administradores
+// mantienen el catálogo de misiones globales y dan de alta las demás.
+const DefaultTenantID = "explorax"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,39}$`)
+
+// Tenant es una organización, por lo general una escuela. Sus usuarios,
+// progresos, rankings y clases no se ven desde las demás organizaciones.
+type Tenant struct {
+	// ID es un identificador corto en minúsculas, como "colegio-central"
+	ID        string    `json:"id" bson:"_id" example:"colegio-central"`
+	Name      string    `json:"name" bson:"name" example:"Colegio Central"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// ValidTenantID indica si id puede identificar una organización: de 2 a 40
+// letras minúsculas, dígitos o guiones, sin empezar con guion.
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
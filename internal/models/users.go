@@ -6,10 +6,55 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Role identifica el nivel de acceso de un usuario dentro de la plataforma.
+type Role string
+
+const (
+	RoleStudent Role = "student"
+	RoleTeacher Role = "teacher"
+	RoleAdmin   Role = "admin"
+)
+
 type User struct {
 	ID           primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	TenantID     string             `json:"-" bson:"tenantId"`
 	Username     string             `json:"username" bson:"username"`
 	Email        string             `json:"email" bson:"email"`
 	PasswordHash string             `json:"-" bson:"passwordHash"`
-	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	Role         Role               `json:"role" bson:"role"`
+	XP           int                `json:"xp" bson:"xp"`
+	// Timezone es una zona horaria IANA; vacía equivale a UTC.
+	Timezone string `json:"timezone,omitempty" bson:"timezone,omitempty"`
+	Streak   Streak `json:"streak" bson:"streak"`
+	// BirthDate decide si el usuario es menor; ver PublicName.
+	BirthDate   *time.Time `json:"birthDate,omitempty" bson:"birthDate,omitempty"`
+	DisplayName string     `json:"displayName,omitempty" bson:"displayName,omitempty"`
+	Avatar      string     `json:"avatar,omitempty" bson:"avatar,omitempty"`
+	// HideFromLeaderboard excluye al usuario de los rankings públicos.
+	HideFromLeaderboard bool `json:"hideFromLeaderboard" bson:"hideFromLeaderboard,omitempty"`
+	// Locale es el idioma preferido; vacío usa el de Accept-Language.
+	Locale    string    `json:"locale,omitempty" bson:"locale,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Location devuelve la zona horaria del usuario. Si no tiene una o no es
+// válida se usa UTC.
+func (u User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// EffectiveRole devuelve el rol del usuario; los documentos creados antes de
+// existir el campo se consideran estudiantes.
+func (u User) EffectiveRole() Role {
+	if u.Role == "" {
+		return RoleStudent
+	}
+	return u.Role
 }
@@ -0,0 +1,247 @@
+// Package pagination implementa el contrato común de los endpoints que
+// devuelven listas: los parámetros limit, cursor, sort y fields, y el cursor
+// de la página siguiente, que también se publica en las cabeceras
+// X-Next-Cursor y Link.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"hash/fnv"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"explorax-backend/internal/apierror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Parámetros de la query string y cabecera de la respuesta.
+const (
+	LimitParam       = "limit"
+	CursorParam      = "cursor"
+	SortParam        = "sort"
+	FieldsParam      = "fields"
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+// Spec describe cómo se pagina un endpoint.
+type Spec struct {
+	DefaultLimit int
+	MaxLimit     int
+	// Sorts son los valores que acepta sort. Con Reversible, cada uno
+	// también se acepta con "-" delante para el orden descendente.
+	Sorts      []string
+	Reversible bool
+	// DefaultSort es el orden sin sort, con "-" delante si es descendente;
+	// por defecto, el primero de Sorts
+	DefaultSort string
+	// Fields son los campos que se pueden pedir en fields; ver FieldsOf
+	Fields []string
+	// Scope distingue resultados que cambian sin que cambie la URL, como el
+	// ranking de la semana en curso: el cursor solo vale con el mismo Scope
+	Scope string
+}
+
+// Page es la página pedida, ya validada.
+type Page struct {
+	Offset     int
+	Limit      int
+	Sort       string
+	Descending bool
+	// Fields son los campos a devolver de cada elemento; vacío, todos
+	Fields []string
+	scope  string
+	key    json.RawMessage
+}
+
+// cursor indica dónde empieza la página siguiente: la posición, un resumen de
+// la consulta en que se emitió y, con NextAfter, la clave de orden del último
+// elemento entregado.
+type cursor struct {
+	Scope  string          `json:"s"`
+	Offset int             `json:"o"`
+	Key    json.RawMessage `json:"k,omitempty"`
+}
+
+// Parse lee los parámetros de paginación de la petición. Un cursor vale
+// únicamente para la misma ruta con los mismos parámetros, salvo limit y
+// fields, que pueden cambiar entre páginas.
+func Parse(c *gin.Context, spec Spec) (Page, *apierror.Error) {
+	page := Page{Limit: spec.DefaultLimit, scope: scope(c, spec.Scope)}
+
+	if v := c.Query(LimitParam); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > spec.MaxLimit {
+			return page, apierror.InvalidQuery(apierror.Field(LimitParam, apierror.OutOfRange).
+				With("min", 1).With("max", spec.MaxLimit))
+		}
+		page.Limit = n
+	}
+
+	sort := spec.DefaultSort
+	if sort == "" && len(spec.Sorts) > 0 {
+		sort = spec.Sorts[0]
+	}
+	if v := c.Query(SortParam); v != "" {
+		sort = v
+	}
+	if sort != "" {
+		if spec.Reversible && strings.HasPrefix(sort, "-") {
+			page.Descending = true
+			sort = sort[1:]
+		}
+		if !slices.Contains(spec.Sorts, sort) {
+			return page, apierror.InvalidQuery(apierror.Field(SortParam, apierror.UnknownValue).
+				With("allowed", strings.Join(spec.Sorts, ", ")))
+		}
+		page.Sort = sort
+	}
+
+	if v := c.Query(FieldsParam); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			if !slices.Contains(spec.Fields, field) {
+				return page, apierror.InvalidQuery(apierror.Field(FieldsParam, apierror.UnknownValue).
+					With("allowed", strings.Join(spec.Fields, ", ")))
+			}
+			if !slices.Contains(page.Fields, field) {
+				page.Fields = append(page.Fields, field)
+			}
+		}
+	}
+
+	if v := c.Query(CursorParam); v != "" {
+		var cur cursor
+		raw, err := base64.RawURLEncoding.DecodeString(v)
+		if err == nil {
+			err = json.Unmarshal(raw, &cur)
+		}
+		if err != nil || cur.Offset < 0 || cur.Scope != page.scope {
+			return page, apierror.New(apierror.InvalidCursor)
+		}
+		page.Offset = cur.Offset
+		page.key = cur.Key
+	}
+	return page, nil
+}
+
+// After decodifica en v la clave guardada con NextAfter en el cursor, para
+// leer la página a partir de ella en lugar de saltar Offset elementos.
+// Devuelve false en la primera página; un cursor sin clave o con una clave
+// que no corresponde a v no es válido.
+func (p Page) After(v interface{}) (bool, *apierror.Error) {
+	if p.Offset == 0 && p.key == nil {
+		return false, nil
+	}
+	if p.key == nil || json.Unmarshal(p.key, v) != nil {
+		return false, apierror.New(apierror.InvalidCursor)
+	}
+	return true, nil
+}
+
+// Fetch es cuántos elementos hay que pedir a la base de datos: uno más que
+// Limit, para saber si hay otra página.
+func (p Page) Fetch() int {
+	return p.Limit + 1
+}
+
+// Next recorta items, obtenidos con Fetch, al tamaño de la página. Si hay
+// otra página, publica su cursor en las cabeceras X-Next-Cursor y Link (con
+// rel="next" y la URL de la petición con ese cursor) y lo devuelve.
+func Next[T any](c *gin.Context, p Page, items []T) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+	items = items[:p.Limit]
+	return items, publish(c, cursor{Scope: p.scope, Offset: p.Offset + len(items)})
+}
+
+// NextAfter es como Next, pero el cursor guarda además key(último elemento),
+// que la página siguiente recupera con After. Offset sigue contando los
+// elementos ya entregados, por ejemplo para numerar posiciones.
+func NextAfter[T any](c *gin.Context, p Page, items []T, key func(T) interface{}) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+	items = items[:p.Limit]
+	raw, _ := json.Marshal(key(items[len(items)-1]))
+	return items, publish(c, cursor{Scope: p.scope, Offset: p.Offset + len(items), Key: raw})
+}
+
+// publish codifica cur y lo publica en las cabeceras X-Next-Cursor y Link.
+func publish(c *gin.Context, cur cursor) string {
+	raw, _ := json.Marshal(cur)
+	next := base64.RawURLEncoding.EncodeToString(raw)
+
+	nextURL := *c.Request.URL
+	q := nextURL.Query()
+	q.Set(CursorParam, next)
+	nextURL.RawQuery = q.Encode()
+	c.Header(NextCursorHeader, next)
+	c.Header("Link", "<"+nextURL.RequestURI()+">; rel=\"next\"")
+	return next
+}
+
+// Select devuelve items con solo los campos de fields, que son las claves de
+// primer nivel del JSON de cada elemento.
+func Select[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	selected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &all); err != nil {
+			return nil, err
+		}
+		keep := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				keep[field] = value
+			}
+		}
+		selected = append(selected, keep)
+	}
+	return selected, nil
+}
+
+// FieldsOf devuelve los nombres JSON de los campos de v, un struct, incluidos
+// los de los structs embebidos, para usarlos como Spec.Fields.
+func FieldsOf(v interface{}) []string {
+	return jsonFields(reflect.TypeOf(v))
+}
+
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case name == "-" || !f.IsExported():
+			continue
+		case name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct:
+			fields = append(fields, jsonFields(f.Type)...)
+		case name == "":
+			fields = append(fields, f.Name)
+		default:
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// scope resume la ruta y los parámetros de la petición que definen los
+// resultados, para rechazar cursores emitidos en otra consulta.
+func scope(c *gin.Context, extra string) string {
+	q := c.Request.URL.Query()
+	q.Del(CursorParam)
+	q.Del(LimitParam)
+	q.Del(FieldsParam)
+	h := fnv.New64a()
+	h.Write([]byte(c.Request.URL.Path + "?" + q.Encode() + "#" + extra))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
@@ -0,0 +1,59 @@
+// Package textnorm normaliza textos escritos por los usuarios para poder
+// compararlos y buscarlos sin depender de mayúsculas, acentos ni espacios.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold normaliza un texto para compararlo sin distinguir mayúsculas, acentos
+// ni espacios repetidos: "  Órbita  Baja " y "orbita baja" son iguales.
+func Fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(strings.Join(strings.Fields(folded), " "))
+}
+
+// stopWords son palabras vacías del español que no sirven para buscar.
+var stopWords = map[string]bool{
+	"a": true, "al": true, "con": true, "de": true, "del": true, "el": true,
+	"en": true, "la": true, "las": true, "lo": true, "los": true, "o": true,
+	"para": true, "por": true, "que": true, "se": true, "su": true, "un": true,
+	"una": true, "y": true,
+}
+
+// Terms divide un texto en palabras normalizadas con Fold, sin signos de
+// puntuación ni palabras vacías: "¿Qué hay en la Vía Láctea?" devuelve
+// "hay", "via" y "lactea".
+func Terms(s string) []string {
+	words := strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, w := range words {
+		if !stopWords[w] {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// Stem reduce una palabra normalizada a una raíz aproximada quitando el
+// plural, para que "planeta" y "planetas" o "mision" y "misiones" coincidan.
+func Stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "es"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
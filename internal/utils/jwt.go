@@ -2,22 +2,127 @@
 package utils
 
 import (
-	"os"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
 
+	"explorax-backend/internal/models"
+
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateJWT(userID string) (string, error) {
+const (
+	// AccessTokenTTL es la vigencia del token de acceso enviado en cada petición.
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL es la vigencia de una sesión sin actividad de refresco.
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidToken se devuelve cuando un token no es válido, está vencido o le
+// falta alguno de los claims obligatorios.
+var ErrInvalidToken = errors.New("token inválido")
+
+// AccessClaims son los datos que viajan dentro de un token de acceso.
+type AccessClaims struct {
+	UserID    string
+	Role      string
+	SessionID string
+	// TenantID es la organización del usuario; ver models.Tenant
+	TenantID string
+}
+
+// TokenSigner emite y valida tokens de acceso.
+type TokenSigner interface {
+	SignAccessToken(claims AccessClaims) (string, error)
+	ParseAccessToken(token string) (AccessClaims, error)
+}
+
+// HMACSigner implementa TokenSigner con JWT firmados con HS256.
+type HMACSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewHMACSigner crea un TokenSigner con la clave secreta dada. El reloj se usa
+// para calcular el vencimiento de los tokens emitidos.
+func NewHMACSigner(secret string, now func() time.Time) *HMACSigner {
+	if now == nil {
+		now = time.Now
+	}
+	return &HMACSigner{secret: []byte(secret), now: now}
+}
+
+// SignAccessToken genera un token de acceso de corta duración ligado a una sesión.
+func (s *HMACSigner) SignAccessToken(claims AccessClaims) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["user_id"] = userID
-	claims["exp"] = time.Now().Add(72 * time.Hour).Unix() // Expira en 72 horas
+	mapClaims := token.Claims.(jwt.MapClaims)
+	mapClaims["user_id"] = claims.UserID
+	mapClaims["role"] = claims.Role
+	mapClaims["sid"] = claims.SessionID
+	mapClaims["tenant"] = claims.TenantID
+	mapClaims["exp"] = s.now().Add(AccessTokenTTL).Unix()
 
-	secret := os.Getenv("JWT_SECRET")
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(s.secret)
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
+
+// ParseAccessToken valida la firma y el vencimiento de un token y devuelve sus claims.
+// Los tokens sin rol, emitidos antes de existir los roles, se tratan como de
+// estudiante, y los que no indican organización, como de models.DefaultTenantID.
+func (s *HMACSigner) ParseAccessToken(tokenString string) (AccessClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de firma inesperado: %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return AccessClaims{}, ErrInvalidToken
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return AccessClaims{}, ErrInvalidToken
+	}
+
+	userID, ok := mapClaims["user_id"].(string)
+	if !ok {
+		return AccessClaims{}, fmt.Errorf("%w: falta user_id", ErrInvalidToken)
+	}
+	sessionID, ok := mapClaims["sid"].(string)
+	if !ok {
+		return AccessClaims{}, fmt.Errorf("%w: falta sid", ErrInvalidToken)
+	}
+	role, _ := mapClaims["role"].(string)
+	if role == "" {
+		role = string(models.RoleStudent)
+	}
+	tenantID, _ := mapClaims["tenant"].(string)
+	if tenantID == "" {
+		tenantID = models.DefaultTenantID
+	}
+
+	return AccessClaims{UserID: userID, Role: role, SessionID: sessionID, TenantID: tenantID}, nil
+}
+
+// GenerateRefreshToken genera un refresh token opaco y aleatorio.
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken devuelve el hash con el que se almacena un refresh token.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}